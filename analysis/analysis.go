@@ -0,0 +1,269 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package analysis characterizes what a configured v1std pipeline
+responds to, by driving it with parametric stimuli from the stimulus
+package and recording the responses of selected units of its output.
+It measures orientation tuning, spatial-frequency tuning and
+contrast-response curves with drifting gratings, and receptive field
+maps by reverse correlation with white noise, as lab/table tables.
+*/
+package analysis
+
+//go:generate core generate -add-types
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/lab/table"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/stimulus"
+	"github.com/emer/v1vision/v1std"
+)
+
+// Unit is a unit in the output of a pipeline to record from.
+type Unit struct {
+	// Name is the name of the unit, used for table columns.
+	Name string
+
+	// Index is the index of the unit in the output for one data-parallel
+	// item, i.e., excluding the outer NData dimension
+	// (e.g., Y, X, Row, Angle for [v1std.V1cGrey] Output).
+	Index []int
+}
+
+// NewUnit returns a new [Unit] with given name and index.
+// If name is empty, it is set from the index, e.g., "U_4_4_3_0".
+func NewUnit(name string, index ...int) Unit {
+	if name == "" {
+		name = "U"
+		for _, i := range index {
+			name += fmt.Sprintf("_%d", i)
+		}
+	}
+	return Unit{Name: name, Index: index}
+}
+
+// Analyzer drives a v1std pipeline with stimuli,
+// recording the responses of the selected Units.
+// The pipeline must be configured with NData items and the
+// stimulus image Size, which are used to present different
+// stimulus conditions in parallel.
+type Analyzer struct {
+	// Pipeline is the configured pipeline to drive, e.g., [v1std.V1cGrey].
+	// If it is a [v1std.ItemResetter], each item is reset before
+	// each stimulus is presented.
+	Pipeline v1std.ImageRunner `display:"-"`
+
+	// Output returns the output of the pipeline after it is run,
+	// with an outer NData dimension, e.g., the Output field of the
+	// pipeline, or a Values4D SubSpace of its V1Vision.
+	Output func() tensor.Tensor `display:"-"`
+
+	// Image is the image handler for the pipeline.
+	// Its Size is the size of the stimulus images.
+	Image v1std.Image
+
+	// NData is the number of data-parallel items that the pipeline
+	// is configured for.
+	NData int
+
+	// Units are the units to record the responses of.
+	Units []Unit
+
+	// Frames is the number of frames to present each stimulus for
+	// (e.g., drifting gratings), over which the responses are averaged.
+	Frames int `default:"1"`
+
+	// Settle is the number of initial frames of each stimulus that are
+	// excluded from the averaged responses, e.g., for temporal integration.
+	// At least the last frame is always recorded: Settle is limited
+	// to Frames-1.
+	Settle int
+}
+
+// NewAnalyzer returns a new [Analyzer] for given pipeline and output
+// function, configured with given number of data-parallel items and
+// image size, recording from given units.
+func NewAnalyzer(pipe v1std.ImageRunner, output func() tensor.Tensor, ndata int, size image.Point, units ...Unit) *Analyzer {
+	an := &Analyzer{Pipeline: pipe, Output: output, NData: ndata, Units: units}
+	an.Defaults()
+	an.Image.Size = size
+	return an
+}
+
+func (an *Analyzer) Defaults() {
+	an.Image.Defaults()
+	an.Frames = 1
+	an.Settle = 0
+}
+
+// Responses presents each of the given stimuli, in batches of NData,
+// and returns the average responses of the Units as a
+// [stimulus][unit] tensor.
+func (an *Analyzer) Responses(stims ...stimulus.Stimulus) *tensor.Float32 {
+	nu := len(an.Units)
+	resp := tensor.NewFloat32(len(stims), nu)
+	nfr := max(an.Frames, 1)
+	settle := max(min(an.Settle, nfr-1), 0)
+	nf := nfr - settle
+	rs, _ := an.Pipeline.(v1std.ItemResetter)
+	imgs := make([]image.Image, an.NData)
+	idx := make([]int, 0, 8)
+	for st := 0; st < len(stims); st += an.NData {
+		if rs != nil {
+			for ni := range an.NData {
+				rs.ResetItem(ni)
+			}
+		}
+		for fi := range nfr {
+			for ni := range an.NData {
+				si := min(st+ni, len(stims)-1) // repeat last to fill batch
+				imgs[ni] = stimulus.Image(stims[si], an.Image.Size, float32(fi))
+			}
+			an.Pipeline.RunImages(&an.Image, imgs...)
+			if fi < settle {
+				continue
+			}
+			out := an.Output()
+			for ni := range min(an.NData, len(stims)-st) {
+				for ui, u := range an.Units {
+					idx = append(append(idx[:0], ni), u.Index...)
+					resp.SetAdd(float32(out.Float(idx...))/float32(nf), st+ni, ui)
+				}
+			}
+		}
+	}
+	return resp
+}
+
+// Tuning measures a tuning curve over given parameter values,
+// using the given function to return the stimulus for each value.
+// Returns a table with a column named param for the values, and a
+// column for the average response of each unit, named by the Unit Name.
+func (an *Analyzer) Tuning(param string, values []float32, stim func(v float32) stimulus.Stimulus) *table.Table {
+	stims := make([]stimulus.Stimulus, len(values))
+	for i, v := range values {
+		stims[i] = stim(v)
+	}
+	resp := an.Responses(stims...)
+	dt := table.New(param)
+	pc := dt.AddFloat32Column(param)
+	ucs := make([]*tensor.Float32, len(an.Units))
+	for ui, u := range an.Units {
+		ucs[ui] = dt.AddFloat32Column(u.Name)
+	}
+	dt.SetNumRows(len(values))
+	for i, v := range values {
+		pc.Set1D(v, i)
+		for ui := range an.Units {
+			ucs[ui].Set1D(resp.Value(i, ui), i)
+		}
+	}
+	return dt
+}
+
+// OrientationTuning measures orientation tuning curves for given
+// grating orientation angles in degrees, with the other grating
+// parameters as given. Returns a table with an Angle column
+// and a column for each unit (see [Analyzer.Tuning]).
+func (an *Analyzer) OrientationTuning(gr *stimulus.Grating, angles []float32) *table.Table {
+	return an.Tuning("Angle", angles, func(v float32) stimulus.Stimulus {
+		g := *gr
+		g.Angle = v
+		return &g
+	})
+}
+
+// SFTuning measures spatial-frequency tuning curves for given
+// grating frequencies in cycles per pixel, with the other grating
+// parameters as given. Returns a table with a Freq column
+// and a column for each unit (see [Analyzer.Tuning]).
+func (an *Analyzer) SFTuning(gr *stimulus.Grating, freqs []float32) *table.Table {
+	return an.Tuning("Freq", freqs, func(v float32) stimulus.Stimulus {
+		g := *gr
+		g.Freq = v
+		return &g
+	})
+}
+
+// ContrastResponse measures contrast-response curves for given
+// grating contrasts, with the other grating parameters as given.
+// Returns a table with a Contrast column and a column for each unit
+// (see [Analyzer.Tuning]).
+func (an *Analyzer) ContrastResponse(gr *stimulus.Grating, contrasts []float32) *table.Table {
+	return an.Tuning("Contrast", contrasts, func(v float32) stimulus.Stimulus {
+		g := *gr
+		g.Contrast = v
+		return &g
+	})
+}
+
+// ReverseCorrelation measures receptive field maps for each unit by
+// reverse correlation (the response-weighted average stimulus) over n
+// frames of the given noise stimulus, each presented for Frames frames.
+// Returns a table with a row for each unit, with a Unit name column
+// and an RF column with the [Y][X] map, with Y = 0 at the bottom,
+// where positive values indicate that increased luminance at that
+// location increases the response.
+func (an *Analyzer) ReverseCorrelation(ns *stimulus.Noise, n int) *table.Table {
+	stims := make([]stimulus.Stimulus, n)
+	for i := range n {
+		stims[i] = &frozen{Stimulus: ns, Time: float32(i)}
+	}
+	resp := an.Responses(stims...)
+	nu := len(an.Units)
+	sz := an.Image.Size
+	dt := table.New("ReverseCorrelation")
+	names := dt.AddStringColumn("Unit")
+	rfs := dt.AddFloat32Column("RF", sz.Y, sz.X)
+	dt.SetNumRows(nu)
+	means := make([]float32, nu)
+	for ui, u := range an.Units {
+		names.SetString1D(u.Name, ui)
+		for i := range n {
+			means[ui] += resp.Value(i, ui) / float32(n)
+		}
+	}
+	for i := range n {
+		st := stimulus.Tensor(ns, sz, float32(i))
+		for ui := range an.Units {
+			dr := (resp.Value(i, ui) - means[ui]) / float32(n)
+			rf := rfs.SubSpace(ui).(*tensor.Float32)
+			for pi, v := range st.Values {
+				rf.Values[pi] += dr * (v - ns.Mean)
+			}
+		}
+	}
+	return dt
+}
+
+// Preferred returns the parameter value with the maximum response for
+// given unit name in a tuning table from [Analyzer.Tuning].
+func Preferred(dt *table.Table, param, unit string) float32 {
+	pc := dt.Column(param)
+	uc := dt.Column(unit)
+	best := 0
+	for i := range dt.NumRows() {
+		if uc.Float1D(i) > uc.Float1D(best) {
+			best = i
+		}
+	}
+	return float32(pc.Float1D(best))
+}
+
+// frozen is a stimulus frozen at a given time, for presenting
+// a single frame of a dynamic stimulus over multiple frames.
+type frozen struct {
+	stimulus.Stimulus
+
+	// Time is the time at which the stimulus is rendered.
+	Time float32
+}
+
+func (fz *frozen) Render(tsr *tensor.Float32, t float32) {
+	fz.Stimulus.Render(tsr, fz.Time)
+}
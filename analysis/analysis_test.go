@@ -0,0 +1,129 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"image"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/render"
+	"github.com/emer/v1vision/stimulus"
+	"github.com/emer/v1vision/v1std"
+	"github.com/stretchr/testify/assert"
+)
+
+// v1cAnalyzer returns an Analyzer for a V1cGrey pipeline, recording
+// from the V1s On polarity units for each angle at the center.
+func v1cAnalyzer(vi *v1std.V1cGrey) *Analyzer {
+	vi.Defaults()
+	vi.GPU = false
+	sz := image.Point{64, 64}
+	vi.Config(4, sz)
+	cy, cx := int(vi.V1cGeom.Out.Y)/2, int(vi.V1cGeom.Out.X)/2
+	var units []Unit
+	for ang := range vi.V1sGabor.NAngles {
+		units = append(units, NewUnit("", cy, cx, 4, ang))
+	}
+	an := NewAnalyzer(vi, func() tensor.Tensor { return vi.Output }, 4, sz, units...)
+	an.Frames = 6
+	return an
+}
+
+func driftingGrating() *stimulus.Grating {
+	gr := &stimulus.Grating{}
+	gr.Defaults()
+	gr.Freq = 1.0 / 6
+	gr.Speed = 1
+	return gr
+}
+
+func TestOrientationTuning(t *testing.T) {
+	var vi v1std.V1cGrey
+	an := v1cAnalyzer(&vi)
+	assert.Equal(t, "U_4_4_4_0", an.Units[0].Name)
+	angles := []float32{0, 22.5, 45, 67.5, 90, 112.5, 135, 157.5}
+	dt := an.OrientationTuning(driftingGrating(), angles)
+	assert.Equal(t, len(angles), dt.NumRows())
+	assert.Equal(t, 1+len(an.Units), dt.NumColumns())
+	assert.Equal(t, float32(0), Preferred(dt, "Angle", an.Units[0].Name))
+	assert.Contains(t, []float32{22.5, 45, 67.5}, Preferred(dt, "Angle", an.Units[1].Name))
+	assert.Equal(t, float32(90), Preferred(dt, "Angle", an.Units[2].Name))
+	assert.Contains(t, []float32{112.5, 135, 157.5}, Preferred(dt, "Angle", an.Units[3].Name))
+	// orthogonal response is much weaker
+	hc := dt.Column(an.Units[0].Name)
+	assert.Less(t, hc.Float1D(4), 0.1*hc.Float1D(0))
+}
+
+func TestSFTuning(t *testing.T) {
+	var vi v1std.V1cGrey
+	an := v1cAnalyzer(&vi)
+	freqs := []float32{0.02, 0.05, 0.1, 0.15, 0.2, 0.3, 0.4}
+	dt := an.SFTuning(driftingGrating(), freqs)
+	un := an.Units[0].Name
+	pref := Preferred(dt, "Freq", un)
+	assert.Contains(t, []float32{0.1, 0.15}, pref)
+	uc := dt.Column(un)
+	mx := uc.Float1D(3)
+	assert.Less(t, uc.Float1D(0), 0.5*mx)
+	assert.Less(t, uc.Float1D(len(freqs)-1), 0.5*mx)
+}
+
+func TestContrastResponse(t *testing.T) {
+	var vi v1std.V1cGrey
+	an := v1cAnalyzer(&vi)
+	contrasts := []float32{0, 0.1, 0.2, 0.4, 0.8, 1}
+	dt := an.ContrastResponse(driftingGrating(), contrasts)
+	uc := dt.Column(an.Units[0].Name)
+	assert.Less(t, uc.Float1D(0), 1.0e-3)
+	for i := 1; i < len(contrasts); i++ {
+		assert.GreaterOrEqual(t, uc.Float1D(i), uc.Float1D(i-1))
+	}
+	assert.Greater(t, uc.Float1D(len(contrasts)-1), 0.5)
+}
+
+func TestReverseCorrelation(t *testing.T) {
+	var vi v1std.DoGGrey
+	vi.Defaults()
+	vi.GPU = false
+	sz := image.Point{32, 32}
+	vi.Config(8, sz)
+	cy, cx := int(vi.Geom.Out.Y)/2, int(vi.Geom.Out.X)/2
+	an := NewAnalyzer(&vi, func() tensor.Tensor { return vi.Output }, 8, sz, NewUnit("On", cy, cx, 0, 0), NewUnit("Off", cy, cx, 1, 0))
+	var ns stimulus.Noise
+	ns.Defaults()
+	ns.Seed = 1
+	dt := an.ReverseCorrelation(&ns, 800)
+	assert.Equal(t, 2, dt.NumRows())
+	assert.Equal(t, "Off", dt.Column("Unit").String1D(1))
+	rfs := dt.Column("RF").AsValues().(*tensor.Float32)
+	assert.Equal(t, []int{2, sz.Y, sz.X}, rfs.ShapeSizes())
+
+	ctr := render.OverlayPos(math32.Vec2(float32(cx), float32(cy)), int(vi.Geom.Border.X), &vi.Geom)
+	for ui, sign := range []float32{1, -1} {
+		rf := rfs.SubSpace(ui).(*tensor.Float32)
+		mi := 0
+		for i, v := range rf.Values {
+			if sign*v > sign*rf.Values[mi] {
+				mi = i
+			}
+		}
+		pk := math32.Vec2(float32(mi%sz.X), float32(mi/sz.X))
+		assert.Less(t, pk.Sub(ctr).Length(), float32(2))
+		assert.Greater(t, sign*rf.Values[mi], float32(0.005))
+	}
+}
+
+func TestSettle(t *testing.T) {
+	var vi v1std.V1cGrey
+	an := v1cAnalyzer(&vi)
+	gr := driftingGrating()
+	an.Settle = 5 // only the last frame
+	last := an.Responses(gr)
+	assert.Greater(t, last.Value(0, 0), float32(0))
+	an.Settle = 10 // limited to Frames-1
+	assert.Equal(t, last.Values, an.Responses(gr).Values)
+}
@@ -0,0 +1,13 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package analysis
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/analysis.Unit", IDName: "unit", Doc: "Unit is a unit in the output of a pipeline to record from.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the unit, used for table columns."}, {Name: "Index", Doc: "Index is the index of the unit in the output for one data-parallel\nitem, i.e., excluding the outer NData dimension\n(e.g., Y, X, Row, Angle for [v1std.V1cGrey] Output)."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/analysis.Analyzer", IDName: "analyzer", Doc: "Analyzer drives a v1std pipeline with stimuli,\nrecording the responses of the selected Units.\nThe pipeline must be configured with NData items and the\nstimulus image Size, which are used to present different\nstimulus conditions in parallel.", Fields: []types.Field{{Name: "Pipeline", Doc: "Pipeline is the configured pipeline to drive, e.g., [v1std.V1cGrey].\nIf it is a [v1std.ItemResetter], each item is reset before\neach stimulus is presented."}, {Name: "Output", Doc: "Output returns the output of the pipeline after it is run,\nwith an outer NData dimension, e.g., the Output field of the\npipeline, or a Values4D SubSpace of its V1Vision."}, {Name: "Image", Doc: "Image is the image handler for the pipeline.\nIts Size is the size of the stimulus images."}, {Name: "NData", Doc: "NData is the number of data-parallel items that the pipeline\nis configured for."}, {Name: "Units", Doc: "Units are the units to record the responses of."}, {Name: "Frames", Doc: "Frames is the number of frames to present each stimulus for\n(e.g., drifting gratings), over which the responses are averaged."}, {Name: "Settle", Doc: "Settle is the number of initial frames of each stimulus that are\nexcluded from the averaged responses, e.g., for temporal integration.\nAt least the last frame is always recorded: Settle is limited\nto Frames-1."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/analysis.frozen", IDName: "frozen", Doc: "frozen is a stimulus frozen at a given time, for presenting\na single frame of a dynamic stimulus over multiple frames.", Embeds: []types.Field{{Name: "Stimulus"}}, Fields: []types.Field{{Name: "Time", Doc: "Time is the time at which the stimulus is rendered."}}})
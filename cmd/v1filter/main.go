@@ -0,0 +1,438 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command v1filter runs a standard v1std filtering pipeline on a dataset
+// of images, on the CPU in batches of NData images, and writes the
+// outputs to .npy, .npz or TSV files, with a manifest.json that maps
+// the images to the rows (outer dimension) of the outputs.
+// The outputs are written as each batch is processed, so memory use
+// does not grow with the number of images.
+//
+// Usage:
+//
+//	v1filter [flags] <image files or directories>...
+//
+// For example:
+//
+//	v1filter -preset V1cGrey -set V1sKWTA.On=false -ndata 16 -out out images/
+//
+// Parameter overrides set fields of the pipeline struct (e.g.,
+// [v1std.V1cGrey]) by path, after the preset defaults and before Config.
+// The V1cMulti preset can also be configured from a TOML or JSON
+// [v1std.MultiConfig] file with -config, which is applied before the
+// overrides, and the effective config is saved as config.toml in the
+// output directory:
+//
+//	v1filter -preset V1cMulti -config multi.toml -out out images/
+//
+// The V1cMulti preset can select one of the [v1std.MultiPresets] by name
+// (LowMed16DegZoom1 by default), e.g., -preset V1cMulti:LowMed32DegZoom1.
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/base/iox/imagex"
+	"cogentcore.org/core/base/iox/jsonx"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/npy"
+	"github.com/emer/v1vision/v1std"
+)
+
+// Config has the command configuration.
+type Config struct {
+	// Preset is the pipeline preset.
+	Preset string
+
+	// Inputs are image files or directories of image files.
+	Inputs []string
+
+	// List is a file with a list of image files, one per line.
+	List string
+
+	// Out is the output directory.
+	Out string
+
+	// Format is the output format: npy, npz or tsv.
+	Format string
+
+	// NData is the number of images to process in parallel in each batch.
+	NData int
+
+	// Size is the image size, or the preset default if zero.
+	Size image.Point
+
+	// Fit is how images of a different size are fit into Size.
+	Fit v1std.FitModes
+
+	// Params are the parameter overrides, as Field.Path=value.
+	Params []string
+
+	// PipelineConfig is a TOML or JSON pipeline config file,
+	// for the V1cMulti preset (see [v1std.MultiConfig]).
+	PipelineConfig string
+}
+
+// Manifest records the inputs and outputs of a run.
+type Manifest struct {
+	// Preset is the pipeline preset.
+	Preset string
+
+	// Params are the parameter overrides.
+	Params []string
+
+	// Config is the effective pipeline config file, within the
+	// output directory, for presets that support it.
+	Config string `json:",omitempty"`
+
+	// ImageSize is the image size.
+	ImageSize image.Point
+
+	// Format is the output format.
+	Format string
+
+	// Images are the image files, in the order of the output rows.
+	Images []string
+
+	// Outputs are the output files.
+	Outputs []ManifestOutput
+}
+
+// ManifestOutput records an output file.
+type ManifestOutput struct {
+	// Name is the name of the output (array name in a .npz file).
+	Name string
+
+	// File is the output file name, within the output directory.
+	File string
+
+	// Shape is the shape of the output, where the outer dimension
+	// is the number of images.
+	Shape []int
+
+	// Rows are the names of the inner rows of the output,
+	// e.g., LenSum (see [v1std.Layout]).
+	Rows []string
+
+	// Cols are the names of the inner columns of the output,
+	// e.g., gabor angles.
+	Cols []string
+}
+
+// stringsFlag is a repeatable string flag.
+type stringsFlag []string
+
+func (sf *stringsFlag) String() string { return strings.Join(*sf, ",") }
+
+func (sf *stringsFlag) Set(s string) error {
+	*sf = append(*sf, s)
+	return nil
+}
+
+func main() {
+	cfg := &Config{}
+	var size, fit string
+	var sets stringsFlag
+	flag.StringVar(&cfg.Preset, "preset", "V1cGrey", "pipeline preset: "+strings.Join(presets, ", ")+
+		"\nV1cMulti can be followed by :name of a V1cMulti preset: "+strings.Join(v1std.MultiPresetNames(), ", "))
+	flag.StringVar(&cfg.List, "list", "", "file with a list of image files, one per line")
+	flag.StringVar(&cfg.Out, "out", "v1filter_out", "output directory")
+	flag.StringVar(&cfg.Format, "format", "npy", "output format: npy, npz or tsv")
+	flag.IntVar(&cfg.NData, "ndata", 8, "number of images to process in parallel in each batch")
+	flag.StringVar(&size, "size", "", "image size as WxH (default is the preset size)")
+	flag.StringVar(&fit, "fit", "Stretch", "how images are fit into the size: Stretch, Letterbox or Crop")
+	flag.StringVar(&cfg.PipelineConfig, "config", "", "TOML or JSON pipeline config file, for the V1cMulti preset")
+	flag.Var(&sets, "set", "parameter override as Field.Path=value (repeatable)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: v1filter [flags] <image files or directories>...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	cfg.Inputs = flag.Args()
+	cfg.Params = sets
+	err := cfg.Fit.SetString(fit)
+	if err == nil && size != "" {
+		_, err = fmt.Sscanf(size, "%dx%d", &cfg.Size.X, &cfg.Size.Y)
+	}
+	if err == nil {
+		err = Run(cfg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "v1filter:", err)
+		os.Exit(1)
+	}
+}
+
+// Run runs the command with given config.
+func Run(cfg *Config) error {
+	switch cfg.Format {
+	case "npy", "npz", "tsv":
+	default:
+		return fmt.Errorf("unknown format: %q, must be npy, npz or tsv", cfg.Format)
+	}
+	files, err := imageFiles(cfg.Inputs, cfg.List)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no image files given")
+	}
+	pl, err := newPipeline(cfg.Preset)
+	if err != nil {
+		return err
+	}
+	if cfg.PipelineConfig != "" {
+		if pl.openConfig == nil {
+			return fmt.Errorf("preset %s does not support a pipeline config file", cfg.Preset)
+		}
+		if err := pl.openConfig(cfg.PipelineConfig); err != nil {
+			return err
+		}
+	}
+	if err := pl.setParams(cfg.Params); err != nil {
+		return err
+	}
+	size := cfg.Size
+	if size == (image.Point{}) {
+		size = pl.size
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("image size must be > 0: %v", size)
+	}
+	if pl.validate != nil {
+		if err := pl.validate(size); err != nil {
+			return err
+		}
+	}
+	ndata := max(min(cfg.NData, len(files)), 1)
+	pl.config(ndata, size)
+	var im v1std.Image
+	im.Defaults()
+	im.Size = size
+	im.Fit = cfg.Fit
+
+	if err := os.MkdirAll(cfg.Out, 0777); err != nil {
+		return err
+	}
+	mf := &Manifest{Preset: cfg.Preset, Params: cfg.Params, ImageSize: size, Format: cfg.Format, Images: files}
+	var ows []*outputWriter
+	defer func() {
+		for _, ow := range ows {
+			ow.file.Close() // in case of error; already closed otherwise
+		}
+	}()
+	imgs := make([]image.Image, ndata)
+	for st := 0; st < len(files); st += ndata {
+		for ni := range ndata {
+			fn := files[min(st+ni, len(files)-1)] // repeat last to fill batch
+			img, _, err := imagex.Open(fn)
+			if err != nil {
+				return err
+			}
+			imgs[ni] = img
+		}
+		pl.run(&im, imgs)
+		bouts := pl.outputs()
+		if ows == nil {
+			for _, bo := range bouts {
+				ow, err := newOutputWriter(cfg, bo, len(files))
+				if err != nil {
+					return err
+				}
+				ows = append(ows, ow)
+			}
+		}
+		for i, bo := range bouts {
+			if err := ows[i].write(bo.tsr, min(ndata, len(files)-st)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, ow := range ows {
+		if err := ow.close(); err != nil {
+			return err
+		}
+		mf.Outputs = append(mf.Outputs, ow.mo)
+	}
+	if cfg.Format == "npz" {
+		if err := writeNPZ(cfg.Out, ows); err != nil {
+			return err
+		}
+	}
+	if pl.saveConfig != nil {
+		mf.Config = "config.toml"
+		if err := pl.saveConfig(filepath.Join(cfg.Out, mf.Config)); err != nil {
+			return err
+		}
+	}
+	return jsonx.SaveIndent(mf, filepath.Join(cfg.Out, "manifest.json"))
+}
+
+// outputWriter writes an output to a file in the configured format,
+// one batch of rows at a time, so that the outputs for all of the
+// images do not need to be held in memory. For the npz format,
+// each output is written to a temporary .npy file, which is added
+// to the .npz file by [writeNPZ] at the end.
+type outputWriter struct {
+	// mo records the output in the manifest.
+	mo ManifestOutput
+
+	file *os.File
+	bw   *bufio.Writer
+
+	// npw writes the npy and npz formats.
+	npw *npy.Writer
+}
+
+// newOutputWriter returns a new [outputWriter] for given batch output,
+// with given total number of rows (images), creating its file.
+func newOutputWriter(cfg *Config, out output, rows int) (*outputWriter, error) {
+	shape := out.tsr.ShapeSizes()
+	shape[0] = rows
+	ow := &outputWriter{mo: ManifestOutput{Name: out.name, File: out.name + "." + cfg.Format, Shape: shape, Rows: out.layout.RowNames(), Cols: out.layout.ColNames()}}
+	fn := ow.mo.File
+	if cfg.Format == "npz" {
+		ow.mo.File = "outputs.npz"
+		fn = out.name + ".npy.tmp"
+	}
+	f, err := os.Create(filepath.Join(cfg.Out, fn))
+	if err != nil {
+		return nil, err
+	}
+	ow.file = f
+	ow.bw = bufio.NewWriter(f)
+	if cfg.Format == "tsv" {
+		return ow, nil
+	}
+	ow.npw, err = npy.NewWriter(ow.bw, rows, out.tsr)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return ow, nil
+}
+
+// write writes the first n rows of given batch output.
+func (ow *outputWriter) write(tsr *tensor.Float32, n int) error {
+	sizes := tsr.ShapeSizes()
+	sizes[0] = n
+	rows := tensor.NewFloat32FromValues(tsr.Values[:n*(tsr.Len()/tsr.DimSize(0))]...)
+	rows.SetShapeSizes(sizes...)
+	if ow.npw != nil {
+		return ow.npw.Write(rows)
+	}
+	return tensor.WriteCSV(tensor.Reshape(rows, n, rows.Len()/n), ow.bw, tensor.Tab)
+}
+
+// close checks that all rows were written, and flushes and closes the file.
+func (ow *outputWriter) close() error {
+	var err error
+	if ow.npw != nil {
+		err = ow.npw.Close()
+	}
+	if ferr := ow.bw.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := ow.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// writeNPZ writes the outputs.npz file in given directory from the
+// temporary .npy files of the given closed outputs, removing them.
+func writeNPZ(dir string, ows []*outputWriter) error {
+	f, err := os.Create(filepath.Join(dir, "outputs.npz"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, ow := range ows {
+		fw, err := zw.Create(ow.mo.Name + ".npy")
+		if err != nil {
+			return err
+		}
+		tf, err := os.Open(ow.file.Name())
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(fw, tf)
+		tf.Close()
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(tf.Name()); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// imageFiles returns the image files from given inputs, which are image
+// files or directories (all image files in the directory, in sorted
+// order), and the files listed in the given list file, if non-empty.
+func imageFiles(inputs []string, list string) ([]string, error) {
+	var files []string
+	for _, in := range inputs {
+		st, err := os.Stat(in)
+		if err != nil {
+			return nil, err
+		}
+		if !st.IsDir() {
+			files = append(files, in)
+			continue
+		}
+		ents, err := os.ReadDir(in)
+		if err != nil {
+			return nil, err
+		}
+		var dfs []string
+		for _, ent := range ents {
+			if !ent.IsDir() && isImage(ent.Name()) {
+				dfs = append(dfs, filepath.Join(in, ent.Name()))
+			}
+		}
+		slices.Sort(dfs)
+		files = append(files, dfs...)
+	}
+	if list == "" {
+		return files, nil
+	}
+	f, err := os.Open(list)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fn := strings.TrimSpace(sc.Text())
+		if fn == "" || strings.HasPrefix(fn, "#") {
+			continue
+		}
+		files = append(files, fn)
+	}
+	return files, sc.Err()
+}
+
+// isImage returns whether the given file name has an image file extension.
+func isImage(fn string) bool {
+	switch strings.ToLower(filepath.Ext(fn)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".tif", ".tiff", ".bmp", ".webp":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/base/iox/jsonx"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/npy"
+	"github.com/emer/v1vision/v1std"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	img := "../../v1vision/testdata/side-tee-128.png"
+	dir := t.TempDir()
+	list := filepath.Join(dir, "list.txt")
+	assert.NoError(t, os.WriteFile(list, []byte(img+"\n# comment\n\n"+img+"\n"), 0666))
+	cfg := &Config{Preset: "V1cGrey", Inputs: []string{img}, List: list, Out: dir, Format: "npy", NData: 2, Params: []string{"V1sKWTA.On=false"}}
+	assert.NoError(t, Run(cfg))
+
+	var mf Manifest
+	assert.NoError(t, jsonx.Open(&mf, filepath.Join(dir, "manifest.json")))
+	assert.Equal(t, []string{img, img, img}, mf.Images)
+	assert.Len(t, mf.Outputs, 1)
+	assert.Equal(t, "Output.npy", mf.Outputs[0].File)
+	assert.Equal(t, []int{3, 16, 16, 5, 4}, mf.Outputs[0].Shape)
+	assert.Equal(t, []string{"LenSum", "EndStop_Plus", "EndStop_Minus", "Simple_On", "Simple_Off"}, mf.Outputs[0].Rows)
+	assert.Equal(t, []string{"0", "45", "90", "135"}, mf.Outputs[0].Cols)
+	st, err := os.Stat(filepath.Join(dir, "Output.npy"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(128+3*16*16*5*4*4), st.Size())
+
+	// npz and tsv have the same values, written in batches
+	npyOut, err := npy.Open(filepath.Join(dir, "Output.npy"))
+	assert.NoError(t, err)
+	cfg.Format = "npz"
+	assert.NoError(t, Run(cfg))
+	names, npzOuts, err := npy.OpenNPZ(filepath.Join(dir, "outputs.npz"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Output"}, names)
+	assert.Equal(t, npyOut.ShapeSizes(), npzOuts[0].ShapeSizes())
+	assert.True(t, slices.Equal(npyOut.(*tensor.Float32).Values, npzOuts[0].(*tensor.Float32).Values))
+	_, err = os.Stat(filepath.Join(dir, "Output.npy.tmp"))
+	assert.True(t, os.IsNotExist(err))
+	cfg.Format = "tsv"
+	assert.NoError(t, Run(cfg))
+	tsvOut := tensor.NewFloat32(3, 16*16*5*4)
+	assert.NoError(t, tensor.OpenCSV(tsvOut, fsx.Filename(filepath.Join(dir, "Output.tsv")), tensor.Tab))
+	assert.True(t, slices.Equal(npyOut.(*tensor.Float32).Values, tsvOut.Values))
+	tsv, err := os.ReadFile(filepath.Join(dir, "Output.tsv"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, bytes.Count(tsv, []byte("\n")))
+	cfg.Format = "npy"
+
+	cfg.Params = []string{"NoSuchField=1"}
+	assert.Error(t, Run(cfg))
+	cfg.Params = nil
+	cfg.Format = "csv"
+	assert.Error(t, Run(cfg))
+	cfg.Format = "tsv"
+	cfg.Preset = "NoSuchPreset"
+	assert.Error(t, Run(cfg))
+
+	conf := filepath.Join(dir, "multi.toml")
+	assert.NoError(t, os.WriteFile(conf, []byte(`ImageSize = {X = 64, Y = 64}
+DoGColor = []
+
+[[V1c]]
+Name = "M8"
+Zoom = 1
+Border = 12
+Size = 12
+Spacing = 4
+`), 0666))
+	cfg = &Config{Preset: "V1cGrey", Inputs: []string{img}, Out: dir, Format: "npz", NData: 1, PipelineConfig: conf}
+	assert.Error(t, Run(cfg))
+	cfg.Preset = "V1cMulti"
+	assert.NoError(t, Run(cfg))
+	mf = Manifest{}
+	assert.NoError(t, jsonx.Open(&mf, filepath.Join(dir, "manifest.json")))
+	assert.Equal(t, image.Point{64, 64}, mf.ImageSize)
+	assert.Equal(t, "config.toml", mf.Config)
+	assert.Len(t, mf.Outputs, 1)
+	assert.Equal(t, "V1c_M8", mf.Outputs[0].Name)
+	assert.Equal(t, []int{1, 8, 8, 9, 4}, mf.Outputs[0].Shape)
+	var vi v1std.V1cMulti
+	vi.Defaults()
+	assert.NoError(t, vi.OpenConfig(filepath.Join(dir, mf.Config)))
+	assert.Equal(t, 1, len(vi.V1cParams))
+	assert.Equal(t, 0, len(vi.DoGParams))
+
+	cfg = &Config{Preset: "V1cMulti:LowMedHigh8DegGrey", Inputs: []string{img}, Out: dir, Format: "npz", NData: 1}
+	assert.NoError(t, Run(cfg))
+	mf = Manifest{}
+	assert.NoError(t, jsonx.Open(&mf, filepath.Join(dir, "manifest.json")))
+	assert.Equal(t, image.Point{64, 64}, mf.ImageSize)
+	assert.Len(t, mf.Outputs, 3)
+	assert.Equal(t, "V1c_H8", mf.Outputs[2].Name)
+	assert.Equal(t, []int{1, 16, 16, 5, 4}, mf.Outputs[2].Shape)
+	cfg.Preset = "V1cMulti:NoSuchPreset"
+	assert.Error(t, Run(cfg))
+	// default LowMed16DegZoom1 sizes require a 128x128 image size
+	cfg.Preset = "V1cMulti"
+	cfg.Size = image.Point{100, 100}
+	assert.Error(t, Run(cfg))
+	cfg.Size = image.Point{}
+	assert.NoError(t, Run(cfg))
+	cfg.Preset = "V1cGrey"
+	cfg.Size = image.Point{-1, 64}
+	assert.Error(t, Run(cfg))
+	cfg.Size = image.Point{}
+	cfg.Params = []string{"V1sTopK.Pool.On=true"}
+	assert.Error(t, Run(cfg))
+	cfg.Params = nil
+	cfg.Preset = "V1cGrey:LowMedHigh8DegGrey"
+	assert.Error(t, Run(cfg))
+}
+
+func TestSetParamsUpdate(t *testing.T) {
+	img := "../../v1vision/testdata/side-tee-128.png"
+	run := func(params ...string) []float32 {
+		dir := t.TempDir()
+		cfg := &Config{Preset: "V1cGrey", Inputs: []string{img}, Out: dir, Format: "npy", NData: 1, Params: params}
+		assert.NoError(t, Run(cfg))
+		out, err := npy.Open(filepath.Join(dir, "Output.npy"))
+		assert.NoError(t, err)
+		return out.(*tensor.Float32).Values
+	}
+	def := run()
+	assert.True(t, slices.Equal(def, run("V1sKWTA.ActTau=3")))
+	// ActDt is derived from ActTau by KWTA.Update.
+	assert.False(t, slices.Equal(def, run("V1sKWTA.ActTau=1")))
+
+	pl, err := newPipeline("V1cGrey")
+	assert.NoError(t, err)
+	assert.NoError(t, pl.setParams([]string{"V1sKWTA.ActTau=2"}))
+	assert.Equal(t, float32(0.5), pl.params.(*v1std.V1cGrey).V1sKWTA.ActDt)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"strings"
+
+	"cogentcore.org/core/base/reflectx"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1std"
+)
+
+// presets are the names of the available pipeline presets.
+var presets = []string{"V1cGrey", "V1cColor", "DoGGrey", "DoGColor", "V1cMulti"}
+
+// output is a named output of a pipeline, with an outer NData dimension.
+type output struct {
+	name   string
+	tsr    *tensor.Float32
+	layout *v1std.Layout
+}
+
+// pipeline is a v1std pipeline configured from a preset.
+type pipeline struct {
+	// params is the pipeline struct that overrides are applied to.
+	params any
+
+	// size is the default image size for the preset.
+	size image.Point
+
+	// validate returns an error if the pipeline parameters are not valid
+	// for given image size, for presets that can check them.
+	validate func(size image.Point) error
+
+	// config configures the pipeline for given ndata and image size.
+	config func(ndata int, size image.Point)
+
+	// run runs the pipeline on given images.
+	run func(im *v1std.Image, imgs []image.Image)
+
+	// outputs returns the outputs of the pipeline after running.
+	outputs func() []output
+
+	// openConfig opens a pipeline config file, for presets that support it.
+	openConfig func(filename string) error
+
+	// saveConfig saves the effective pipeline config file,
+	// for presets that support it.
+	saveConfig func(filename string) error
+}
+
+// newPipeline returns a new pipeline for given preset name,
+// configured to run on the CPU. The V1cMulti preset can be followed
+// by :name of one of the [v1std.MultiPresets], e.g., V1cMulti:LowMed32DegZoom1.
+func newPipeline(preset string) (*pipeline, error) {
+	pl := &pipeline{size: image.Point{128, 128}}
+	preset, multi, _ := strings.Cut(preset, ":")
+	if multi != "" && preset != "V1cMulti" {
+		return nil, fmt.Errorf("only the V1cMulti preset has named presets: %q", preset+":"+multi)
+	}
+	switch preset {
+	case "V1cGrey":
+		vi := &v1std.V1cGrey{}
+		vi.Defaults()
+		pl.params = vi
+		pl.validate = func(size image.Point) error { return vi.Validate() }
+		pl.config = func(ndata int, size image.Point) {
+			vi.GPU = false
+			vi.Config(ndata, size)
+		}
+		pl.run = func(im *v1std.Image, imgs []image.Image) { vi.RunImages(im, imgs...) }
+		pl.outputs = func() []output { return []output{layoutOutput("Output", vi.Layout(), vi.Output)} }
+	case "V1cColor":
+		vi := &v1std.V1cColor{}
+		vi.Defaults()
+		pl.params = vi
+		pl.config = func(ndata int, size image.Point) {
+			vi.GPU = false
+			vi.Config(ndata, size)
+		}
+		pl.run = func(im *v1std.Image, imgs []image.Image) { vi.RunImages(im, imgs...) }
+		pl.outputs = func() []output { return []output{layoutOutput("Output", vi.Layout(), vi.Output)} }
+	case "DoGGrey":
+		vi := &v1std.DoGGrey{}
+		vi.Defaults()
+		pl.params = vi
+		pl.config = func(ndata int, size image.Point) {
+			vi.GPU = false
+			vi.Config(ndata, size)
+		}
+		pl.run = func(im *v1std.Image, imgs []image.Image) { vi.RunImages(im, imgs...) }
+		pl.outputs = func() []output { return []output{layoutOutput("Output", vi.Layout(), vi.Output)} }
+	case "DoGColor":
+		vi := &v1std.DoGColor{}
+		vi.Defaults()
+		pl.params = vi
+		pl.config = func(ndata int, size image.Point) {
+			vi.GPU = false
+			vi.Config(ndata, size)
+		}
+		pl.run = func(im *v1std.Image, imgs []image.Image) { vi.RunImages(im, imgs...) }
+		pl.outputs = func() []output { return []output{layoutOutput("Output", vi.Layout(), vi.Output)} }
+	case "V1cMulti":
+		vi := &v1std.V1cMulti{}
+		vi.Defaults()
+		if multi == "" {
+			multi = "LowMed16DegZoom1"
+		}
+		if err := vi.SetPreset(multi); err != nil {
+			return nil, err
+		}
+		pl.params = vi
+		pl.size = vi.Image.Size
+		pl.validate = func(size image.Point) error {
+			var mc v1std.MultiConfig
+			mc.From(vi)
+			mc.ImageSize = size
+			return mc.Validate()
+		}
+		pl.config = func(ndata int, size image.Point) {
+			vi.GPU = false
+			vi.Image.Size = size
+			vi.Config(ndata)
+		}
+		pl.run = func(im *v1std.Image, imgs []image.Image) {
+			vi.Image.Fit = im.Fit
+			vi.RunImages(imgs...)
+		}
+		pl.outputs = func() []output {
+			var outs []output
+			for _, vp := range vi.V1cParams {
+				outs = append(outs, output{"V1c_" + vp.Name, &vp.Output, vp.Layout(vi.SplitColor)})
+			}
+			for _, vp := range vi.DoGParams {
+				outs = append(outs, output{"DoG_" + vp.Name, &vp.Output, vp.Layout()})
+			}
+			return outs
+		}
+		pl.openConfig = func(filename string) error {
+			if err := vi.OpenConfig(filename); err != nil {
+				return err
+			}
+			pl.size = vi.Image.Size
+			return nil
+		}
+		pl.saveConfig = vi.SaveConfig
+	default:
+		return nil, fmt.Errorf("unknown preset: %q, must be one of: %s", preset, strings.Join(presets, ", "))
+	}
+	return pl, nil
+}
+
+// setParams applies the given parameter overrides, as Field.Path=value
+// strings, to the pipeline parameters, and then updates their derived
+// parameters (see [updateParams]).
+func (pl *pipeline) setParams(sets []string) error {
+	vals := make(map[string]any, len(sets))
+	for _, s := range sets {
+		path, val, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("parameter override must be Field.Path=value: %q", s)
+		}
+		path = strings.TrimSpace(path)
+		if _, err := reflectx.FieldByPath(reflect.ValueOf(pl.params), path); err != nil {
+			return fmt.Errorf("parameter override %q: %w", s, err)
+		}
+		vals[path] = strings.TrimSpace(val)
+	}
+	if err := reflectx.SetFieldsFromMap(pl.params, vals); err != nil {
+		return err
+	}
+	updateParams(pl.params)
+	return nil
+}
+
+// updateParams calls Update on each field of the given pipeline struct
+// pointer that has one (e.g., the KWTA params), to recompute the
+// derived parameters (e.g., ActDt from ActTau) after overrides,
+// which the pipeline Config methods do not do.
+func updateParams(params any) {
+	pv := reflect.ValueOf(params).Elem()
+	for i := range pv.NumField() {
+		if !pv.Type().Field(i).IsExported() {
+			continue
+		}
+		if up, ok := pv.Field(i).Addr().Interface().(interface{ Update() }); ok {
+			up.Update()
+		}
+	}
+}
+
+// layoutOutput returns the output with given name for given output tensor,
+// which can be larger than its actual size, in the exact layout shape.
+func layoutOutput(name string, ly *v1std.Layout, tsr *tensor.Float32) output {
+	return output{name, ly.Exact(tsr), ly}
+}
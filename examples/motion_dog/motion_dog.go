@@ -148,7 +148,7 @@ func (vi *Vis) Config() {
 // RenderFrames renders the frames
 func (vi *Vis) RenderFrames() { //types:add
 	vi.V1.ZeroValues()
-	vi.Motion.NormInteg = 0
+	vi.Motion.Reset()
 	vi.Pos = vi.Start
 	for i := range vi.NFrames {
 		vi.RenderFrame()
@@ -158,7 +158,7 @@ func (vi *Vis) RenderFrames() { //types:add
 			vi.tabView.AsyncLock()
 			vi.tabView.Update()
 			vi.tabView.AsyncUnlock()
-			fmt.Printf("%d\tL: %7.4g\tR: %7.4g\tB: %7.4g\tT: %7.4g\tN: %7.4g\n", i, vi.FullField.Value1D(0), vi.FullField.Value1D(1), vi.FullField.Value1D(2), vi.FullField.Value1D(3), vi.Motion.NormInteg)
+			fmt.Printf("%d\tL: %7.4g\tR: %7.4g\tB: %7.4g\tT: %7.4g\tN: %7.4g\n", i, vi.FullField.Value1D(0), vi.FullField.Value1D(1), vi.FullField.Value1D(2), vi.FullField.Value1D(3), vi.Motion.NormInteg[0])
 			time.Sleep(vi.FrameDelay)
 		}
 	}
@@ -28,7 +28,7 @@ type KWTA struct {
 	Iters int32 `default:"10"`
 
 	// Threshold on delta-activation (change in activation) for stopping
-	// updating of activations. Not used on GPU implementation.
+	// updating of activations, if EarlyStop is on.
 	DelActThr float32 `default:"0.005"`
 
 	// Time constant for integrating activation
@@ -58,7 +58,12 @@ type KWTA struct {
 
 	ActDt float32 `display:"-"; json"-" xml"-" desc:"integration rate = 1/ tau"`
 
-	pad, pad1, pad2 float32
+	// EarlyStop stops iterating once the max delta-activation across
+	// all values and data-parallel items is below DelActThr.
+	// This requires an additional transfer from the GPU per iteration.
+	EarlyStop slbool.Bool
+
+	pad1, pad2 float32
 }
 
 func (kp *KWTA) Defaults() {
@@ -0,0 +1,37 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+// Spikes specifies an optional spiking output stage after KWTA
+// inhibition, which converts the rate-code activations into Poisson
+// spike trains and / or spike counts over a number of cycles,
+// for use by spiking networks (e.g., axon).
+type Spikes struct {
+
+	// On enables the spiking output stage.
+	On bool
+
+	// Cycles is the number of cycles (e.g., msec) to generate spikes over.
+	Cycles int `default:"200"`
+
+	// MaxRate is the probability of spiking per cycle for an activation
+	// of 1, e.g., 0.1 = 100 Hz for msec cycles.
+	// The spike probability is MaxRate * activation.
+	MaxRate float32 `default:"0.1"`
+
+	// Trains records the full per-cycle spike trains,
+	// in addition to the spike counts.
+	Trains bool
+
+	// Seed is the random seed for generating spikes. The same seed
+	// and activations always produce the same spikes, on CPU and GPU.
+	Seed uint32 `default:"1"`
+}
+
+func (sp *Spikes) Defaults() {
+	sp.Cycles = 200
+	sp.MaxRate = 0.1
+	sp.Seed = 1
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+// TopK specifies exact top-k selection, as an alternative to the
+// approximate FFFB dynamics of [KWTA], where all but the k largest
+// values are set to 0. Selection can be applied per pool
+// ([Polarity][FilterN] at each location), and / or over the
+// entire layer, with the pool selection applied first.
+type TopK struct {
+
+	// Pool is top-k selection within each pool.
+	Pool TopKSel `display:"inline"`
+
+	// Layer is top-k selection over the entire layer.
+	Layer TopKSel `display:"inline"`
+}
+
+func (tk *TopK) Defaults() {
+	tk.Pool.K = 2
+	tk.Layer.Pct = 0.1
+}
+
+// On returns true if either Pool or Layer selection is on.
+func (tk *TopK) On() bool {
+	return tk.Pool.On || tk.Layer.On
+}
+
+// TopKSel has the parameters for one level of top-k selection.
+type TopKSel struct {
+
+	// On enables this level of top-k selection.
+	On bool
+
+	// K is the number of values to keep, if Pct is 0.
+	K int
+
+	// Pct is the proportion of values to keep, if > 0,
+	// which overrides K.
+	Pct float32
+}
+
+// N returns the number of values to keep out of given total number.
+// At least 1 value is kept.
+func (ts *TopKSel) N(n int) int {
+	k := ts.K
+	if ts.Pct > 0 {
+		k = int(ts.Pct*float32(n) + 0.5)
+	}
+	return max(min(k, n), 1)
+}
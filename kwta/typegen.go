@@ -8,6 +8,12 @@ import (
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.Chans", IDName: "chans", Doc: "Chans are ion channels used in computing point-neuron activation function", Directives: []types.Directive{{Tool: "gosl", Directive: "start"}}, Fields: []types.Field{{Name: "E", Doc: "excitatory sodium (Na) AMPA channels activated by synaptic glutamate"}, {Name: "L", Doc: "constant leak (potassium, K+) channels -- determines resting potential (typically higher than resting potential of K)"}, {Name: "I", Doc: "inhibitory chloride (Cl-) channels activated by synaptic GABA"}, {Name: "K", Doc: "gated / active potassium channels -- typically hyperpolarizing relative to leak / rest"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.KWTA", IDName: "kwta", Doc: "KWTA contains all the parameters needed for computing FFFB\n(feedforward & feedback) inhibition that results in roughly\nk-Winner-Take-All behavior.", Directives: []types.Directive{{Tool: "gosl", Directive: "start"}, {Tool: "gosl", Directive: "import", Args: []string{"github.com/emer/v1vision/fffb"}}, {Tool: "gosl", Directive: "import", Args: []string{"github.com/emer/v1vision/nxx1"}}}, Fields: []types.Field{{Name: "On", Doc: "On is whether to run kWTA or not."}, {Name: "Iters", Doc: "Iters is the maximum number of iterations to perform."}, {Name: "DelActThr", Doc: "Threshold on delta-activation (change in activation) for stopping\nupdating of activations. Not used on GPU implementation."}, {Name: "ActTau", Doc: "Time constant for integrating activation"}, {Name: "Layer", Doc: "Layer-level feedforward & feedback inhibition, applied over entire set of values."}, {Name: "Pool", Doc: "Pool-level (feature groups) feedforward and feedback inhibition.\napplied within inner-most dimensions inside outer 2 dimensions."}, {Name: "XX1", Doc: "XX1 are the Noisy X/X+1 rate code activation function parameters."}, {Name: "Gbar", Doc: "GBar are maximal conductances levels for channels."}, {Name: "Erev", Doc: "Erev are reversal potentials for each channel."}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}, {Name: "ActDt"}, {Name: "pad"}, {Name: "pad1"}, {Name: "pad2"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.KWTA", IDName: "kwta", Doc: "KWTA contains all the parameters needed for computing FFFB\n(feedforward & feedback) inhibition that results in roughly\nk-Winner-Take-All behavior.", Directives: []types.Directive{{Tool: "gosl", Directive: "start"}, {Tool: "gosl", Directive: "import", Args: []string{"github.com/emer/v1vision/fffb"}}, {Tool: "gosl", Directive: "import", Args: []string{"github.com/emer/v1vision/nxx1"}}}, Fields: []types.Field{{Name: "On", Doc: "On is whether to run kWTA or not."}, {Name: "Iters", Doc: "Iters is the maximum number of iterations to perform."}, {Name: "DelActThr", Doc: "Threshold on delta-activation (change in activation) for stopping\nupdating of activations, if EarlyStop is on."}, {Name: "ActTau", Doc: "Time constant for integrating activation"}, {Name: "Layer", Doc: "Layer-level feedforward & feedback inhibition, applied over entire set of values."}, {Name: "Pool", Doc: "Pool-level (feature groups) feedforward and feedback inhibition.\napplied within inner-most dimensions inside outer 2 dimensions."}, {Name: "XX1", Doc: "XX1 are the Noisy X/X+1 rate code activation function parameters."}, {Name: "Gbar", Doc: "GBar are maximal conductances levels for channels."}, {Name: "Erev", Doc: "Erev are reversal potentials for each channel."}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}, {Name: "ActDt"}, {Name: "EarlyStop", Doc: "EarlyStop stops iterating once the max delta-activation across\nall values and data-parallel items is below DelActThr.\nThis requires an additional transfer from the GPU per iteration."}, {Name: "pad1"}, {Name: "pad2"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.NeighInhib", IDName: "neigh-inhib", Doc: "NeighInhib adds an additional inhibition factor based on the same\nfeature along an orthogonal angle -- assumes inner-most X axis\nrepresents angle of gabor or related feature.\nThis helps reduce redundancy of feature code.", Fields: []types.Field{{Name: "On", Doc: "use neighborhood inhibition"}, {Name: "Gi", Doc: "overall value of the inhibition -- this is what is added into the unit Gi inhibition level"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.Spikes", IDName: "spikes", Doc: "Spikes specifies an optional spiking output stage after KWTA\ninhibition, which converts the rate-code activations into Poisson\nspike trains and / or spike counts over a number of cycles,\nfor use by spiking networks (e.g., axon).", Fields: []types.Field{{Name: "On", Doc: "On enables the spiking output stage."}, {Name: "Cycles", Doc: "Cycles is the number of cycles (e.g., msec) to generate spikes over."}, {Name: "MaxRate", Doc: "MaxRate is the probability of spiking per cycle for an activation\nof 1, e.g., 0.1 = 100 Hz for msec cycles.\nThe spike probability is MaxRate * activation."}, {Name: "Trains", Doc: "Trains records the full per-cycle spike trains,\nin addition to the spike counts."}, {Name: "Seed", Doc: "Seed is the random seed for generating spikes. The same seed\nand activations always produce the same spikes, on CPU and GPU."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.TopK", IDName: "top-k", Doc: "TopK specifies exact top-k selection, as an alternative to the\napproximate FFFB dynamics of [KWTA], where all but the k largest\nvalues are set to 0. Selection can be applied per pool\n([Polarity][FilterN] at each location), and / or over the\nentire layer, with the pool selection applied first.", Fields: []types.Field{{Name: "Pool", Doc: "Pool is top-k selection within each pool."}, {Name: "Layer", Doc: "Layer is top-k selection over the entire layer."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/kwta.TopKSel", IDName: "top-k-sel", Doc: "TopKSel has the parameters for one level of top-k selection.", Fields: []types.Field{{Name: "On", Doc: "On enables this level of top-k selection."}, {Name: "K", Doc: "K is the number of values to keep, if Pct is 0."}, {Name: "Pct", Doc: "Pct is the proportion of values to keep, if > 0,\nwhich overrides K."}}})
@@ -6,20 +6,20 @@ import (
 	"cogentcore.org/core/enums"
 )
 
-var _DirectionsValues = []Directions{0, 1, 2, 3}
+var _DirectionsValues = []Directions{0, 1, 2, 3, 4, 5, 6, 7}
 
 // DirectionsN is the highest valid value for type Directions, plus one.
 //
 //gosl:start
-const DirectionsN Directions = 4
+const DirectionsN Directions = 8
 
 //gosl:end
 
-var _DirectionsValueMap = map[string]Directions{`Left`: 0, `Right`: 1, `Down`: 2, `Up`: 3}
+var _DirectionsValueMap = map[string]Directions{`Left`: 0, `Right`: 1, `Down`: 2, `Up`: 3, `Expand`: 4, `Contract`: 5, `Clockwise`: 6, `CounterClockwise`: 7}
 
-var _DirectionsDescMap = map[Directions]string{0: ``, 1: ``, 2: ``, 3: ``}
+var _DirectionsDescMap = map[Directions]string{0: ``, 1: ``, 2: ``, 3: ``, 4: `Expand is outward radial motion from the focus.`, 5: `Contract is inward radial motion toward the focus.`, 6: `Clockwise is rotation around the focus.`, 7: `CounterClockwise is rotation around the focus.`}
 
-var _DirectionsMap = map[Directions]string{0: `Left`, 1: `Right`, 2: `Down`, 3: `Up`}
+var _DirectionsMap = map[Directions]string{0: `Left`, 1: `Right`, 2: `Down`, 3: `Up`, 4: `Expand`, 5: `Contract`, 6: `Clockwise`, 7: `CounterClockwise`}
 
 // String returns the string representation of this Directions value.
 func (i Directions) String() string { return enums.String(i, _DirectionsMap) }
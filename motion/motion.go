@@ -9,6 +9,7 @@ cells (SAC) that compute centrifugal motion flow from each point.
 package motion
 
 import (
+	"cogentcore.org/core/base/slicesx"
 	"cogentcore.org/lab/tensor"
 )
 
@@ -16,6 +17,8 @@ import (
 
 // Directions are the motion directions, in feature order,
 // as represented in the Star and FullField outputs.
+// The optic flow directions follow, as represented in the
+// OpticFlow outputs.
 type Directions int32 //enums:enum
 
 const (
@@ -23,6 +26,18 @@ const (
 	Right
 	Down
 	Up
+
+	// Expand is outward radial motion from the focus.
+	Expand
+
+	// Contract is inward radial motion toward the focus.
+	Contract
+
+	// Clockwise is rotation around the focus.
+	Clockwise
+
+	// CounterClockwise is rotation around the focus.
+	CounterClockwise
 )
 
 // Params has the motion parameters for retinal starburst amacrine
@@ -43,13 +58,43 @@ type Params struct {
 	// FullGain is multiplier for FullField
 	FullGain float32
 
+	// FlowGain is multiplier for the local Flow field,
+	// applied to the average opponent Star values within each pool.
+	FlowGain float32
+
+	// FlowPool is the size of the pools, in Star units, over which
+	// the local Flow field is computed. Pools are non-overlapping.
+	FlowPool int
+
+	// OpticFlow computes the optic flow summary values for
+	// Expand, Contract, Clockwise, CounterClockwise motion around
+	// the Focus, which are integrated along with the FullField values.
+	OpticFlow bool
+
+	// FocusX is the horizontal center of the OpticFlow templates,
+	// as a proportion of the image width (0.5 = center).
+	FocusX float32
+
+	// FocusY is the vertical center of the OpticFlow templates,
+	// as a proportion of the image height (0.5 = center).
+	FocusY float32
+
+	// GridY is the number of rows in the regional Grid of full-field
+	// motion values.
+	GridY int
+
+	// GridX is the number of columns in the regional Grid of full-field
+	// motion values.
+	GridX int
+
 	// IntegTau is the integration time constant for integrating
 	// the normalization and full field values over frames, to get
 	// a more consistent value.
 	IntegTau float32
 
-	// NormInteg is the integrated normalization value -- updated in FullFieldInteg
-	NormInteg float32 `edit:"-"`
+	// NormInteg is the integrated normalization value for each
+	// data-parallel item -- updated in FullFieldInteg
+	NormInteg []float32 `edit:"-"`
 
 	// DoGSumScalarIndex is the index into the V1Vision Scalars output for
 	// Sum of DoG activity, used for normalizing.
@@ -57,6 +102,9 @@ type Params struct {
 
 	// FFScalarIndex is the index into the V1Vision Scalars output for FullField
 	FFScalarIndex int `edit:"-"`
+
+	// OpticScalarIndex is the index into the V1Vision Scalars output for OpticFlow
+	OpticScalarIndex int `edit:"-"`
 }
 
 func (pr *Params) Defaults() {
@@ -65,57 +113,87 @@ func (pr *Params) Defaults() {
 	pr.FastTau = 2
 	pr.Gain = 20
 	pr.FullGain = 1
+	pr.FlowGain = 10
+	pr.FlowPool = 4
+	pr.FocusX = 0.5
+	pr.FocusY = 0.5
+	pr.GridY = 4
+	pr.GridX = 4
 	pr.IntegTau = 6
 }
 
+// Reset resets the integrated normalization values for all items.
+func (pr *Params) Reset() {
+	clear(pr.NormInteg)
+}
+
+// ResetItem resets the integrated normalization value for
+// given data-parallel item index.
+func (pr *Params) ResetItem(ni int) {
+	if ni < len(pr.NormInteg) {
+		pr.NormInteg[ni] = 0
+	}
+}
+
+// IntegRows returns the number of rows in the integrated
+// full-field output: 2 for [L,R][D,U], plus 2 more for
+// [Expand,Contract][Clockwise,CounterClockwise] if OpticFlow.
+func (pr *Params) IntegRows() int {
+	if pr.OpticFlow {
+		return 4
+	}
+	return 2
+}
+
 // FullFieldInteg computes a full-field integration of instantaneous
 // MotionFullField results, in scalars input at FFScalarIndex
 // Resulting integ tensor is 4 values (2x2) with left, right, bottom, top units.
+// If OpticFlow, the MotionOpticFlow results at OpticScalarIndex are
+// also integrated, into 2 more rows (4x2) with expand, contract,
+// clockwise, counterclockwise units (see [Directions] for 1D indexes).
 // integ = integrated full-field values over time
 // visNormInteg = integrated visNorm, actually used for normalization
 func (pr *Params) FullFieldInteg(ndata int, scalars, integ *tensor.Float32) {
 	idt := 1.0 / pr.IntegTau
-	integ.SetShapeSizes(ndata, 2, 2)
+	integ.SetShapeSizes(ndata, pr.IntegRows(), 2)
+	pr.NormInteg = slicesx.SetLength(pr.NormInteg, ndata)
 	for di := range ndata {
 		visNorm := scalars.Value(pr.DoGSumScalarIndex, di)
-		if pr.NormInteg == 0 {
-			pr.NormInteg = visNorm
+		normInteg := &pr.NormInteg[di]
+		if *normInteg == 0 {
+			*normInteg = visNorm
 		} else {
-			pr.NormInteg += idt * (visNorm - pr.NormInteg)
+			*normInteg += idt * (visNorm - *normInteg)
 		}
 		vnf := pr.FullGain
-		if pr.NormInteg > 0 {
-			vnf /= pr.NormInteg
+		if *normInteg > 0 {
+			vnf /= *normInteg
 		}
 
-		act := func(v float32) float32 { return vnf * v }
-		l := scalars.Value(pr.FFScalarIndex+0, di)
-		r := scalars.Value(pr.FFScalarIndex+1, di)
-		if l > r {
-			l = act(l - r)
-			r = 0
-		} else {
-			r = act(r - l)
-			l = 0
+		// opponent competition between scalars at si, si+1, integrated into row y
+		opponent := func(y, si int) {
+			a := scalars.Value(si, di)
+			b := scalars.Value(si+1, di)
+			if a > b {
+				a = vnf * (a - b)
+				b = 0
+			} else {
+				b = vnf * (b - a)
+				a = 0
+			}
+			integf := func(x int, v float32) {
+				vi := integ.Value(di, y, x)
+				vi += idt * (v - vi)
+				integ.Set(vi, di, y, x)
+			}
+			integf(0, a)
+			integf(1, b)
 		}
-		b := scalars.Value(pr.FFScalarIndex+2, di)
-		u := scalars.Value(pr.FFScalarIndex+3, di)
-		if b > u {
-			b = act(b - u)
-			u = 0
-		} else {
-			u = act(u - b)
-			b = 0
-		}
-
-		integf := func(y, x int, v float32) {
-			vi := integ.Value(di, y, x)
-			vi += idt * (v - vi)
-			integ.Set(vi, di, y, x)
+		opponent(0, pr.FFScalarIndex)
+		opponent(1, pr.FFScalarIndex+2)
+		if pr.OpticFlow {
+			opponent(2, pr.OpticScalarIndex)
+			opponent(3, pr.OpticScalarIndex+2)
 		}
-		integf(0, 0, l)
-		integf(0, 1, r)
-		integf(1, 0, b)
-		integf(1, 1, u)
 	}
 }
@@ -6,6 +6,6 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/motion.Directions", IDName: "directions", Doc: "Directions are the motion directions, in feature order,\nas represented in the Star and FullField outputs."})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/motion.Directions", IDName: "directions", Doc: "Directions are the motion directions, in feature order,\nas represented in the Star and FullField outputs.\nThe optic flow directions follow, as represented in the\nOpticFlow outputs."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/motion.Params", IDName: "params", Doc: "Params has the motion parameters for retinal starburst amacrine\ncells (SAC) that compute centrifugal motion flow from each point.", Fields: []types.Field{{Name: "SlowTau", Doc: "SlowTau is the time constant (in frames) for integrating\nslow inhibitory inputs."}, {Name: "FastTau", Doc: "FastTau is the time constant (in frames) for integrating\nfast excitatory inputs."}, {Name: "Gain", Doc: "Gain is multiplier on the opponent difference for Star computation."}, {Name: "FullGain", Doc: "FullGain is multiplier for FullField"}, {Name: "IntegTau", Doc: "IntegTau is the integration time constant for integrating\nthe normalization and full field values over frames, to get\na more consistent value."}, {Name: "NormInteg", Doc: "NormInteg is the integrated normalization value -- updated in FullFieldInteg"}, {Name: "DoGSumScalarIndex", Doc: "DoGSumScalarIndex is the index into the V1Vision Scalars output for\nSum of DoG activity, used for normalizing."}, {Name: "FFScalarIndex", Doc: "FFScalarIndex is the index into the V1Vision Scalars output for FullField"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/motion.Params", IDName: "params", Doc: "Params has the motion parameters for retinal starburst amacrine\ncells (SAC) that compute centrifugal motion flow from each point.", Fields: []types.Field{{Name: "SlowTau", Doc: "SlowTau is the time constant (in frames) for integrating\nslow inhibitory inputs."}, {Name: "FastTau", Doc: "FastTau is the time constant (in frames) for integrating\nfast excitatory inputs."}, {Name: "Gain", Doc: "Gain is multiplier on the opponent difference for Star computation."}, {Name: "FullGain", Doc: "FullGain is multiplier for FullField"}, {Name: "FlowGain", Doc: "FlowGain is multiplier for the local Flow field,\napplied to the average opponent Star values within each pool."}, {Name: "FlowPool", Doc: "FlowPool is the size of the pools, in Star units, over which\nthe local Flow field is computed. Pools are non-overlapping."}, {Name: "OpticFlow", Doc: "OpticFlow computes the optic flow summary values for\nExpand, Contract, Clockwise, CounterClockwise motion around\nthe Focus, which are integrated along with the FullField values."}, {Name: "FocusX", Doc: "FocusX is the horizontal center of the OpticFlow templates,\nas a proportion of the image width (0.5 = center)."}, {Name: "FocusY", Doc: "FocusY is the vertical center of the OpticFlow templates,\nas a proportion of the image height (0.5 = center)."}, {Name: "GridY", Doc: "GridY is the number of rows in the regional Grid of full-field\nmotion values."}, {Name: "GridX", Doc: "GridX is the number of columns in the regional Grid of full-field\nmotion values."}, {Name: "IntegTau", Doc: "IntegTau is the integration time constant for integrating\nthe normalization and full field values over frames, to get\na more consistent value."}, {Name: "NormInteg", Doc: "NormInteg is the integrated normalization value for each\ndata-parallel item -- updated in FullFieldInteg"}, {Name: "DoGSumScalarIndex", Doc: "DoGSumScalarIndex is the index into the V1Vision Scalars output for\nSum of DoG activity, used for normalizing."}, {Name: "FFScalarIndex", Doc: "FFScalarIndex is the index into the V1Vision Scalars output for FullField"}, {Name: "OpticScalarIndex", Doc: "OpticScalarIndex is the index into the V1Vision Scalars output for OpticFlow"}}})
@@ -0,0 +1,190 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package npy reads and writes tensors in the NumPy .npy and .npz formats,
+preserving their shape and dtype, so that v1vision outputs can be
+exchanged with Python (e.g., numpy.load) without a TSV round-trip.
+*/
+package npy
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"cogentcore.org/lab/tensor"
+)
+
+// magic is the magic string at the start of a .npy file.
+const magic = "\x93NUMPY"
+
+// Write writes the given tensor to w in the .npy format, in C
+// (row-major) order, with the dtype corresponding to its values:
+// float32 (<f4), float64 (<f8), int (<i8), int32 (<i4), uint32 (<u4)
+// or byte (|u1). Other tensor types are written as float64.
+func Write(w io.Writer, tsr tensor.Tensor) error {
+	descr, data := values(tsr)
+	if _, err := io.WriteString(w, header(descr, tsr.ShapeSizes())); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
+
+// values returns the .npy dtype and the data to write for given tensor.
+func values(tsr tensor.Tensor) (string, any) {
+	switch tv := tsr.AsValues().(type) {
+	case *tensor.Float32:
+		return "<f4", tv.Values
+	case *tensor.Float64:
+		return "<f8", tv.Values
+	case *tensor.Int:
+		i64 := make([]int64, len(tv.Values))
+		for i, v := range tv.Values {
+			i64[i] = int64(v)
+		}
+		return "<i8", i64
+	case *tensor.Int32:
+		return "<i4", tv.Values
+	case *tensor.Uint32:
+		return "<u4", tv.Values
+	case *tensor.Byte:
+		return "|u1", tv.Values
+	default:
+		f64 := make([]float64, tv.Len())
+		for i := range f64 {
+			f64[i] = tv.Float1D(i)
+		}
+		return "<f8", f64
+	}
+}
+
+// Writer writes a .npy file incrementally, in blocks of rows along
+// the outer dimension, so that arrays with many rows do not need to be
+// held in memory. The total number of rows must be known in advance,
+// as it is recorded in the header. See [Write] for the dtypes.
+type Writer struct {
+	w     io.Writer
+	descr string
+
+	// shape is the full shape of the array.
+	shape []int
+
+	// n is the number of rows written so far.
+	n int
+}
+
+// NewWriter returns a new [Writer] that writes to w an array with given
+// total number of rows, and the dtype and inner (row) shape of given
+// tensor (e.g., the first block of rows), and writes the header.
+func NewWriter(w io.Writer, rows int, tsr tensor.Tensor) (*Writer, error) {
+	descr, _ := values(tsr)
+	shape := tsr.ShapeSizes()
+	if len(shape) == 0 {
+		return nil, fmt.Errorf("npy.NewWriter: tensor must have an outer row dimension")
+	}
+	shape[0] = rows
+	nw := &Writer{w: w, descr: descr, shape: shape}
+	if _, err := io.WriteString(w, header(descr, shape)); err != nil {
+		return nil, err
+	}
+	return nw, nil
+}
+
+// Write writes the rows of given tensor, which must have the same
+// dtype and inner shape as the array, after the rows already written.
+func (nw *Writer) Write(tsr tensor.Tensor) error {
+	descr, data := values(tsr)
+	shape := tsr.ShapeSizes()
+	switch {
+	case descr != nw.descr:
+		return fmt.Errorf("npy.Writer: dtype %s != %s", descr, nw.descr)
+	case len(shape) != len(nw.shape) || !slices.Equal(shape[1:], nw.shape[1:]):
+		return fmt.Errorf("npy.Writer: row shape %v != %v", shape, nw.shape)
+	case nw.n+shape[0] > nw.shape[0]:
+		return fmt.Errorf("npy.Writer: %d rows is more than the total: %d", nw.n+shape[0], nw.shape[0])
+	}
+	nw.n += shape[0]
+	return binary.Write(nw.w, binary.LittleEndian, data)
+}
+
+// Close returns an error if fewer than the total number of rows
+// have been written. It does not close the underlying writer.
+func (nw *Writer) Close() error {
+	if nw.n != nw.shape[0] {
+		return fmt.Errorf("npy.Writer: %d rows written, not the total: %d", nw.n, nw.shape[0])
+	}
+	return nil
+}
+
+// header returns the version 1.0 .npy header for given dtype and shape,
+// padded so that the data starts at a multiple of 64 bytes.
+func header(descr string, shape []int) string {
+	dims := make([]string, len(shape))
+	for i, s := range shape {
+		dims[i] = fmt.Sprintf("%d", s)
+	}
+	shp := "(" + strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shp += ","
+	}
+	shp += ")"
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': %s, }", descr, shp)
+	pre := len(magic) + 4 // magic, version, header length
+	n := pre + len(dict) + 1
+	dict += strings.Repeat(" ", (64-n%64)%64) + "\n"
+	hl := len(dict)
+	return magic + "\x01\x00" + string([]byte{byte(hl), byte(hl >> 8)}) + dict
+}
+
+// Save saves the given tensor to a .npy file. See [Write].
+func Save(filename string, tsr tensor.Tensor) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = Write(f, tsr)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteNPZ writes the given tensors to w in the .npz format, which is a
+// zip archive of .npy files, with the given array names (without the
+// .npy extension), in the same order as the tensors. See [Write].
+func WriteNPZ(w io.Writer, names []string, tsrs ...tensor.Tensor) error {
+	if len(names) != len(tsrs) {
+		return fmt.Errorf("npy.WriteNPZ: number of names: %d != number of tensors: %d", len(names), len(tsrs))
+	}
+	zw := zip.NewWriter(w)
+	for i, tsr := range tsrs {
+		fw, err := zw.Create(names[i] + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := Write(fw, tsr); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// SaveNPZ saves the given tensors to a .npz file, with the given
+// array names. See [WriteNPZ].
+func SaveNPZ(filename string, names []string, tsrs ...tensor.Tensor) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = WriteNPZ(f, names, tsrs...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
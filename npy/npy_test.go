@@ -0,0 +1,143 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cogentcore.org/lab/tensor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	tsr := tensor.NewFloat32(2, 3, 4)
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(i) * 0.5
+	}
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, tsr))
+	data := b.Bytes()
+	assert.Equal(t, magic+"\x01\x00", string(data[:8]))
+	hl := int(binary.LittleEndian.Uint16(data[8:10]))
+	assert.Equal(t, 0, (10+hl)%64)
+	hdr := string(data[10 : 10+hl])
+	assert.Contains(t, hdr, "'descr': '<f4'")
+	assert.Contains(t, hdr, "'shape': (2, 3, 4)")
+	assert.Equal(t, byte('\n'), hdr[hl-1])
+	vals := make([]float32, tsr.Len())
+	assert.NoError(t, binary.Read(bytes.NewReader(data[10+hl:]), binary.LittleEndian, vals))
+	assert.Equal(t, tsr.Values, vals)
+
+	b.Reset()
+	assert.NoError(t, Write(&b, tensor.NewInt(5)))
+	assert.Contains(t, b.String(), "'descr': '<i8', 'fortran_order': False, 'shape': (5,)")
+	assert.Equal(t, 128+5*8, b.Len()) // header padded to 128
+}
+
+func TestWriteNPZ(t *testing.T) {
+	a := tensor.NewFloat64(3)
+	c := tensor.NewByte(2, 2)
+	var b bytes.Buffer
+	assert.Error(t, WriteNPZ(&b, []string{"a"}, a, c))
+	b.Reset()
+	assert.NoError(t, WriteNPZ(&b, []string{"a", "c"}, a, c))
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, zr.File, 2)
+	assert.Equal(t, "a.npy", zr.File[0].Name)
+	assert.Equal(t, "c.npy", zr.File[1].Name)
+	assert.Equal(t, uint64(128+4), zr.File[1].UncompressedSize64)
+}
+
+func TestRead(t *testing.T) {
+	f32 := tensor.NewFloat32(2, 3, 4)
+	for i := range f32.Values {
+		f32.Values[i] = float32(i) * 0.5
+	}
+	i64 := tensor.NewIntFromValues(1, -2, 3)
+	by := tensor.NewByte(3, 1)
+	by.Values = []byte{1, 2, 255}
+	u32 := tensor.NewUint32(2, 2)
+	u32.Values = []uint32{0, 1, 1 << 31, 7}
+	tsrs := []tensor.Values{f32, tensor.NewFloat64FromValues(0.25, -1), i64, tensor.NewInt32(1, 2), u32, by}
+	for _, tsr := range tsrs {
+		var b bytes.Buffer
+		assert.NoError(t, Write(&b, tsr))
+		rt, err := Read(&b)
+		assert.NoError(t, err)
+		assert.IsType(t, tsr, rt)
+		assert.Equal(t, tsr.ShapeSizes(), rt.ShapeSizes())
+		for i := range tsr.Len() {
+			assert.Equal(t, tsr.Float1D(i), rt.Float1D(i))
+		}
+	}
+
+	// big-endian, version 2.0, scalar, from numpy
+	hdr := "{'descr': '>i4', 'fortran_order': False, 'shape': (), }\n"
+	data := []byte(magic + "\x02\x00")
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(hdr)))
+	data = append(data, hdr...)
+	data = binary.BigEndian.AppendUint32(data, 258)
+	rt, err := Read(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, rt.ShapeSizes())
+	assert.Equal(t, 258, rt.Int1D(0))
+
+	_, err = Read(bytes.NewReader([]byte("not npy data")))
+	assert.Error(t, err)
+	var b bytes.Buffer
+	b.WriteString(header("<f4", []int{2}))
+	_, err = Read(&b) // missing data
+	assert.Error(t, err)
+	b.Reset()
+	b.WriteString(header("<c8", []int{2}))
+	_, err = Read(&b)
+	assert.Error(t, err)
+	b.Reset()
+	b.WriteString(strings.Replace(header("<f4", []int{2}), "False", "True ", 1))
+	_, err = Read(&b)
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "a.npz")
+	assert.NoError(t, SaveNPZ(fn, []string{"f32", "i64"}, f32, i64))
+	names, rts, err := OpenNPZ(fn)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"f32", "i64"}, names)
+	assert.Equal(t, f32.Values, rts[0].(*tensor.Float32).Values)
+	assert.Equal(t, i64.Values, rts[1].(*tensor.Int).Values)
+}
+
+func TestWriter(t *testing.T) {
+	tsr := tensor.NewFloat32(5, 2, 3)
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(i)
+	}
+	rows := func(st, n int) *tensor.Float32 {
+		r := tensor.NewFloat32FromValues(tsr.Values[st*6 : (st+n)*6]...)
+		r.SetShapeSizes(n, 2, 3)
+		return r
+	}
+	var b bytes.Buffer
+	nw, err := NewWriter(&b, 5, rows(0, 2))
+	assert.NoError(t, err)
+	assert.NoError(t, nw.Write(rows(0, 2)))
+	assert.Error(t, nw.Close())
+	assert.Error(t, nw.Write(tensor.NewFloat32(1, 3, 2)))
+	assert.Error(t, nw.Write(tensor.NewFloat64(1, 2, 3)))
+	assert.NoError(t, nw.Write(rows(2, 2)))
+	assert.Error(t, nw.Write(rows(3, 2)))
+	assert.NoError(t, nw.Write(rows(4, 1)))
+	assert.NoError(t, nw.Close())
+
+	var w bytes.Buffer
+	assert.NoError(t, Write(&w, tsr))
+	assert.Equal(t, w.Bytes(), b.Bytes())
+}
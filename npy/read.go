@@ -0,0 +1,206 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/lab/tensor"
+)
+
+// Read reads a tensor in the .npy format from r, returning a tensor
+// of the type corresponding to its dtype, with the same shape:
+// float32 (f4), float64 (f8), int (i8), int32 (i4), uint32 (u4)
+// or byte (u1), in either byte order. Fortran-order arrays are not
+// supported. A 0-dimensional (scalar) array is returned with shape (1).
+func Read(r io.Reader) (tensor.Values, error) {
+	descr, shape, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if descr[0] == '>' {
+		order = binary.BigEndian
+	}
+	if len(shape) == 0 {
+		shape = []int{1}
+	}
+	var tsr tensor.Values
+	var data any
+	var i64 []int64
+	switch descr[1:] {
+	case "f4":
+		t := tensor.NewFloat32(shape...)
+		tsr, data = t, t.Values
+	case "f8":
+		t := tensor.NewFloat64(shape...)
+		tsr, data = t, t.Values
+	case "i8":
+		tsr = tensor.NewInt(shape...)
+		i64 = make([]int64, tsr.Len())
+		data = i64
+	case "i4":
+		t := tensor.NewInt32(shape...)
+		tsr, data = t, t.Values
+	case "u4":
+		t := tensor.NewUint32(shape...)
+		tsr, data = t, t.Values
+	case "u1":
+		t := tensor.NewByte(shape...)
+		tsr, data = t, t.Values
+	default:
+		return nil, fmt.Errorf("npy.Read: dtype not supported: %q", descr)
+	}
+	err = binary.Read(r, order, data)
+	if err != nil {
+		return nil, fmt.Errorf("npy.Read: reading data: %w", err)
+	}
+	for i, v := range i64 {
+		tsr.SetInt1D(int(v), i)
+	}
+	return tsr, nil
+}
+
+// readHeader reads the .npy header from r, returning the dtype
+// descr and the shape.
+func readHeader(r io.Reader) (string, []int, error) {
+	pre := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(r, pre); err != nil {
+		return "", nil, fmt.Errorf("npy.Read: reading header: %w", err)
+	}
+	if string(pre[:len(magic)]) != magic {
+		return "", nil, fmt.Errorf("npy.Read: not a .npy file")
+	}
+	var hl int
+	switch pre[len(magic)] {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return "", nil, fmt.Errorf("npy.Read: reading header: %w", err)
+		}
+		hl = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return "", nil, fmt.Errorf("npy.Read: reading header: %w", err)
+		}
+		hl = int(n)
+	default:
+		return "", nil, fmt.Errorf("npy.Read: version not supported: %d", pre[len(magic)])
+	}
+	hdr := make([]byte, hl)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return "", nil, fmt.Errorf("npy.Read: reading header: %w", err)
+	}
+	dict := string(hdr)
+	descr, err := headerValue(dict, "descr")
+	if err != nil {
+		return "", nil, err
+	}
+	descr = strings.Trim(descr, `'"`)
+	if len(descr) != 3 || !strings.ContainsRune("<>|=", rune(descr[0])) {
+		return "", nil, fmt.Errorf("npy.Read: dtype not supported: %q", descr)
+	}
+	if descr[0] == '=' {
+		descr = "<" + descr[1:]
+	}
+	fo, err := headerValue(dict, "fortran_order")
+	if err != nil {
+		return "", nil, err
+	}
+	if fo != "False" {
+		return "", nil, fmt.Errorf("npy.Read: fortran_order arrays are not supported")
+	}
+	shp, err := headerValue(dict, "shape")
+	if err != nil {
+		return "", nil, err
+	}
+	var shape []int
+	for _, s := range strings.Split(strings.Trim(shp, "()"), ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return "", nil, fmt.Errorf("npy.Read: invalid shape: %q", shp)
+		}
+		shape = append(shape, n)
+	}
+	return descr, shape, nil
+}
+
+// headerValue returns the value of given key in the header dict,
+// which is a Python dict literal with string, bool and tuple values.
+func headerValue(dict, key string) (string, error) {
+	_, rest, ok := strings.Cut(dict, "'"+key+"':")
+	if !ok {
+		return "", fmt.Errorf("npy.Read: header missing key: %q", key)
+	}
+	rest = strings.TrimSpace(rest)
+	end := strings.IndexAny(rest, ",}")
+	if strings.HasPrefix(rest, "(") {
+		end = strings.Index(rest, ")") + 1
+	}
+	if end <= 0 {
+		return "", fmt.Errorf("npy.Read: invalid header: %q", dict)
+	}
+	return strings.TrimSpace(rest[:end]), nil
+}
+
+// Open opens a tensor from a .npy file. See [Read].
+func Open(filename string) (tensor.Values, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// ReadNPZ reads the tensors in the .npz format from r, of given size,
+// returning the array names (without the .npy extension) and the
+// tensors, in the order they are stored. See [Read].
+func ReadNPZ(r io.ReaderAt, size int64) ([]string, []tensor.Values, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, len(zr.File))
+	tsrs := make([]tensor.Values, len(zr.File))
+	for i, zf := range zr.File {
+		names[i] = strings.TrimSuffix(zf.Name, ".npy")
+		fr, err := zf.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		tsrs[i], err = Read(fr)
+		fr.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", zf.Name, err)
+		}
+	}
+	return names, tsrs, nil
+}
+
+// OpenNPZ opens the tensors from a .npz file. See [ReadNPZ].
+func OpenNPZ(filename string) ([]string, []tensor.Values, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ReadNPZ(f, st.Size())
+}
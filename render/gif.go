@@ -0,0 +1,43 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+)
+
+// WriteGIF writes the given frame images as an animated GIF that loops
+// forever, with given delay between frames in 100ths of a second.
+// Frames are mapped onto the standard Plan9 palette.
+func WriteGIF(w io.Writer, delay int, frames ...image.Image) error {
+	anim := &gif.GIF{}
+	for _, fr := range frames {
+		pi := image.NewPaletted(fr.Bounds(), palette.Plan9)
+		draw.Draw(pi, pi.Rect, fr, fr.Bounds().Min, draw.Src)
+		anim.Image = append(anim.Image, pi)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// SaveGIF saves the given frame images to an animated GIF file,
+// with given delay between frames in 100ths of a second.
+// See [WriteGIF].
+func SaveGIF(filename string, delay int, frames ...image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = WriteGIF(f, delay, frames...)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/gosl/slvec"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// Overlay renders orientation-tuned values as oriented line segments
+// on top of the image, for each Geom.Out location of the values.
+// The image tensor is [RGB][Y][X] with padWidth padding on all sides,
+// as in [v1vision.RGBTensorToImage] (e.g., from [ImageOf] or [GreyImageOf]),
+// and the rendered image is the unpadded size.
+// The values tensor is [Y][X]...[FilterN], where the FilterN filters are
+// orientations evenly spaced over 180 degrees, starting with horizontal,
+// as in [gabor.Filter], and any dimensions in between (e.g., Polarity)
+// are reduced by max. This works for [ValuesOf] outputs and
+// [Values4DOf] outputs with angles as the inner dimension.
+// geom is the geometry of the convolution on the image (e.g., V1sGeom),
+// and any additional pooling geometries applied to its outputs
+// (e.g., V1cGeom from [v1vision.V1Vision.NewMaxPool]) are given in order.
+// Line length and opacity are proportional to the normalized activation,
+// with maximum length equal to the spacing between locations.
+func Overlay(pr *Params, imgTsr tensor.Tensor, padWidth int, tsr tensor.Tensor, geom *v1vision.Geom, pools ...*v1vision.Geom) *image.RGBA {
+	img := v1vision.RGBTensorToImage(nil, tensor.AsFloat32(imgTsr), padWidth, pr.TopZero)
+	ny, nx, fn := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(tsr.NumDims()-1)
+	nu := tsr.Len() / (ny * nx * fn)
+	flat := tensor.Reshape(tsr, ny, nx, nu, fn)
+	mx := pr.maxAbs(tsr)
+	isy := img.Bounds().Dy()
+	spc := float32(geom.Spacing.X)
+	for _, pg := range pools {
+		spc *= float32(pg.Spacing.X)
+	}
+	for y := range ny {
+		for x := range nx {
+			pos := OverlayPos(math32.Vec2(float32(x), float32(y)), padWidth, geom, pools...)
+			if !pr.TopZero {
+				pos.Y = float32(isy-1) - pos.Y
+			}
+			for fi := range fn {
+				v := float32(0)
+				for ui := range nu {
+					v = max(v, float32(flat.Float(y, x, ui, fi)))
+				}
+				v = min(v/mx, 1)
+				if v <= 0 {
+					continue
+				}
+				rad := 0.5 * v * spc
+				ang := float32(fi) * math32.Pi / float32(fn)
+				dx := rad * math32.Cos(ang)
+				dy := rad * math32.Sin(ang)
+				if !pr.TopZero {
+					dy = -dy
+				}
+				blendLine(img, pos.X-dx, pos.Y-dy, pos.X+dx, pos.Y+dy, hueColor(float32(fi)/float32(fn)), v)
+			}
+		}
+	}
+	return img
+}
+
+// OverlayPos returns the position in the unpadded image (with Y = 0
+// at the top of the image tensor) of the center of given output location
+// of the given convolution geometry on an image with padWidth padding,
+// after any additional pooling geometries applied to its outputs, in order.
+func OverlayPos(out math32.Vector2, padWidth int, geom *v1vision.Geom, pools ...*v1vision.Geom) math32.Vector2 {
+	pos := out
+	for i := len(pools) - 1; i >= 0; i-- {
+		pg := pools[i]
+		pos = pos.Mul(vec2(pg.Spacing)).Add(vec2(pg.FilterSize).SubScalar(1).MulScalar(0.5))
+	}
+	st := vec2(geom.Border).Sub(vec2(geom.FilterLt)).SubScalar(float32(padWidth))
+	ctr := vec2(geom.FilterSize).SubScalar(1).MulScalar(0.5)
+	return st.Add(ctr).Add(pos.Mul(vec2(geom.Spacing)))
+}
+
+// vec2 returns the float32 version of given int vector.
+func vec2(v slvec.Vector2i) math32.Vector2 {
+	return math32.Vec2(float32(v.X), float32(v.Y))
+}
+
+// GreyImageOf returns the Images entry at given index and data item from
+// [v1vision.V1Vision.Images], for a greyscale image where only the first
+// component is set, as a [RGB][Y][X] tensor with the grey value in all
+// components, for [Overlay].
+func GreyImageOf(vv *v1vision.V1Vision, ii, ni int) *tensor.Float32 {
+	grey := tensor.Reslice(vv.Images, ii, ni, 0)
+	ny, nx := grey.DimSize(0), grey.DimSize(1)
+	tsr := tensor.NewFloat32(3, ny, nx)
+	for y := range ny {
+		for x := range nx {
+			v := grey.Float(y, x)
+			for ci := range 3 {
+				tsr.SetFloat(v, ci, y, x)
+			}
+		}
+	}
+	return tsr
+}
+
+// blendLine draws a line from x0, y0 to x1, y1, blending the color
+// with the existing image colors with given opacity.
+func blendLine(img *image.RGBA, x0, y0, x1, y1 float32, clr color.RGBA, alpha float32) {
+	n := int(max(math32.Abs(x1-x0), math32.Abs(y1-y0))) + 1
+	blend := func(ex, c uint8) uint8 {
+		return toByte(((1-alpha)*float32(ex) + alpha*float32(c)) / 255)
+	}
+	for i := range n + 1 {
+		t := float32(i) / float32(n)
+		x := int(math32.Floor(x0 + t*(x1-x0) + 0.5))
+		y := int(math32.Floor(y0 + t*(y1-y0) + 0.5))
+		if !(image.Point{x, y}.In(img.Rect)) {
+			continue
+		}
+		ex := img.RGBAAt(x, y)
+		img.SetRGBA(x, y, color.RGBA{blend(ex.R, clr.R), blend(ex.G, clr.G), blend(ex.B, clr.B), 255})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// StarFlow returns the net motion at each location from star motion
+// values of shape [Y][X][Polarity][4 * FilterN], where 4 is for
+// Left, Right, Down, Up for each input filter (e.g., one data item
+// of [v1std.MotionPath.Star]), as a [Y][X][2] tensor of dx, dy,
+// with positive values for Right and Up motion respectively.
+// This sums over polarities and filters, as in [v1vision.MotionFlow].
+func StarFlow(star tensor.Tensor) *tensor.Float32 {
+	ny, nx, np, nf := star.DimSize(0), star.DimSize(1), star.DimSize(2), star.DimSize(3)/4
+	flow := tensor.NewFloat32(ny, nx, 2)
+	for y := range ny {
+		for x := range nx {
+			dx, dy := 0.0, 0.0
+			for pi := range np {
+				for fi := range nf {
+					dfo := fi * 4
+					dx += star.Float(y, x, pi, dfo+1) - star.Float(y, x, pi, dfo)
+					dy += star.Float(y, x, pi, dfo+3) - star.Float(y, x, pi, dfo+2)
+				}
+			}
+			flow.SetFloat(dx, y, x, 0)
+			flow.SetFloat(dy, y, x, 1)
+		}
+	}
+	return flow
+}
+
+// Quiver renders motion vectors of shape [Y][X][2] (dx, dy, with positive
+// values for Right and Up, e.g., from [StarFlow] or one data item of
+// [v1std.MotionPath.Flow]) as arrows on top of the image, which is an
+// [RGB][Y][X] tensor with padWidth padding, as in [Overlay].
+// Arrow positions are determined by geom and any pooling geometries,
+// as in [OverlayPos] (e.g., the motion Geom, and FlowGeom for Flow).
+// Arrow length and opacity are proportional to the magnitude normalized
+// by Max (or the max magnitude if 0), with maximum length equal to the
+// spacing between locations, and the color indicates the direction.
+func Quiver(pr *Params, imgTsr tensor.Tensor, padWidth int, flow tensor.Tensor, geom *v1vision.Geom, pools ...*v1vision.Geom) *image.RGBA {
+	img := v1vision.RGBTensorToImage(nil, tensor.AsFloat32(imgTsr), padWidth, pr.TopZero)
+	ny, nx := flow.DimSize(0), flow.DimSize(1)
+	mx := pr.Max
+	if mx == 0 {
+		for y := range ny {
+			for x := range nx {
+				mx = max(mx, flowVec(flow, y, x).Length())
+			}
+		}
+		if mx == 0 {
+			return img
+		}
+	}
+	isy := img.Bounds().Dy()
+	spc := float32(geom.Spacing.X)
+	for _, pg := range pools {
+		spc *= float32(pg.Spacing.X)
+	}
+	for y := range ny {
+		for x := range nx {
+			d := flowVec(flow, y, x)
+			mag := d.Length()
+			v := min(mag/mx, 1)
+			if v <= 0 {
+				continue
+			}
+			pos := OverlayPos(math32.Vec2(float32(x), float32(y)), padWidth, geom, pools...)
+			ang := math32.Atan2(d.Y, d.X)
+			clr := hueColor(ang / (2 * math32.Pi))
+			if !pr.TopZero {
+				pos.Y = float32(isy-1) - pos.Y
+				d.Y = -d.Y
+			}
+			d = d.MulScalar(v * spc / mag)
+			tip := pos.Add(d.MulScalar(0.5))
+			tail := pos.Sub(d.MulScalar(0.5))
+			blendLine(img, tail.X, tail.Y, tip.X, tip.Y, clr, v)
+			hd := d.MulScalar(-0.3)
+			for _, ha := range []float32{-math32.Pi / 6, math32.Pi / 6} {
+				h := tip.Add(hd.Rot(ha, math32.Vector2{}))
+				blendLine(img, tip.X, tip.Y, h.X, h.Y, clr, v)
+			}
+		}
+	}
+	return img
+}
+
+// flowVec returns the dx, dy motion vector at given location.
+func flowVec(flow tensor.Tensor, y, x int) math32.Vector2 {
+	return math32.Vec2(float32(flow.Float(y, x, 0)), float32(flow.Float(y, x, 1)))
+}
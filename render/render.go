@@ -0,0 +1,339 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package render provides headless rendering of v1vision filters,
+intermediate values, outputs, and images to image.RGBA grids
+(montages), which can be saved as PNG files for diagnostics
+in tests, CI, and batch jobs, without requiring a display.
+
+Signed values are rendered with positive (on) as green and negative
+(off) as red, which is also used for the Polarity dimension of Values.
+Orientation-tuned values (e.g., gabor angles) can also be rendered
+as colored line glyphs at each location, or as an Overlay of oriented
+line segments on top of the original image. Motion outputs can be
+rendered with Quiver as arrows over a frame image, and frame sequences
+can be saved as animated GIFs with SaveGIF.
+
+Consistent with the v1vision coordinate system, Y = 0 is rendered at
+the bottom, unless Params.TopZero is set.
+*/
+package render
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"cogentcore.org/core/base/iox/imagex"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// Params are the rendering parameters.
+type Params struct {
+
+	// Scale is the number of pixels per value (i.e., the size of each
+	// value cell). For glyphs, this should be at least 5.
+	Scale int `default:"4"`
+
+	// Gap is the number of pixels between tiles in a grid.
+	Gap int `default:"2"`
+
+	// Max is the absolute value that maps to full intensity.
+	// If 0, the max absolute value of the rendered values is used.
+	Max float32
+
+	// TopZero renders Y = 0 at the top, instead of the bottom.
+	TopZero bool
+
+	// Background is the color of the gaps and empty space.
+	Background color.RGBA
+}
+
+func (pr *Params) Defaults() {
+	pr.Scale = 4
+	pr.Gap = 2
+	pr.Background = color.RGBA{64, 64, 64, 255}
+}
+
+// NewParams returns new default Params.
+func NewParams() *Params {
+	pr := &Params{}
+	pr.Defaults()
+	return pr
+}
+
+// SavePNG saves the given image to a PNG file.
+func SavePNG(img image.Image, filename string) error {
+	return imagex.Save(img, filename)
+}
+
+// Filters renders filters of shape [FilterN][Y][X] to a horizontal row
+// of tiles, one per filter, with positive values green and negative red.
+// Use [FiltersOf] to get the filters for one filter type.
+func Filters(pr *Params, tsr tensor.Tensor) *image.RGBA {
+	fn, ny, nx := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(2)
+	mx := pr.maxAbs(tsr)
+	img := pr.newGrid(1, fn, ny, nx)
+	for fi := range fn {
+		for y := range ny {
+			for x := range nx {
+				pr.setCell(img, 0, fi, ny, nx, y, x, signedColor(float32(tsr.Float(fi, y, x))/mx))
+			}
+		}
+	}
+	return img
+}
+
+// Values renders values of shape [Y][X][Polarity][FilterN] to a
+// horizontal row of tiles, one per filter, with the on (0) polarity
+// as green and the off (1) polarity as red.
+// Use [ValuesOf] to get one entry of [v1vision.V1Vision.Values].
+func Values(pr *Params, tsr tensor.Tensor) *image.RGBA {
+	ny, nx, np, fn := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(2), tsr.DimSize(3)
+	mx := pr.maxAbs(tsr)
+	img := pr.newGrid(1, fn, ny, nx)
+	for fi := range fn {
+		for y := range ny {
+			for x := range nx {
+				on := float32(tsr.Float(y, x, 0, fi)) / mx
+				off := float32(0)
+				if np > 1 {
+					off = float32(tsr.Float(y, x, 1, fi)) / mx
+				}
+				pr.setCell(img, 0, fi, ny, nx, y, x, polarityColor(on, off))
+			}
+		}
+	}
+	return img
+}
+
+// ValuesGlyphs renders values of shape [Y][X][Polarity][FilterN] as
+// orientation glyphs, where the FilterN filters are orientations
+// evenly spaced over 180 degrees, starting with horizontal
+// (as in [gabor.Filter]). At each location, a line is drawn for each
+// orientation, with a distinct color per orientation and intensity
+// given by the max over polarities, so that the strongest orientation
+// is most visible. Scale determines the size of each glyph.
+func ValuesGlyphs(pr *Params, tsr tensor.Tensor) *image.RGBA {
+	ny, nx, np, fn := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(2), tsr.DimSize(3)
+	mx := pr.maxAbs(tsr)
+	sc := pr.Scale
+	img := image.NewRGBA(image.Rect(0, 0, nx*sc, ny*sc))
+	draw.Draw(img, img.Bounds(), image.Black, image.Point{}, draw.Src)
+	rad := 0.5 * float32(sc-1)
+	for y := range ny {
+		for x := range nx {
+			cx := float32(x*sc) + rad
+			cy := float32(pr.flipY(y, ny)*sc) + rad
+			for fi := range fn {
+				v := float32(0)
+				for pi := range np {
+					v = max(v, float32(tsr.Float(y, x, pi, fi)))
+				}
+				v = min(v/mx, 1)
+				if v <= 0 {
+					continue
+				}
+				ang := float32(fi) * math32.Pi / float32(fn)
+				dx := rad * math32.Cos(ang)
+				dy := rad * math32.Sin(ang)
+				if !pr.TopZero {
+					dy = -dy
+				}
+				clr := scaleColor(hueColor(float32(fi)/float32(fn)), v)
+				drawLine(img, cx-dx, cy-dy, cx+dx, cy+dy, clr)
+			}
+		}
+	}
+	return img
+}
+
+// Values4D renders values of shape [PoolY][PoolX][UnitY][UnitX]
+// to a grid of pools, each with UnitY x UnitX values, with positive
+// values green and negative red.
+// Use [Values4DOf] to get one entry of [v1vision.V1Vision.Values4D].
+func Values4D(pr *Params, tsr tensor.Tensor) *image.RGBA {
+	py, px, uy, ux := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(2), tsr.DimSize(3)
+	mx := pr.maxAbs(tsr)
+	img := pr.newGrid(py, px, uy, ux)
+	for gy := range py {
+		ty := pr.flipY(gy, py)
+		for gx := range px {
+			for y := range uy {
+				for x := range ux {
+					clr := signedColor(float32(tsr.Float(gy, gx, y, x)) / mx)
+					pr.setCell(img, ty, gx, uy, ux, y, x, clr)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Image renders an image tensor of shape [RGB][Y][X] to an RGB image,
+// with values in the 0-1 range (Max is not used).
+// Use [ImageOf] to get one entry of [v1vision.V1Vision.Images].
+func Image(pr *Params, tsr tensor.Tensor) *image.RGBA {
+	nc, ny, nx := tsr.DimSize(0), tsr.DimSize(1), tsr.DimSize(2)
+	img := image.NewRGBA(image.Rect(0, 0, nx*pr.Scale, ny*pr.Scale))
+	for y := range ny {
+		for x := range nx {
+			var c [3]uint8
+			for ci := range 3 {
+				v := float32(tsr.Float(min(ci, nc-1), y, x))
+				c[ci] = toByte(v)
+			}
+			pr.fillCell(img, x*pr.Scale, pr.flipY(y, ny)*pr.Scale, color.RGBA{c[0], c[1], c[2], 255})
+		}
+	}
+	return img
+}
+
+// FiltersOf returns the filters of given filter type index from
+// [v1vision.V1Vision.Filters], with given number of filters and
+// size, as a [FilterN][Y][X] tensor view for [Filters].
+func FiltersOf(vv *v1vision.V1Vision, ftyp, fn, size int) tensor.Tensor {
+	return tensor.Reslice(vv.Filters, ftyp, tensor.Slice{Stop: fn}, tensor.Slice{Stop: size}, tensor.Slice{Stop: size})
+}
+
+// ValuesOf returns the Values entry at given index and data item from
+// [v1vision.V1Vision.Values], with given output geometry and number of
+// filters, as a [Y][X][Polarity][FilterN] tensor view for [Values].
+func ValuesOf(vv *v1vision.V1Vision, vi, ni int, geom *v1vision.Geom, fn int) tensor.Tensor {
+	return tensor.Reslice(vv.Values, vi, ni, tensor.Slice{Stop: int(geom.Out.Y)}, tensor.Slice{Stop: int(geom.Out.X)}, tensor.FullAxis, tensor.Slice{Stop: fn})
+}
+
+// Values4DOf returns the Values4D entry at given index and data item from
+// [v1vision.V1Vision.Values4D], with given pool geometry and unit sizes,
+// as a [PoolY][PoolX][UnitY][UnitX] tensor view for [Values4D].
+func Values4DOf(vv *v1vision.V1Vision, vi, ni int, geom *v1vision.Geom, unitY, unitX int) tensor.Tensor {
+	return tensor.Reslice(vv.Values4D, vi, ni, tensor.Slice{Stop: int(geom.Out.Y)}, tensor.Slice{Stop: int(geom.Out.X)}, tensor.Slice{Stop: unitY}, tensor.Slice{Stop: unitX})
+}
+
+// ImageOf returns the Images entry at given index and data item from
+// [v1vision.V1Vision.Images], as a [RGB][Y][X] tensor view for [Image].
+func ImageOf(vv *v1vision.V1Vision, ii, ni int) tensor.Tensor {
+	return tensor.Reslice(vv.Images, ii, ni)
+}
+
+// maxAbs returns the Max value or the max absolute value in tensor
+// if Max is 0, which is 1 if all values are 0.
+func (pr *Params) maxAbs(tsr tensor.Tensor) float32 {
+	if pr.Max > 0 {
+		return pr.Max
+	}
+	mx := float32(0)
+	n := tsr.Len()
+	for i := range n {
+		mx = max(mx, math32.Abs(float32(tsr.Float1D(i))))
+	}
+	if mx == 0 {
+		return 1
+	}
+	return mx
+}
+
+// flipY returns the rendered row for given Y index out of ny.
+func (pr *Params) flipY(y, ny int) int {
+	if pr.TopZero {
+		return y
+	}
+	return ny - 1 - y
+}
+
+// newGrid returns a new image for a grid of gy x gx tiles,
+// each ty x tx cells, filled with the Background color.
+func (pr *Params) newGrid(gy, gx, ty, tx int) *image.RGBA {
+	w := gx*tx*pr.Scale + (gx+1)*pr.Gap
+	h := gy*ty*pr.Scale + (gy+1)*pr.Gap
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), &image.Uniform{pr.Background}, image.Point{}, draw.Src)
+	return img
+}
+
+// setCell sets the cell at Y, X within tile gy, gx of a grid made by
+// newGrid, with Y flipped according to TopZero.
+func (pr *Params) setCell(img *image.RGBA, gy, gx, ty, tx, y, x int, clr color.RGBA) {
+	sc := pr.Scale
+	px := pr.Gap + gx*(tx*sc+pr.Gap) + x*sc
+	py := pr.Gap + gy*(ty*sc+pr.Gap) + pr.flipY(y, ty)*sc
+	pr.fillCell(img, px, py, clr)
+}
+
+// fillCell fills the Scale x Scale cell starting at given pixel.
+func (pr *Params) fillCell(img *image.RGBA, px, py int, clr color.RGBA) {
+	for y := range pr.Scale {
+		for x := range pr.Scale {
+			img.SetRGBA(px+x, py+y, clr)
+		}
+	}
+}
+
+// toByte converts a 0-1 value to a clipped byte value.
+func toByte(v float32) uint8 {
+	return uint8(255 * min(max(v, 0), 1))
+}
+
+// signedColor returns green for positive and red for negative
+// normalized values.
+func signedColor(v float32) color.RGBA {
+	if v >= 0 {
+		return color.RGBA{0, toByte(v), 0, 255}
+	}
+	return color.RGBA{toByte(-v), 0, 0, 255}
+}
+
+// polarityColor returns a color with on as green and off as red.
+func polarityColor(on, off float32) color.RGBA {
+	return color.RGBA{toByte(off), toByte(on), 0, 255}
+}
+
+// hueColor returns a fully saturated color for given hue in 0-1 range.
+func hueColor(h float32) color.RGBA {
+	h6 := 6 * (h - math32.Floor(h))
+	i := int(h6)
+	f := h6 - float32(i)
+	var r, g, b float32
+	switch i {
+	case 0:
+		r, g, b = 1, f, 0
+	case 1:
+		r, g, b = 1-f, 1, 0
+	case 2:
+		r, g, b = 0, 1, f
+	case 3:
+		r, g, b = 0, 1-f, 1
+	case 4:
+		r, g, b = f, 0, 1
+	default:
+		r, g, b = 1, 0, 1-f
+	}
+	return color.RGBA{toByte(r), toByte(g), toByte(b), 255}
+}
+
+// scaleColor returns the color scaled by given 0-1 intensity.
+func scaleColor(clr color.RGBA, v float32) color.RGBA {
+	return color.RGBA{toByte(v * float32(clr.R) / 255), toByte(v * float32(clr.G) / 255), toByte(v * float32(clr.B) / 255), 255}
+}
+
+// drawLine draws a line between given points, taking the max with
+// existing colors so that overlapping lines combine.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 float32, clr color.RGBA) {
+	n := int(max(math32.Abs(x1-x0), math32.Abs(y1-y0))) + 1
+	for i := range n + 1 {
+		t := float32(i) / float32(n)
+		x := int(math32.Floor(x0 + t*(x1-x0) + 0.5))
+		y := int(math32.Floor(y0 + t*(y1-y0) + 0.5))
+		if !(image.Point{x, y}.In(img.Rect)) {
+			continue
+		}
+		ex := img.RGBAAt(x, y)
+		img.SetRGBA(x, y, color.RGBA{max(ex.R, clr.R), max(ex.G, clr.G), max(ex.B, clr.B), 255})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/base/iox/imagex"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1std"
+	"github.com/emer/v1vision/v1vision"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	var vi v1std.V1cGrey
+	var img v1std.Image
+
+	vi.Defaults()
+	vi.GPU = false
+	img.Defaults()
+	vi.Config(1, img.Size)
+	im, _, err := imagex.Open("../v1vision/testdata/side-tee-128.png")
+	assert.NoError(t, err)
+	vi.RunImages(&img, im)
+
+	pr := NewParams()
+	dir := t.TempDir()
+	nang := vi.V1sGabor.NAngles
+	sz := vi.V1sGabor.Size
+
+	fimg := Filters(pr, FiltersOf(&vi.V1, 0, nang, sz))
+	assert.Equal(t, image.Pt(nang*sz*pr.Scale+(nang+1)*pr.Gap, sz*pr.Scale+2*pr.Gap), fimg.Bounds().Size())
+	assert.Equal(t, pr.Background, fimg.RGBAAt(0, 0))
+	assert.NoError(t, SavePNG(fimg, filepath.Join(dir, "filters.png")))
+
+	ny, nx := int(vi.V1sGeom.Out.Y), int(vi.V1sGeom.Out.X)
+	vals := ValuesOf(&vi.V1, 0, 0, &vi.V1sGeom, nang)
+	assert.Equal(t, []int{ny, nx, 2, nang}, vals.ShapeSizes())
+	vimg := Values(pr, vals)
+	assert.Equal(t, image.Pt(nang*nx*pr.Scale+(nang+1)*pr.Gap, ny*pr.Scale+2*pr.Gap), vimg.Bounds().Size())
+	assert.NoError(t, SavePNG(vimg, filepath.Join(dir, "values.png")))
+
+	pr.Scale = 7
+	gimg := ValuesGlyphs(pr, vals)
+	assert.Equal(t, image.Pt(nx*pr.Scale, ny*pr.Scale), gimg.Bounds().Size())
+	assert.NoError(t, SavePNG(gimg, filepath.Join(dir, "glyphs.png")))
+	pr.Scale = 4
+
+	oy, ox := int(vi.V1cGeom.Out.Y), int(vi.V1cGeom.Out.X)
+	out := Values4DOf(&vi.V1, 0, 0, &vi.V1cGeom, 5, nang)
+	oimg := Values4D(pr, out)
+	assert.Equal(t, image.Pt(ox*nang*pr.Scale+(ox+1)*pr.Gap, oy*5*pr.Scale+(oy+1)*pr.Gap), oimg.Bounds().Size())
+	assert.NoError(t, SavePNG(oimg, filepath.Join(dir, "output.png")))
+
+	pad := int(vi.V1sGeom.Border.X)
+	simg := Overlay(pr, GreyImageOf(&vi.V1, 0, 0), pad, vals, &vi.V1sGeom)
+	assert.Equal(t, img.Size, simg.Bounds().Size())
+	assert.NoError(t, SavePNG(simg, filepath.Join(dir, "overlay-v1s.png")))
+	cimg := Overlay(pr, GreyImageOf(&vi.V1, 0, 0), pad, out, &vi.V1sGeom, &vi.V1cGeom)
+	assert.Equal(t, img.Size, cimg.Bounds().Size())
+	assert.NoError(t, SavePNG(cimg, filepath.Join(dir, "overlay-v1c.png")))
+
+	iimg := Image(pr, ImageOf(&vi.V1, 0, 0))
+	isz := vi.V1.Images.ShapeSizes()
+	assert.Equal(t, image.Pt(isz[4]*pr.Scale, isz[3]*pr.Scale), iimg.Bounds().Size())
+	assert.NoError(t, SavePNG(iimg, filepath.Join(dir, "image.png")))
+}
+
+func TestColors(t *testing.T) {
+	pr := NewParams()
+	pr.Scale = 1
+	pr.Gap = 0
+	vals := tensor.NewFloat32(1, 2, 2, 1)
+	vals.Set(1, 0, 0, 0, 0)   // on
+	vals.Set(0.5, 0, 1, 1, 0) // off
+	img := Values(pr, vals)
+	// Y = 0 is at the bottom
+	assert.Equal(t, color.RGBA{0, 255, 0, 255}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{127, 0, 0, 255}, img.RGBAAt(1, 0))
+
+	pr.TopZero = true
+	v4 := tensor.NewFloat32(2, 1, 1, 1)
+	v4.Set(-1, 0, 0, 0, 0)
+	v4.Set(1, 1, 0, 0, 0)
+	img = Values4D(pr, v4)
+	assert.Equal(t, color.RGBA{255, 0, 0, 255}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{0, 255, 0, 255}, img.RGBAAt(0, 1))
+}
+
+func TestOverlay(t *testing.T) {
+	pr := NewParams()
+	var geom v1vision.Geom
+	geom.SetImage(math32.Vec2i(3, 3), math32.Vec2i(4, 4), math32.Vec2i(5, 5), image.Pt(16, 16))
+	pool := v1vision.Geom{}
+	pool.SetFilter(math32.Vec2i(0, 0), math32.Vec2i(2, 2), math32.Vec2i(2, 2), geom.Out.V())
+	assert.Equal(t, math32.Vec2(4, 8), OverlayPos(math32.Vec2(1, 2), 3, &geom))
+	assert.Equal(t, math32.Vec2(2, 2), OverlayPos(math32.Vec2(0, 0), 3, &geom, &pool))
+
+	imt := tensor.NewFloat32(3, 22, 22)
+	vals := tensor.NewFloat32(int(geom.Out.Y), int(geom.Out.X), 2, 4)
+	vals.Set(1, 1, 2, 1, 0) // horizontal
+	vals.Set(1, 2, 1, 0, 2) // vertical
+	pr.TopZero = true
+	img := Overlay(pr, imt, 3, vals, &geom)
+	assert.Equal(t, image.Pt(16, 16), img.Bounds().Size())
+	red := color.RGBA{255, 0, 0, 255}
+	assert.Equal(t, red, img.RGBAAt(8, 4))
+	assert.Equal(t, red, img.RGBAAt(10, 4))
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(8, 6))
+	cyan := hueColor(0.5)
+	assert.Equal(t, cyan, img.RGBAAt(4, 8))
+	assert.Equal(t, cyan, img.RGBAAt(4, 10))
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(6, 8))
+}
+
+func TestQuiver(t *testing.T) {
+	pr := NewParams()
+	var geom v1vision.Geom
+	geom.SetImage(math32.Vec2i(3, 3), math32.Vec2i(4, 4), math32.Vec2i(5, 5), image.Pt(16, 16))
+
+	star := tensor.NewFloat32(int(geom.Out.Y), int(geom.Out.X), 2, 4)
+	star.Set(1, 1, 2, 0, 1)   // right
+	star.Set(0.5, 1, 2, 1, 0) // left, other polarity
+	star.Set(1, 2, 1, 1, 3)   // up
+	flow := StarFlow(star)
+	assert.Equal(t, float32(0.5), flow.Value(1, 2, 0))
+	assert.Equal(t, float32(0), flow.Value(1, 2, 1))
+	assert.Equal(t, float32(1), flow.Value(2, 1, 1))
+
+	imt := tensor.NewFloat32(3, 22, 22)
+	pr.TopZero = true
+	frames := make([]image.Image, 2)
+	for i := range frames {
+		img := Quiver(pr, imt, 3, flow, &geom)
+		assert.Equal(t, image.Pt(16, 16), img.Bounds().Size())
+		frames[i] = img
+	}
+	red := color.RGBA{255, 0, 0, 255}
+	img := frames[0].(*image.RGBA)
+	// right arrow at 8, 4, with half length
+	assert.Equal(t, red.G, img.RGBAAt(9, 4).G)
+	assert.Greater(t, img.RGBAAt(9, 4).R, uint8(100))
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(6, 4))
+	// up arrow at 4, 8, pointing to higher Y with TopZero
+	up := hueColor(0.25)
+	assert.Equal(t, up, img.RGBAAt(4, 10))
+	assert.Equal(t, up, img.RGBAAt(4, 6))
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteGIF(&buf, 10, frames...))
+	anim, err := gif.DecodeAll(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(anim.Image))
+	assert.Equal(t, []int{10, 10}, anim.Delay)
+	assert.NoError(t, SaveGIF(filepath.Join(t.TempDir(), "quiver.gif"), 10, frames...))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stimulus
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+)
+
+// Bar is a rectangular bar moving at a constant velocity,
+// optionally wrapping around the edges of the image.
+type Bar struct {
+	// Length is the length of the bar along its Angle, in pixels.
+	Length float32 `default:"16"`
+
+	// Width is the width of the bar, in pixels.
+	Width float32 `default:"4"`
+
+	// Angle is the orientation of the long axis of the bar,
+	// in degrees, where 0 is horizontal.
+	Angle float32 `default:"90"`
+
+	// Start is the position of the center of the bar at time 0,
+	// relative to the center of the image, in pixels.
+	Start math32.Vector2
+
+	// Velocity is the motion of the bar, in pixels per frame.
+	Velocity math32.Vector2
+
+	// Wrap wraps the bar around the edges of the image.
+	Wrap bool
+
+	// Lum is the luminance of the bar.
+	Lum float32 `default:"1"`
+
+	// Background is the background luminance.
+	Background float32 `default:"0"`
+}
+
+func (br *Bar) Defaults() {
+	br.Length = 16
+	br.Width = 4
+	br.Angle = 90
+	br.Start = math32.Vector2{}
+	br.Velocity = math32.Vec2(1, 0)
+	br.Wrap = false
+	br.Lum = 1
+	br.Background = 0
+}
+
+// Pos returns the position of the center of the bar at time t,
+// relative to the center of the image.
+func (br *Bar) Pos(t float32) math32.Vector2 {
+	return br.Start.Add(br.Velocity.MulScalar(t))
+}
+
+func (br *Bar) Render(tsr *tensor.Float32, t float32) {
+	ny, nx := tsr.DimSize(0), tsr.DimSize(1)
+	sz := math32.Vec2(float32(nx), float32(ny))
+	ctr := center(tsr)
+	bpos := br.Pos(t)
+	along := dirVec(br.Angle)
+	perp := dirVec(br.Angle + 90)
+	for y := range ny {
+		for x := range nx {
+			d := math32.Vec2(float32(x), float32(y)).Sub(ctr).Sub(bpos)
+			if br.Wrap {
+				d.X -= sz.X * math32.Round(d.X/sz.X)
+				d.Y -= sz.Y * math32.Round(d.Y/sz.Y)
+			}
+			v := br.Background
+			if math32.Abs(d.Dot(along)) <= 0.5*br.Length && math32.Abs(d.Dot(perp)) <= 0.5*br.Width {
+				v = br.Lum
+			}
+			tsr.Set(v, y, x)
+		}
+	}
+}
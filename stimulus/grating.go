@@ -0,0 +1,133 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stimulus
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+)
+
+// Grating is a drifting sinusoidal or square-wave grating,
+// optionally within a circular aperture.
+type Grating struct {
+	// Angle is the orientation of the bars of the grating, in degrees,
+	// where 0 is horizontal, as in [gabor.Filter] angles.
+	Angle float32 `default:"0"`
+
+	// Freq is the spatial frequency, in cycles per pixel.
+	Freq float32 `default:"0.0625"`
+
+	// Phase is the spatial phase at the center of the image at time 0,
+	// in degrees, where 0 is a sine wave starting at the Mean.
+	Phase float32
+
+	// Contrast is the Michelson contrast, as the amplitude of the
+	// luminance modulation relative to the Mean.
+	Contrast float32 `default:"1"`
+
+	// Mean is the mean luminance.
+	Mean float32 `default:"0.5"`
+
+	// Speed is the drift speed, in pixels per frame, orthogonal to the
+	// bars, in the direction of Angle + 90 degrees (e.g., upward for
+	// horizontal bars). Negative values drift in the opposite direction.
+	Speed float32
+
+	// Square renders a square-wave grating instead of a sinusoid.
+	Square bool
+
+	// Radius is the radius of a circular aperture around the center,
+	// in pixels, outside of which the luminance is the Mean. 0 = full field.
+	Radius float32
+}
+
+func (gr *Grating) Defaults() {
+	gr.Angle = 0
+	gr.Freq = 0.0625
+	gr.Phase = 0
+	gr.Contrast = 1
+	gr.Mean = 0.5
+	gr.Speed = 0
+	gr.Square = false
+	gr.Radius = 0
+}
+
+// Period returns the period of the grating in pixels.
+func (gr *Grating) Period() float32 {
+	return 1 / gr.Freq
+}
+
+// TemporalFreq returns the temporal frequency of the drift,
+// in cycles per frame.
+func (gr *Grating) TemporalFreq() float32 {
+	return gr.Speed * gr.Freq
+}
+
+// Modulation returns the luminance modulation of the grating in the
+// -1..1 range at given position relative to the center, at time t.
+func (gr *Grating) Modulation(pos math32.Vector2, t float32) float32 {
+	if gr.Radius > 0 && pos.Length() > gr.Radius {
+		return 0
+	}
+	perp := dirVec(gr.Angle + 90)
+	ph := 2*math32.Pi*gr.Freq*(pos.Dot(perp)-gr.Speed*t) + math32.DegToRad(gr.Phase)
+	v := math32.Sin(ph)
+	if gr.Square {
+		switch {
+		case v > 1.0e-6:
+			v = 1
+		case v < -1.0e-6:
+			v = -1
+		default:
+			v = 0
+		}
+	}
+	return v
+}
+
+func (gr *Grating) Render(tsr *tensor.Float32, t float32) {
+	ctr := center(tsr)
+	for y := range tsr.DimSize(0) {
+		for x := range tsr.DimSize(1) {
+			pos := math32.Vec2(float32(x), float32(y)).Sub(ctr)
+			tsr.Set(clamp(gr.Mean*(1+gr.Contrast*gr.Modulation(pos, t))), y, x)
+		}
+	}
+}
+
+// Plaid is the sum of two drifting gratings, with the Mean and
+// Radius of the first grating. For equal contrasts, each grating
+// should have a Contrast of at most 0.5 to avoid clipping.
+type Plaid struct {
+	// A is the first grating.
+	A Grating
+
+	// B is the second grating.
+	B Grating
+}
+
+func (pl *Plaid) Defaults() {
+	pl.A.Defaults()
+	pl.A.Angle = 45
+	pl.A.Contrast = 0.5
+	pl.B.Defaults()
+	pl.B.Angle = 135
+	pl.B.Contrast = 0.5
+}
+
+func (pl *Plaid) Render(tsr *tensor.Float32, t float32) {
+	ctr := center(tsr)
+	for y := range tsr.DimSize(0) {
+		for x := range tsr.DimSize(1) {
+			pos := math32.Vec2(float32(x), float32(y)).Sub(ctr)
+			if pl.A.Radius > 0 && pos.Length() > pl.A.Radius {
+				tsr.Set(pl.A.Mean, y, x)
+				continue
+			}
+			m := pl.A.Contrast*pl.A.Modulation(pos, t) + pl.B.Contrast*pl.B.Modulation(pos, t)
+			tsr.Set(clamp(pl.A.Mean*(1+m)), y, x)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stimulus
+
+import (
+	"math/rand/v2"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+)
+
+// Noise is Gaussian white noise, with a new random pattern on each
+// frame (unless Frozen), as independent blocks of pixels, e.g.,
+// for reverse-correlation receptive field mapping.
+type Noise struct {
+	// Mean is the mean luminance.
+	Mean float32 `default:"0.5"`
+
+	// Sigma is the standard deviation of the luminance.
+	Sigma float32 `default:"0.15"`
+
+	// Block is the size of the square blocks of pixels that have
+	// the same random value.
+	Block int `default:"1"`
+
+	// Frozen uses the same random pattern on every frame.
+	Frozen bool
+
+	// Seed is the random seed that determines the noise patterns.
+	Seed uint64
+}
+
+func (ns *Noise) Defaults() {
+	ns.Mean = 0.5
+	ns.Sigma = 0.15
+	ns.Block = 1
+	ns.Frozen = false
+}
+
+func (ns *Noise) Render(tsr *tensor.Float32, t float32) {
+	ny, nx := tsr.DimSize(0), tsr.DimSize(1)
+	frame := uint64(0)
+	if !ns.Frozen {
+		frame = uint64(int64(math32.Floor(t)))
+	}
+	rnd := rand.New(rand.NewPCG(ns.Seed, frame))
+	bs := max(ns.Block, 1)
+	for by := 0; by < ny; by += bs {
+		for bx := 0; bx < nx; bx += bs {
+			v := clamp(ns.Mean + ns.Sigma*float32(rnd.NormFloat64()))
+			for y := by; y < min(by+bs, ny); y++ {
+				for x := bx; x < min(bx+bs, nx); x++ {
+					tsr.Set(v, y, x)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package stimulus provides parametric synthetic visual stimuli:
+drifting sinusoidal and square-wave gratings, plaids, random-dot
+kinematograms (RDK), moving bars, and Gaussian noise, for measuring
+tuning curves and for regression tests of v1vision filters.
+
+Stimuli are rendered at a given time (in frames) as greyscale luminance
+values in the 0-1 range, and are fully determined by their parameters
+(including any random Seed), so that any frame can be regenerated
+exactly. Consistent with the v1vision coordinate system, positions and
+directions are in pixels with Y increasing upward, relative to the
+center of the image, and rendered tensors have Y = 0 at the bottom.
+Angles are in degrees, counter-clockwise from horizontal (rightward).
+*/
+package stimulus
+
+//go:generate core generate -add-types
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// Stimulus is a parametric visual stimulus that can be rendered
+// at any point in time.
+type Stimulus interface {
+	// Render renders the stimulus at given time, in frames, into the
+	// given [Y][X] tensor, as luminance values in the 0-1 range,
+	// with Y = 0 at the bottom.
+	Render(tsr *tensor.Float32, t float32)
+}
+
+// Tensor returns the stimulus rendered at given time, in frames,
+// as a new [Y][X] tensor of given size, with Y = 0 at the bottom.
+func Tensor(st Stimulus, size image.Point, t float32) *tensor.Float32 {
+	tsr := tensor.NewFloat32(size.Y, size.X)
+	st.Render(tsr, t)
+	return tsr
+}
+
+// Image returns the stimulus rendered at given time, in frames,
+// as a greyscale image of given size.
+func Image(st Stimulus, size image.Point, t float32) *image.Gray {
+	tsr := Tensor(st, size, t)
+	img := image.NewGray(image.Rectangle{Max: size})
+	for y := range size.Y {
+		sy := size.Y - 1 - y
+		for x := range size.X {
+			img.Pix[y*img.Stride+x] = uint8(math32.Round(255 * tsr.Value(sy, x)))
+		}
+	}
+	return img
+}
+
+// Frames returns n successive frames of the stimulus, at times 0..n-1,
+// as greyscale images of given size, e.g., for [v1std.Image] RunImages
+// methods, or render.SaveGIF.
+func Frames(st Stimulus, size image.Point, n int) []image.Image {
+	frames := make([]image.Image, n)
+	for i := range n {
+		frames[i] = Image(st, size, float32(i))
+	}
+	return frames
+}
+
+// SetImages renders the given stimuli at given time, in frames, directly
+// into [v1vision.V1Vision.Images] at given index, one for each data-parallel
+// item, with the luminance in all three RGB components, and padWidth
+// padding on all sides, which is set to 0 (as in [v1vision.RGBToTensor]).
+// size is the image size exclusive of the padding.
+func SetImages(vv *v1vision.V1Vision, idx, padWidth int, size image.Point, t float32, sts ...Stimulus) error {
+	if len(sts) != vv.NData {
+		return fmt.Errorf("stimulus.SetImages: number of stimuli: %d != NData: %d", len(sts), vv.NData)
+	}
+	isz := vv.Images.ShapeSizes()
+	if size.Y+2*padWidth > isz[3] || size.X+2*padWidth > isz[4] {
+		return fmt.Errorf("stimulus.SetImages: size: %v with padding: %d does not fit in Images: %v", size, padWidth, isz[3:])
+	}
+	tsr := vv.Images.SubSpace(idx).(*tensor.Float32)
+	tensor.SetAllFloat64(tsr, 0)
+	st := tensor.NewFloat32(size.Y, size.X)
+	for ni, s := range sts {
+		s.Render(st, t)
+		for y := range size.Y {
+			for x := range size.X {
+				v := st.Value(y, x)
+				for ci := range 3 {
+					tsr.Set(v, ni, ci, y+padWidth, x+padWidth)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// center returns the position of the center of the tensor,
+// in pixel coordinates.
+func center(tsr *tensor.Float32) math32.Vector2 {
+	return math32.Vec2(float32(tsr.DimSize(1)-1), float32(tsr.DimSize(0)-1)).MulScalar(0.5)
+}
+
+// clamp returns the value clamped to the 0-1 range.
+func clamp(v float32) float32 {
+	return math32.Clamp(v, 0, 1)
+}
+
+// dirVec returns the unit vector for given angle in degrees.
+func dirVec(angle float32) math32.Vector2 {
+	rad := math32.DegToRad(angle)
+	return math32.Vec2(math32.Cos(rad), math32.Sin(rad))
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stimulus
+
+import (
+	"image"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/stats/stats"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+	"github.com/stretchr/testify/assert"
+)
+
+var size = image.Point{64, 48}
+
+func TestGrating(t *testing.T) {
+	var gr Grating
+	gr.Defaults()
+	gr.Freq = 0.125 // period of 8 pixels
+	tsr := Tensor(&gr, size, 0)
+	for y := range size.Y {
+		for x := range size.X {
+			assert.InDelta(t, tsr.Value(y, 0), tsr.Value(y, x), 1.0e-5)
+			if y+8 < size.Y {
+				assert.InDelta(t, tsr.Value(y, x), tsr.Value(y+8, x), 1.0e-4)
+			}
+		}
+	}
+	assert.InDelta(t, 0.5, stats.Mean(tensor.As1D(tsr)).Float1D(0), 1.0e-4)
+	assert.InDelta(t, 1, stats.Max(tensor.As1D(tsr)).Float1D(0), 0.05) // sampled off-peak
+	assert.InDelta(t, 0, stats.Min(tensor.As1D(tsr)).Float1D(0), 0.05)
+
+	// drifting upward by 2 pixels per frame
+	gr.Speed = 2
+	tsr3 := Tensor(&gr, size, 3)
+	for y := range size.Y - 6 {
+		assert.InDelta(t, tsr.Value(y, 0), tsr3.Value(y+6, 0), 1.0e-4)
+	}
+
+	gr.Speed = 0
+	gr.Angle = 90
+	gr.Square = true
+	gr.Phase = 45
+	gr.Contrast = 0.5
+	tsr = Tensor(&gr, size, 0)
+	for y := range size.Y {
+		for x := range size.X {
+			assert.InDelta(t, tsr.Value(0, x), tsr.Value(y, x), 1.0e-5)
+			v := tsr.Value(y, x)
+			assert.True(t, v == 0.25 || v == 0.75)
+		}
+	}
+
+	gr.Radius = 10
+	tsr = Tensor(&gr, size, 0)
+	assert.Equal(t, float32(0.5), tsr.Value(0, 0))
+}
+
+func TestPlaid(t *testing.T) {
+	var pl Plaid
+	pl.Defaults()
+	tsr := Tensor(&pl, size, 0)
+	ctr := center(tsr)
+	for y := range size.Y {
+		for x := range size.X {
+			pos := math32.Vec2(float32(x), float32(y)).Sub(ctr)
+			m := 0.5*pl.A.Modulation(pos, 0) + 0.5*pl.B.Modulation(pos, 0)
+			assert.InDelta(t, 0.5*(1+m), tsr.Value(y, x), 1.0e-5)
+		}
+	}
+	assert.LessOrEqual(t, stats.Max(tensor.As1D(tsr)).Float1D(0), 1.0)
+	assert.GreaterOrEqual(t, stats.Min(tensor.As1D(tsr)).Float1D(0), 0.0)
+}
+
+func TestRDK(t *testing.T) {
+	var rd RDK
+	rd.Defaults()
+	rd.Seed = 3
+	rd.Direction = 30
+	sz := math32.Vec2(float32(size.X), float32(size.Y))
+	vel := dirVec(rd.Direction).MulScalar(rd.Speed)
+	for di := range rd.NDots {
+		p0 := rd.DotPos(di, sz, 0)
+		p5 := rd.DotPos(di, sz, 5)
+		d := p5.Sub(p0).Sub(vel.MulScalar(5))
+		d.X -= sz.X * math32.Round(d.X/sz.X)
+		d.Y -= sz.Y * math32.Round(d.Y/sz.Y)
+		assert.InDelta(t, 0, d.Length(), 1.0e-3)
+	}
+	tsr := Tensor(&rd, size, 5)
+	assert.Equal(t, tsr.Values, Tensor(&rd, size, 5).Values)
+	assert.Greater(t, stats.Sum(tensor.As1D(tsr)).Float1D(0), 0.0)
+
+	// with lifetime 4, dots are replotted once within 4 frames,
+	// and random directions do not move coherently.
+	rd.Lifetime = 4
+	rd.Coherence = 0
+	ncoh := 0
+	for di := range rd.NDots {
+		p0 := rd.DotPos(di, sz, 0)
+		p4 := rd.DotPos(di, sz, 4)
+		d := p4.Sub(p0).Sub(vel.MulScalar(4))
+		d.X -= sz.X * math32.Round(d.X/sz.X)
+		d.Y -= sz.Y * math32.Round(d.Y/sz.Y)
+		if d.Length() < 1.0e-3 {
+			ncoh++
+		}
+	}
+	assert.Less(t, ncoh, rd.NDots/10)
+}
+
+func TestBar(t *testing.T) {
+	var br Bar
+	br.Defaults()
+	br.Start = math32.Vec2(-10, 0)
+	tsr := Tensor(&br, size, 0)
+	assert.InDelta(t, br.Length*br.Width, stats.Sum(tensor.As1D(tsr)).Float1D(0), float64(br.Length+br.Width))
+
+	// vertical bar moves right by 1 per frame
+	tsr4 := Tensor(&br, size, 4)
+	for y := range size.Y {
+		for x := range size.X - 4 {
+			assert.Equal(t, tsr.Value(y, x), tsr4.Value(y, x+4))
+		}
+	}
+
+	br.Wrap = true
+	br.Start = math32.Vec2(float32(size.X)/2, 0)
+	tsr = Tensor(&br, size, 0)
+	assert.InDelta(t, br.Length*br.Width, stats.Sum(tensor.As1D(tsr)).Float1D(0), float64(br.Length+br.Width))
+	assert.Equal(t, float32(1), tsr.Value(size.Y/2, 0))
+}
+
+func TestNoise(t *testing.T) {
+	var ns Noise
+	ns.Defaults()
+	ns.Block = 2
+	tsr := Tensor(&ns, size, 0)
+	assert.InDelta(t, ns.Mean, stats.Mean(tensor.As1D(tsr)).Float1D(0), 0.02)
+	assert.InDelta(t, ns.Sigma, stats.Std(tensor.As1D(tsr)).Float1D(0), 0.02)
+	for y := 0; y < size.Y; y += 2 {
+		for x := 0; x < size.X; x += 2 {
+			assert.Equal(t, tsr.Value(y, x), tsr.Value(y+1, x+1))
+		}
+	}
+	assert.Equal(t, tsr.Values, Tensor(&ns, size, 0.5).Values)
+	assert.NotEqual(t, tsr.Values, Tensor(&ns, size, 1).Values)
+	ns.Frozen = true
+	assert.Equal(t, Tensor(&ns, size, 0).Values, Tensor(&ns, size, 1).Values)
+}
+
+func TestSetImages(t *testing.T) {
+	var gr Grating
+	gr.Defaults()
+	var br Bar
+	br.Defaults()
+
+	img := Image(&br, size, 0)
+	tsr := Tensor(&br, size, 0)
+	for y := range size.Y {
+		for x := range size.X {
+			assert.Equal(t, uint8(255*tsr.Value(size.Y-1-y, x)), img.GrayAt(x, y).Y)
+		}
+	}
+	assert.Len(t, Frames(&br, size, 3), 3)
+
+	pad := 4
+	var vv v1vision.V1Vision
+	vv.Init(2)
+	vv.NewImage(math32.Vec2i(size.X+2*pad, size.Y+2*pad))
+	assert.Error(t, SetImages(&vv, 0, pad, size, 0, &gr))
+	assert.Error(t, SetImages(&vv, 0, pad+1, size, 0, &gr, &br))
+	assert.NoError(t, SetImages(&vv, 0, pad, size, 0, &gr, &br))
+	gtsr := Tensor(&gr, size, 0)
+	for ni, st := range []*tensor.Float32{gtsr, tsr} {
+		for ci := range 3 {
+			assert.Equal(t, float32(0), vv.Images.Value(0, ni, ci, 0, 0))
+			for y := range size.Y {
+				for x := range size.X {
+					assert.Equal(t, st.Value(y, x), vv.Images.Value(0, ni, ci, y+pad, x+pad))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package stimulus
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.Bar", IDName: "bar", Doc: "Bar is a rectangular bar moving at a constant velocity,\noptionally wrapping around the edges of the image.", Fields: []types.Field{{Name: "Length", Doc: "Length is the length of the bar along its Angle, in pixels."}, {Name: "Width", Doc: "Width is the width of the bar, in pixels."}, {Name: "Angle", Doc: "Angle is the orientation of the long axis of the bar,\nin degrees, where 0 is horizontal."}, {Name: "Start", Doc: "Start is the position of the center of the bar at time 0,\nrelative to the center of the image, in pixels."}, {Name: "Velocity", Doc: "Velocity is the motion of the bar, in pixels per frame."}, {Name: "Wrap", Doc: "Wrap wraps the bar around the edges of the image."}, {Name: "Lum", Doc: "Lum is the luminance of the bar."}, {Name: "Background", Doc: "Background is the background luminance."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.Grating", IDName: "grating", Doc: "Grating is a drifting sinusoidal or square-wave grating,\noptionally within a circular aperture.", Fields: []types.Field{{Name: "Angle", Doc: "Angle is the orientation of the bars of the grating, in degrees,\nwhere 0 is horizontal, as in [gabor.Filter] angles."}, {Name: "Freq", Doc: "Freq is the spatial frequency, in cycles per pixel."}, {Name: "Phase", Doc: "Phase is the spatial phase at the center of the image at time 0,\nin degrees, where 0 is a sine wave starting at the Mean."}, {Name: "Contrast", Doc: "Contrast is the Michelson contrast, as the amplitude of the\nluminance modulation relative to the Mean."}, {Name: "Mean", Doc: "Mean is the mean luminance."}, {Name: "Speed", Doc: "Speed is the drift speed, in pixels per frame, orthogonal to the\nbars, in the direction of Angle + 90 degrees (e.g., upward for\nhorizontal bars). Negative values drift in the opposite direction."}, {Name: "Square", Doc: "Square renders a square-wave grating instead of a sinusoid."}, {Name: "Radius", Doc: "Radius is the radius of a circular aperture around the center,\nin pixels, outside of which the luminance is the Mean. 0 = full field."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.Plaid", IDName: "plaid", Doc: "Plaid is the sum of two drifting gratings, with the Mean and\nRadius of the first grating. For equal contrasts, each grating\nshould have a Contrast of at most 0.5 to avoid clipping.", Fields: []types.Field{{Name: "A", Doc: "A is the first grating."}, {Name: "B", Doc: "B is the second grating."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.Noise", IDName: "noise", Doc: "Noise is Gaussian white noise, with a new random pattern on each\nframe (unless Frozen), as independent blocks of pixels, e.g.,\nfor reverse-correlation receptive field mapping.", Fields: []types.Field{{Name: "Mean", Doc: "Mean is the mean luminance."}, {Name: "Sigma", Doc: "Sigma is the standard deviation of the luminance."}, {Name: "Block", Doc: "Block is the size of the square blocks of pixels that have\nthe same random value."}, {Name: "Frozen", Doc: "Frozen uses the same random pattern on every frame."}, {Name: "Seed", Doc: "Seed is the random seed that determines the noise patterns."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.RDK", IDName: "rdk", Doc: "RDK is a random-dot kinematogram, where a Coherence proportion\nof the dots move in the same Direction, and the rest move in\nrandom directions. Dot positions are determined by the Seed, so\nthat each frame can be rendered independently, and dots wrap\naround the edges of the image.", Fields: []types.Field{{Name: "NDots", Doc: "NDots is the number of dots."}, {Name: "Radius", Doc: "Radius is the radius of each dot, in pixels."}, {Name: "Coherence", Doc: "Coherence is the probability that each dot moves in Direction,\nrather than a random direction, in each lifetime."}, {Name: "Direction", Doc: "Direction is the direction of coherent motion, in degrees,\ncounter-clockwise from rightward."}, {Name: "Speed", Doc: "Speed is the speed of all dots, in pixels per frame."}, {Name: "Lifetime", Doc: "Lifetime is the number of frames after which each dot is replotted\nat a new random location, with a new coherent or random direction.\nThe lifetimes of the dots are staggered. 0 = infinite."}, {Name: "Dot", Doc: "Dot is the luminance of the dots."}, {Name: "Background", Doc: "Background is the background luminance."}, {Name: "Seed", Doc: "Seed is the random seed that determines the dot positions and\ndirections."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/stimulus.Stimulus", IDName: "stimulus", Doc: "Stimulus is a parametric visual stimulus that can be rendered\nat any point in time.", Methods: []types.Method{{Name: "Render", Doc: "Render renders the stimulus at given time, in frames, into the\ngiven [Y][X] tensor, as luminance values in the 0-1 range,\nwith Y = 0 at the bottom.", Args: []string{"tsr", "t"}}}})
@@ -0,0 +1,44 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package temporal provides parameters for temporal filtering of
+successive video frames, into sustained (parvocellular-like) and
+transient (magnocellular-like) channels.
+*/
+package temporal
+
+//go:generate core generate -add-types
+
+// Params has the temporal filtering parameters for computing
+// sustained and transient channels from successive frames.
+type Params struct {
+
+	// SustainedTau is the time constant (in frames) of the exponential
+	// low-pass filter for the sustained channel.
+	SustainedTau float32
+
+	// FastTau is the time constant (in frames) of the fast exponential
+	// filter in the biphasic (band-pass) kernel for the transient channel.
+	FastTau float32
+
+	// SlowTau is the time constant (in frames) of the slow exponential
+	// filter that is subtracted from the fast one in the biphasic kernel
+	// for the transient channel. Must be > FastTau.
+	SlowTau float32
+
+	// SustainedGain is multiplier on the sustained channel output.
+	SustainedGain float32
+
+	// TransientGain is multiplier on the transient channel output.
+	TransientGain float32
+}
+
+func (pr *Params) Defaults() {
+	pr.SustainedTau = 4
+	pr.FastTau = 1
+	pr.SlowTau = 4
+	pr.SustainedGain = 1
+	pr.TransientGain = 2
+}
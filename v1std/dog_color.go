@@ -26,6 +26,11 @@ type DoGColor struct {
 	// set [v1vision.UseGPU].
 	GPU bool
 
+	// Mask excludes the invalid regions of the input images (e.g., from
+	// a Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]
+	// regions, so that padding does not generate spurious edge responses.
+	Mask bool
+
 	// LGN DoG filter parameters. Generally have larger fields,
 	// and no spatial tuning (i.e., OnSigma == OffSigma), consistent
 	// with blob cells.
@@ -43,9 +48,13 @@ type DoGColor struct {
 	// Output has the resulting DoG filter outputs, pointing to Values in V1.
 	// [Y, X, Polarity, Feature], where Polarity = On (0) vs Off (1) stronger.
 	// Feature: 0 = Red vs. Green; 1 = Blue vs. Yellow.
+	// See [DoGColor.Layout] for the names.
 	Output *tensor.Float32 `display:"no-inline"`
 
 	outIdx int
+
+	// maskIndex is the Images index of the valid-region mask, if Mask.
+	maskIndex int
 }
 
 func (vi *DoGColor) Defaults() {
@@ -82,6 +91,9 @@ func (vi *DoGColor) Config(ndata int, imageSize image.Point) {
 	kwtaIdx := 0
 	img := vi.V1.NewImage(vi.Geom.In.V())
 	wrap := vi.V1.NewImage(vi.Geom.In.V())
+	if vi.Mask {
+		vi.maskIndex = vi.V1.NewMaskImage(vi.Geom.In.V())
+	}
 	lmsRG := vi.V1.NewImage(vi.Geom.In.V())
 	lmsBY := vi.V1.NewImage(vi.Geom.In.V())
 
@@ -91,8 +103,12 @@ func (vi *DoGColor) Config(ndata int, imageSize image.Point) {
 	out := vi.V1.NewValues(int(vi.Geom.Out.Y), int(vi.Geom.Out.X), 2)
 	dogFt := vi.V1.NewDoGOnOff(&vi.DoG, &vi.Geom)
 
+	if vi.Mask {
+		vi.V1.SetMaskImage(vi.maskIndex)
+	}
 	vi.V1.NewConvolveDiff(lmsRG, v1vision.Red, lmsRG, v1vision.Green, dogFt, 0, 1, out, 0, 1, vi.DoG.OnGain, &vi.Geom)
 	vi.V1.NewConvolveDiff(lmsBY, v1vision.Blue, lmsBY, v1vision.Yellow, dogFt, 0, 1, out, 1, 1, vi.DoG.OnGain, &vi.Geom)
+	vi.V1.ClearMaskImage()
 
 	vi.outIdx = out
 	if vi.KWTA.On.IsTrue() {
@@ -112,6 +128,18 @@ func (vi *DoGColor) RunImages(im *Image, imgs ...image.Image) {
 	v1vision.UseGPU = vi.GPU
 	vi.V1.SetAsCurrent()
 	im.SetImagesRGB(&vi.V1, int(vi.Geom.Border.X), imgs...)
+	if vi.Mask {
+		im.SetMask(&vi.V1, vi.maskIndex, int(vi.Geom.Border.X))
+	}
 	vi.V1.Run(v1vision.ValuesVar)
 	vi.Output = vi.V1.Values.SubSpace(vi.outIdx).(*tensor.Float32)
 }
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are the On, Off polarities, and the columns are the
+// RedGreen and BlueYellow color contrasts.
+// Note that the Output can be larger than the Layout Shape:
+// use [Layout.Exact] to get an exact copy.
+func (vi *DoGColor) Layout() *Layout {
+	return dogColorLayout("", &vi.Geom)
+}
@@ -23,6 +23,11 @@ type DoGGrey struct {
 	// set [v1vision.UseGPU].
 	GPU bool
 
+	// Mask excludes the invalid regions of the input images (e.g., from
+	// a Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]
+	// regions, so that padding does not generate spurious edge responses.
+	Mask bool
+
 	// LGN DoG filter parameters.
 	DoG dog.Filter
 
@@ -34,7 +39,11 @@ type DoGGrey struct {
 
 	// Output has the resulting DoG filter outputs, pointing to Values in V1.
 	// [Y, X, Polarity, 1], where Polarity = On (0) vs Off (1) stronger.
+	// See [DoGGrey.Layout] for the names.
 	Output *tensor.Float32 `display:"no-inline"`
+
+	// maskIndex is the Images index of the valid-region mask, if Mask.
+	maskIndex int
 }
 
 func (vi *DoGGrey) Defaults() {
@@ -63,9 +72,16 @@ func (vi *DoGGrey) Config(ndata int, imageSize image.Point) {
 	vi.V1.Init(ndata)
 	img := vi.V1.NewImage(vi.Geom.In.V())
 	wrap := vi.V1.NewImage(vi.Geom.In.V())
+	if vi.Mask {
+		vi.maskIndex = vi.V1.NewMaskImage(vi.Geom.In.V())
+	}
 
 	vi.V1.NewWrapImage(img, 0, wrap, int(vi.Geom.Border.X), &vi.Geom)
+	if vi.Mask {
+		vi.V1.SetMaskImage(vi.maskIndex)
+	}
 	_, out := vi.V1.NewDoG(wrap, 0, &vi.DoG, &vi.Geom)
+	vi.V1.ClearMaskImage()
 	vi.V1.NewLogValues(out, out, 1, 1.0, &vi.Geom)
 	vi.V1.NewNormDiv(v1vision.MaxScalar, out, out, 1, &vi.Geom)
 
@@ -81,6 +97,17 @@ func (vi *DoGGrey) RunImages(im *Image, imgs ...image.Image) {
 	v1vision.UseGPU = vi.GPU
 	vi.V1.SetAsCurrent()
 	im.SetImagesGrey(&vi.V1, int(vi.Geom.Border.X), imgs...)
+	if vi.Mask {
+		im.SetMask(&vi.V1, vi.maskIndex, int(vi.Geom.Border.X))
+	}
 	vi.V1.Run(v1vision.ValuesVar)
 	vi.Output = vi.V1.Values.SubSpace(0).(*tensor.Float32)
 }
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are the On, Off polarities, with one Grey column.
+// Note that the Output can be larger than the Layout Shape:
+// use [Layout.Exact] to get an exact copy.
+func (vi *DoGGrey) Layout() *Layout {
+	return &Layout{Name: "DoG", Y: int(vi.Geom.Out.Y), X: int(vi.Geom.Out.X), Rows: polarityRows("", DoG, Grey), Cols: []Label{{Name: "Grey", Feature: DoG, Color: Grey}}}
+}
@@ -0,0 +1,214 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package v1std
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _FitModesValues = []FitModes{0, 1, 2}
+
+// FitModesN is the highest valid value for type FitModes, plus one.
+const FitModesN FitModes = 3
+
+var _FitModesValueMap = map[string]FitModes{`Stretch`: 0, `Letterbox`: 1, `Crop`: 2}
+
+var _FitModesDescMap = map[FitModes]string{0: `Stretch resizes the image to the target size, without preserving the aspect ratio.`, 1: `Letterbox resizes the image to fit within the target size, preserving the aspect ratio, centered within a black (0) padding region that is excluded from the Valid region.`, 2: `Crop resizes the image to cover the target size, preserving the aspect ratio, and crops out the center.`}
+
+var _FitModesMap = map[FitModes]string{0: `Stretch`, 1: `Letterbox`, 2: `Crop`}
+
+// String returns the string representation of this FitModes value.
+func (i FitModes) String() string { return enums.String(i, _FitModesMap) }
+
+// SetString sets the FitModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *FitModes) SetString(s string) error {
+	return enums.SetString(i, s, _FitModesValueMap, "FitModes")
+}
+
+// Int64 returns the FitModes value as an int64.
+func (i FitModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the FitModes value from an int64.
+func (i *FitModes) SetInt64(in int64) { *i = FitModes(in) }
+
+// Desc returns the description of the FitModes value.
+func (i FitModes) Desc() string { return enums.Desc(i, _FitModesDescMap) }
+
+// FitModesValues returns all possible values for the type FitModes.
+func FitModesValues() []FitModes { return _FitModesValues }
+
+// Values returns all possible values for the type FitModes.
+func (i FitModes) Values() []enums.Enum { return enums.Values(_FitModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i FitModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *FitModes) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "FitModes") }
+
+var _FeaturesValues = []Features{0, 1, 2, 3, 4, 5, 6, 7, 8}
+
+// FeaturesN is the highest valid value for type Features, plus one.
+const FeaturesN Features = 9
+
+var _FeaturesValueMap = map[string]Features{`Orientation`: 0, `LenSum`: 1, `EndStop`: 2, `Simple`: 3, `DoG`: 4, `Sustained`: 5, `Transient`: 6, `Disparity`: 7, `Motion`: 8}
+
+var _FeaturesDescMap = map[Features]string{0: `Orientation is a gabor filter orientation angle.`, 1: `LenSum is a V1 complex length-sum response.`, 2: `EndStop is a V1 complex end-stop response, in one of two directions along the orientation (Index 0 = plus, 1 = minus).`, 3: `Simple is a max-pooled V1 simple cell (gabor) response.`, 4: `DoG is a difference-of-gaussian response.`, 5: `Sustained is a sustained (parvocellular-like) temporal DoG response.`, 6: `Transient is a transient (magnocellular-like) temporal DoG response.`, 7: `Disparity is a binocular disparity energy response, with Index the position disparity shift in pixels (see [Stereo.Shift]).`, 8: `Motion is a starburst motion response, with Index the motion.Directions direction: Left, Right, Down or Up.`}
+
+var _FeaturesMap = map[Features]string{0: `Orientation`, 1: `LenSum`, 2: `EndStop`, 3: `Simple`, 4: `DoG`, 5: `Sustained`, 6: `Transient`, 7: `Disparity`, 8: `Motion`}
+
+// String returns the string representation of this Features value.
+func (i Features) String() string { return enums.String(i, _FeaturesMap) }
+
+// SetString sets the Features value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Features) SetString(s string) error {
+	return enums.SetString(i, s, _FeaturesValueMap, "Features")
+}
+
+// Int64 returns the Features value as an int64.
+func (i Features) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Features value from an int64.
+func (i *Features) SetInt64(in int64) { *i = Features(in) }
+
+// Desc returns the description of the Features value.
+func (i Features) Desc() string { return enums.Desc(i, _FeaturesDescMap) }
+
+// FeaturesValues returns all possible values for the type Features.
+func FeaturesValues() []Features { return _FeaturesValues }
+
+// Values returns all possible values for the type Features.
+func (i Features) Values() []enums.Enum { return enums.Values(_FeaturesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Features) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Features) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Features") }
+
+var _PolaritiesValues = []Polarities{0, 1, 2}
+
+// PolaritiesN is the highest valid value for type Polarities, plus one.
+const PolaritiesN Polarities = 3
+
+var _PolaritiesValueMap = map[string]Polarities{`NoPolarity`: 0, `OnPolarity`: 1, `OffPolarity`: 2}
+
+var _PolaritiesDescMap = map[Polarities]string{0: `NoPolarity is for features without a polarity.`, 1: `OnPolarity is the on (positive) polarity.`, 2: `OffPolarity is the off (negative) polarity.`}
+
+var _PolaritiesMap = map[Polarities]string{0: `NoPolarity`, 1: `OnPolarity`, 2: `OffPolarity`}
+
+// String returns the string representation of this Polarities value.
+func (i Polarities) String() string { return enums.String(i, _PolaritiesMap) }
+
+// SetString sets the Polarities value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Polarities) SetString(s string) error {
+	return enums.SetString(i, s, _PolaritiesValueMap, "Polarities")
+}
+
+// Int64 returns the Polarities value as an int64.
+func (i Polarities) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Polarities value from an int64.
+func (i *Polarities) SetInt64(in int64) { *i = Polarities(in) }
+
+// Desc returns the description of the Polarities value.
+func (i Polarities) Desc() string { return enums.Desc(i, _PolaritiesDescMap) }
+
+// PolaritiesValues returns all possible values for the type Polarities.
+func PolaritiesValues() []Polarities { return _PolaritiesValues }
+
+// Values returns all possible values for the type Polarities.
+func (i Polarities) Values() []enums.Enum { return enums.Values(_PolaritiesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Polarities) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Polarities) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "Polarities")
+}
+
+var _ColorsValues = []Colors{0, 1, 2, 3}
+
+// ColorsN is the highest valid value for type Colors, plus one.
+const ColorsN Colors = 4
+
+var _ColorsValueMap = map[string]Colors{`Grey`: 0, `AllColors`: 1, `RedGreen`: 2, `BlueYellow`: 3}
+
+var _ColorsDescMap = map[Colors]string{0: `Grey is the greyscale (luminance) channel.`, 1: `AllColors is the max across the Grey, RedGreen and BlueYellow channels.`, 2: `RedGreen is the red vs. green color opponent channel.`, 3: `BlueYellow is the blue vs. yellow color opponent channel.`}
+
+var _ColorsMap = map[Colors]string{0: `Grey`, 1: `AllColors`, 2: `RedGreen`, 3: `BlueYellow`}
+
+// String returns the string representation of this Colors value.
+func (i Colors) String() string { return enums.String(i, _ColorsMap) }
+
+// SetString sets the Colors value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Colors) SetString(s string) error { return enums.SetString(i, s, _ColorsValueMap, "Colors") }
+
+// Int64 returns the Colors value as an int64.
+func (i Colors) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Colors value from an int64.
+func (i *Colors) SetInt64(in int64) { *i = Colors(in) }
+
+// Desc returns the description of the Colors value.
+func (i Colors) Desc() string { return enums.Desc(i, _ColorsDescMap) }
+
+// ColorsValues returns all possible values for the type Colors.
+func ColorsValues() []Colors { return _ColorsValues }
+
+// Values returns all possible values for the type Colors.
+func (i Colors) Values() []enums.Enum { return enums.Values(_ColorsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Colors) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Colors) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Colors") }
+
+var _RawVideoFormatsValues = []RawVideoFormats{0, 1}
+
+// RawVideoFormatsN is the highest valid value for type RawVideoFormats, plus one.
+const RawVideoFormatsN RawVideoFormats = 2
+
+var _RawVideoFormatsValueMap = map[string]RawVideoFormats{`RawRGB24`: 0, `RawGray8`: 1}
+
+var _RawVideoFormatsDescMap = map[RawVideoFormats]string{0: `RawRGB24 has 3 bytes per pixel, for R, G, B.`, 1: `RawGray8 has 1 grey byte per pixel.`}
+
+var _RawVideoFormatsMap = map[RawVideoFormats]string{0: `RawRGB24`, 1: `RawGray8`}
+
+// String returns the string representation of this RawVideoFormats value.
+func (i RawVideoFormats) String() string { return enums.String(i, _RawVideoFormatsMap) }
+
+// SetString sets the RawVideoFormats value from its string representation,
+// and returns an error if the string is invalid.
+func (i *RawVideoFormats) SetString(s string) error {
+	return enums.SetString(i, s, _RawVideoFormatsValueMap, "RawVideoFormats")
+}
+
+// Int64 returns the RawVideoFormats value as an int64.
+func (i RawVideoFormats) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the RawVideoFormats value from an int64.
+func (i *RawVideoFormats) SetInt64(in int64) { *i = RawVideoFormats(in) }
+
+// Desc returns the description of the RawVideoFormats value.
+func (i RawVideoFormats) Desc() string { return enums.Desc(i, _RawVideoFormatsDescMap) }
+
+// RawVideoFormatsValues returns all possible values for the type RawVideoFormats.
+func RawVideoFormatsValues() []RawVideoFormats { return _RawVideoFormatsValues }
+
+// Values returns all possible values for the type RawVideoFormats.
+func (i RawVideoFormats) Values() []enums.Enum { return enums.Values(_RawVideoFormatsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i RawVideoFormats) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *RawVideoFormats) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "RawVideoFormats")
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/iox/imagex"
+	"cogentcore.org/core/math32"
+)
+
+// FrameSource is a source of sequential video frames,
+// e.g., [DirFrames], [GIFFrames], [Y4MFrames] or [RawFrames].
+// Use [Frames] to deliver frames from multiple sources
+// batched across data-parallel items.
+type FrameSource interface {
+	// NumFrames returns the total number of frames.
+	NumFrames() int
+
+	// Frame returns the frame at given index.
+	Frame(i int) (image.Image, error)
+
+	// FrameRate returns the native frame rate in frames per second,
+	// or 0 if not known.
+	FrameRate() float32
+
+	// Close closes any open files.
+	Close() error
+}
+
+// OpenFrames opens a [FrameSource] for given path, based on its type:
+// a directory of numbered image files ([DirFrames]), an animated .gif
+// ([GIFFrames]), or a .y4m video ([Y4MFrames]).
+func OpenFrames(path string) (FrameSource, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if st.IsDir() {
+		return OpenDirFrames(path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gif":
+		return OpenGIFFrames(path)
+	case ".y4m":
+		return OpenY4MFrames(path)
+	}
+	return nil, fmt.Errorf("v1std.OpenFrames: unsupported frame source type: %s", path)
+}
+
+// Frames delivers frames from a [FrameSource] for each data-parallel
+// item, in batches of NData images for [Image] RunImages methods,
+// e.g., in [MotionDoG]. Frames are delivered at the given Rate,
+// by skipping or repeating source frames according to their native
+// [FrameSource.FrameRate], and sources can be looped.
+type Frames struct {
+	// Sources are the frame sources for each data-parallel item.
+	Sources []FrameSource
+
+	// Rate is the rate in frames per second at which frames are delivered.
+	// Source frames are skipped or repeated to match their native frame rate.
+	// If 0, or the source frame rate is not known, each source frame is
+	// delivered in turn.
+	Rate float32
+
+	// Loop restarts each source from the first frame after its last frame,
+	// and the item is included in Restarted.
+	// Otherwise, the last frame is repeated until all sources are Done.
+	Loop bool
+
+	// Step is the number of frames delivered since the last Init.
+	Step int `edit:"-"`
+
+	// Index are the source frame indexes of the current frames.
+	Index []int `edit:"-"`
+
+	// Restarted records the items that started over from the first
+	// frame of their source on the current frame, due to Loop
+	// (and all items for the first frame), for which any temporal
+	// integration state should be reset (e.g., ResetItem in [MotionDoG]).
+	Restarted []bool `edit:"-"`
+
+	// Done is set when all of the sources have reached their last frame
+	// without Loop.
+	Done bool `edit:"-"`
+
+	// start are the Step values at which each item was last restarted.
+	start []int
+}
+
+// NewFrames returns a new [Frames] for given sources.
+func NewFrames(srcs ...FrameSource) *Frames {
+	fr := &Frames{Sources: srcs}
+	fr.Init()
+	return fr
+}
+
+// NData returns the number of data-parallel items, i.e., Sources.
+func (fr *Frames) NData() int {
+	return len(fr.Sources)
+}
+
+// Init starts all sources over from the first frame.
+func (fr *Frames) Init() {
+	n := fr.NData()
+	fr.Step = 0
+	fr.Done = false
+	fr.Index = make([]int, n)
+	fr.Restarted = make([]bool, n)
+	fr.start = make([]int, n)
+}
+
+// sourceIndex returns the source frame index for given number
+// of steps since the start of the source.
+func (fr *Frames) sourceIndex(src FrameSource, step int) int {
+	srate := src.FrameRate()
+	if fr.Rate <= 0 || srate <= 0 {
+		return step
+	}
+	return int(math32.Floor(float32(step)*srate/fr.Rate + 1.0e-4))
+}
+
+// Next returns the next batch of frames, one for each source,
+// updating Index, Restarted and Done. Returns an error if any
+// source has no frames, with a nil image for that item.
+func (fr *Frames) Next() ([]image.Image, error) {
+	if len(fr.Index) != fr.NData() {
+		fr.Init()
+	}
+	imgs := make([]image.Image, fr.NData())
+	var errs []error
+	done := true
+	for ni, src := range fr.Sources {
+		n := src.NumFrames()
+		if n == 0 {
+			errs = append(errs, fmt.Errorf("v1std.Frames.Next: source for item %d has no frames", ni))
+			continue
+		}
+		fr.Restarted[ni] = fr.Step == 0
+		idx := fr.sourceIndex(src, fr.Step-fr.start[ni])
+		if idx >= n && fr.Loop {
+			fr.start[ni] = fr.Step
+			fr.Restarted[ni] = true
+			idx = 0
+		}
+		if idx < n-1 || fr.Loop {
+			done = false
+		}
+		idx = max(min(idx, n-1), 0)
+		fr.Index[ni] = idx
+		img, err := src.Frame(idx)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		imgs[ni] = img
+	}
+	fr.Done = done
+	fr.Step++
+	return imgs, errors.Join(errs...)
+}
+
+// ImageRunner is implemented by the standard pipelines that
+// process image inputs, e.g., [MotionDoG].
+type ImageRunner interface {
+	RunImages(im *Image, imgs ...image.Image)
+}
+
+// ItemResetter is implemented by the standard pipelines with temporal
+// integration state that can be reset for each data-parallel item,
+// e.g., [MotionDoG].
+type ItemResetter interface {
+	ResetItem(ni int)
+}
+
+// Run runs the given pipeline on successive batches of frames, using
+// given [Image] handler, until all sources are Done, or the given
+// function returns false (if non-nil), which is called after each step
+// with the index of the frame batch just processed.
+// Items that are Restarted are reset if the pipeline is an [ItemResetter].
+func (fr *Frames) Run(vi ImageRunner, im *Image, fun func(step int) bool) error {
+	for {
+		imgs, err := fr.Next()
+		if err != nil {
+			return err
+		}
+		if rs, ok := vi.(ItemResetter); ok {
+			for ni, r := range fr.Restarted {
+				if r {
+					rs.ResetItem(ni)
+				}
+			}
+		}
+		vi.RunImages(im, imgs...)
+		if fun != nil && !fun(fr.Step-1) {
+			return nil
+		}
+		if fr.Done {
+			return nil
+		}
+	}
+}
+
+// Close closes all of the sources.
+func (fr *Frames) Close() error {
+	var errs []error
+	for _, src := range fr.Sources {
+		errs = append(errs, src.Close())
+	}
+	return errors.Join(errs...)
+}
+
+//////// DirFrames
+
+// DirFrames is a [FrameSource] for a directory of numbered image files,
+// in the order of the last number in their file names (e.g., frame_9.png
+// before frame_10.png), which are opened as needed.
+type DirFrames struct {
+	// Files are the image file names, in frame order.
+	Files []string
+
+	// FPS is the frame rate, in frames per second, if known.
+	FPS float32
+}
+
+// OpenDirFrames returns a [DirFrames] for the image files in given
+// directory.
+func OpenDirFrames(dir string) (*DirFrames, error) {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	df := &DirFrames{}
+	for _, ent := range ents {
+		if ent.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(ent.Name())) {
+		case ".png", ".jpg", ".jpeg", ".gif", ".tif", ".tiff", ".bmp", ".webp":
+			df.Files = append(df.Files, filepath.Join(dir, ent.Name()))
+		}
+	}
+	if len(df.Files) == 0 {
+		return nil, fmt.Errorf("v1std.OpenDirFrames: no image files in: %s", dir)
+	}
+	slices.SortStableFunc(df.Files, func(a, b string) int {
+		an, bn := frameNumber(a), frameNumber(b)
+		if an != bn {
+			return an - bn
+		}
+		return strings.Compare(a, b)
+	})
+	return df, nil
+}
+
+// frameNumber returns the last number in the base name of given file,
+// or -1 if none.
+func frameNumber(fn string) int {
+	base := strings.TrimSuffix(filepath.Base(fn), filepath.Ext(fn))
+	ed := strings.LastIndexFunc(base, isDigit)
+	if ed < 0 {
+		return -1
+	}
+	st := strings.LastIndexFunc(base[:ed+1], func(r rune) bool { return !isDigit(r) }) + 1
+	n, _ := strconv.Atoi(base[st : ed+1])
+	return n
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func (df *DirFrames) NumFrames() int     { return len(df.Files) }
+func (df *DirFrames) FrameRate() float32 { return df.FPS }
+func (df *DirFrames) Close() error       { return nil }
+
+func (df *DirFrames) Frame(i int) (image.Image, error) {
+	img, _, err := imagex.Open(df.Files[i])
+	return img, err
+}
+
+//////// GIFFrames
+
+// GIFFrames is a [FrameSource] for an animated GIF file,
+// which is fully decoded into full-size frames when opened.
+type GIFFrames struct {
+	// Frames are the full frame images, composited according
+	// to the GIF frame disposal methods.
+	Frames []*image.RGBA
+
+	// FPS is the frame rate, in frames per second, from the average
+	// frame delay, or 0 if no delays are specified.
+	FPS float32
+}
+
+// OpenGIFFrames opens an animated GIF file as a [GIFFrames].
+func OpenGIFFrames(filename string) (*GIFFrames, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadGIFFrames(f)
+}
+
+// ReadGIFFrames reads an animated GIF as a [GIFFrames].
+func ReadGIFFrames(r io.Reader) (*GIFFrames, error) {
+	anim, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, err
+	}
+	gf := &GIFFrames{}
+	bd := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	canvas := image.NewRGBA(bd)
+	delay := 0
+	for i, pi := range anim.Image {
+		var prev *image.RGBA
+		disp := byte(0)
+		if i < len(anim.Disposal) {
+			disp = anim.Disposal[i]
+		}
+		if disp == gif.DisposalPrevious {
+			prev = image.NewRGBA(bd)
+			copy(prev.Pix, canvas.Pix)
+		}
+		draw.Draw(canvas, pi.Bounds(), pi, pi.Bounds().Min, draw.Over)
+		frame := image.NewRGBA(bd)
+		copy(frame.Pix, canvas.Pix)
+		gf.Frames = append(gf.Frames, frame)
+		switch disp {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, pi.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = prev
+		}
+		if i < len(anim.Delay) {
+			delay += anim.Delay[i]
+		}
+	}
+	if delay > 0 {
+		gf.FPS = 100 * float32(len(gf.Frames)) / float32(delay)
+	}
+	return gf, nil
+}
+
+func (gf *GIFFrames) NumFrames() int                   { return len(gf.Frames) }
+func (gf *GIFFrames) Frame(i int) (image.Image, error) { return gf.Frames[i], nil }
+func (gf *GIFFrames) FrameRate() float32               { return gf.FPS }
+func (gf *GIFFrames) Close() error                     { return nil }
@@ -6,6 +6,8 @@ package v1std
 
 import (
 	"image"
+	"image/draw"
+	"math"
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/iox/imagex"
@@ -26,28 +28,140 @@ type Image struct {
 	// Size is the target image size to use. Images will be rescaled to this size.
 	Size image.Point
 
+	// Fit is how images of a different size are fit into the target Size.
+	Fit FitModes
+
+	// ItemFit optionally specifies a different Fit for each data-parallel
+	// image item, overriding Fit for the items it has.
+	ItemFit []FitModes `display:"-"`
+
+	// Valid are the valid regions of each of the current Images,
+	// in image coordinates with Y = 0 at the top, excluding any padding
+	// from a Letterbox Fit. See [Image.SetMask].
+	Valid []image.Rectangle `display:"-"`
+
 	// Images are the current input image(s), as Go [image.Image].
 	Images []image.Image `display:"-"`
 
 	// Tsr are the current input image(s) as an RGB tensor.
 	// This points into the V1Vision.Images input image.
 	Tsr *tensor.Float32 `display:"no-inline"`
+
+	// Upload uses [v1vision.V1Vision.UploadImages] to convert the images
+	// into the tensor on the GPU, which is much faster, but the Tsr CPU
+	// copy of the images is not updated when running on the GPU.
+	Upload bool
 }
 
 func (vi *Image) Defaults() {
 	vi.Size = image.Point{128, 128}
 }
 
-// SetImagesResize sets current image(s) for processing, resizing to target size.
+// FitModes are the ways of fitting an image of a different size
+// into the target [Image.Size].
+type FitModes int32 //enums:enum
+
+const (
+	// Stretch resizes the image to the target size,
+	// without preserving the aspect ratio.
+	Stretch FitModes = iota
+
+	// Letterbox resizes the image to fit within the target size,
+	// preserving the aspect ratio, centered within a black (0) padding
+	// region that is excluded from the Valid region.
+	Letterbox
+
+	// Crop resizes the image to cover the target size,
+	// preserving the aspect ratio, and crops out the center.
+	Crop
+)
+
+// ItemFitMode returns the Fit mode for given data-parallel image item.
+func (vi *Image) ItemFitMode(ni int) FitModes {
+	if ni < len(vi.ItemFit) {
+		return vi.ItemFit[ni]
+	}
+	return vi.Fit
+}
+
+// SetImagesResize sets current image(s) for processing, resizing to target
+// size according to the Fit mode for each image, and setting Valid regions.
 func (vi *Image) SetImagesResize(imgs ...image.Image) {
 	// todo: do this all on GPU at some point!
 	vi.Images = imgs
+	vi.Valid = make([]image.Rectangle, len(imgs))
 	for i, im := range vi.Images {
+		vi.Valid[i] = image.Rectangle{Max: vi.Size}
 		isz := im.Bounds().Size()
-		if isz != vi.Size {
+		if isz == vi.Size {
+			continue
+		}
+		fit := vi.ItemFitMode(i)
+		if fit == Stretch {
 			vi.Images[i] = transform.Resize(im, vi.Size.X, vi.Size.Y, transform.Linear)
+			continue
+		}
+		sx := float64(vi.Size.X) / float64(isz.X)
+		sy := float64(vi.Size.Y) / float64(isz.Y)
+		sc := min(sx, sy)
+		if fit == Crop {
+			sc = max(sx, sy)
 		}
+		rsz := image.Pt(max(int(math.Round(sc*float64(isz.X))), 1), max(int(math.Round(sc*float64(isz.Y))), 1))
+		if fit == Crop { // in case of rounding
+			rsz = image.Pt(max(rsz.X, vi.Size.X), max(rsz.Y, vi.Size.Y))
+		} else {
+			rsz = image.Pt(min(rsz.X, vi.Size.X), min(rsz.Y, vi.Size.Y))
+		}
+		rim := transform.Resize(im, rsz.X, rsz.Y, transform.Linear)
+		off := vi.Size.Sub(rsz).Div(2)
+		if fit == Crop {
+			vi.Images[i] = transform.Crop(rim, image.Rectangle{Max: vi.Size}.Sub(off))
+			continue
+		}
+		box := image.NewRGBA(image.Rectangle{Max: vi.Size})
+		draw.Draw(box, box.Bounds(), image.Black, image.Point{}, draw.Src)
+		vi.Valid[i] = image.Rectangle{Min: off, Max: off.Add(rsz)}
+		draw.Draw(box, vi.Valid[i], rim, image.Point{}, draw.Src)
+		vi.Images[i] = box
+	}
+}
+
+// SetMask sets the valid-region mask image at given index in Images
+// (e.g., from [v1vision.V1Vision.NewMaskImage]) from the current Valid
+// regions of the images, with 1 for valid and 0 for invalid pixels.
+// The border of given size around the edges follows the wrap padding
+// of the images (see [v1vision.V1Vision.NewWrapImage]), so it is only
+// invalid where it wraps around invalid (e.g., letterbox) regions, and
+// the mask has no effect for images that are entirely valid.
+func (vi *Image) SetMask(v1 *v1vision.V1Vision, idx, border int) {
+	masks := make([]image.Image, len(vi.Images))
+	psz := vi.Size.Add(image.Pt(2*border, 2*border))
+	for i, vr := range vi.Valid {
+		msk := image.NewGray(image.Rectangle{Max: psz})
+		for y := range psz.Y {
+			sy := wrapIndex(y-border, vi.Size.Y)
+			for x := range psz.X {
+				if image.Pt(wrapIndex(x-border, vi.Size.X), sy).In(vr) {
+					msk.Pix[msk.PixOffset(x, y)] = 255
+				}
+			}
+		}
+		masks[i] = msk
+	}
+	if vi.Upload {
+		errors.Log(v1.UploadImages(idx, 0, true, v1vision.BottomZero, masks...))
+		return
 	}
+	tsr := v1.Images.SubSpace(idx).(*tensor.Float32)
+	tensor.SetAllFloat64(tsr, 0)
+	v1vision.RGBToGrey(tsr, 0, v1vision.BottomZero, masks...)
+}
+
+// wrapIndex returns the index of given possibly out-of-range index
+// within given size, wrapping around as in the [v1vision.WrapPad] op.
+func wrapIndex(i, size int) int {
+	return ((i % size) + size) % size
 }
 
 // OpenImagesResize opens image(s) from given filename(s), and resizes to target size.
@@ -82,6 +196,10 @@ func (vi *Image) GetTensors(v1 *v1vision.V1Vision, idx int) {
 func (vi *Image) SetImagesRGB(v1 *v1vision.V1Vision, border int, imgs ...image.Image) {
 	vi.SetImagesResize(imgs...)
 	vi.GetTensors(v1, 0)
+	if vi.Upload {
+		errors.Log(v1.UploadImages(0, border, false, v1vision.BottomZero, vi.Images...))
+		return
+	}
 	v1vision.RGBToTensor(vi.Tsr, border, v1vision.BottomZero, vi.Images...)
 }
 
@@ -89,7 +207,20 @@ func (vi *Image) SetImagesRGB(v1 *v1vision.V1Vision, border int, imgs ...image.I
 // and converts to a float32 tensor as greyscale image.
 // border is the border size to add around edges.
 func (vi *Image) SetImagesGrey(v1 *v1vision.V1Vision, border int, imgs ...image.Image) {
+	vi.SetImagesGreyIndex(v1, 0, border, imgs...)
+}
+
+// SetImagesGreyIndex sets current image(s) for processing
+// and converts to a float32 tensor as greyscale image,
+// in the Images tensor at given index (e.g., 1 for the right eye
+// images in [Stereo]).
+// border is the border size to add around edges.
+func (vi *Image) SetImagesGreyIndex(v1 *v1vision.V1Vision, idx, border int, imgs ...image.Image) {
 	vi.SetImagesResize(imgs...)
-	vi.GetTensors(v1, 0)
+	vi.GetTensors(v1, idx)
+	if vi.Upload {
+		errors.Log(v1.UploadImages(idx, border, true, v1vision.BottomZero, vi.Images...))
+		return
+	}
 	v1vision.RGBToGrey(vi.Tsr, border, v1vision.BottomZero, vi.Images...)
 }
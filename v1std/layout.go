@@ -0,0 +1,304 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"fmt"
+	"strings"
+
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// Features are the types of features in the rows and columns
+// of a pipeline output [Layout].
+type Features int32 //enums:enum
+
+const (
+	// Orientation is a gabor filter orientation angle.
+	Orientation Features = iota
+
+	// LenSum is a V1 complex length-sum response.
+	LenSum
+
+	// EndStop is a V1 complex end-stop response, in one of two
+	// directions along the orientation (Index 0 = plus, 1 = minus).
+	EndStop
+
+	// Simple is a max-pooled V1 simple cell (gabor) response.
+	Simple
+
+	// DoG is a difference-of-gaussian response.
+	DoG
+
+	// Sustained is a sustained (parvocellular-like) temporal DoG response.
+	Sustained
+
+	// Transient is a transient (magnocellular-like) temporal DoG response.
+	Transient
+
+	// Disparity is a binocular disparity energy response, with Index
+	// the position disparity shift in pixels (see [Stereo.Shift]).
+	Disparity
+
+	// Motion is a starburst motion response, with Index the
+	// motion.Directions direction: Left, Right, Down or Up.
+	Motion
+)
+
+// Polarities are the polarities of features in a [Layout].
+type Polarities int32 //enums:enum
+
+const (
+	// NoPolarity is for features without a polarity.
+	NoPolarity Polarities = iota
+
+	// OnPolarity is the on (positive) polarity.
+	OnPolarity
+
+	// OffPolarity is the off (negative) polarity.
+	OffPolarity
+)
+
+// Colors are the color channels of features in a [Layout].
+type Colors int32 //enums:enum
+
+const (
+	// Grey is the greyscale (luminance) channel.
+	Grey Colors = iota
+
+	// AllColors is the max across the Grey, RedGreen and BlueYellow
+	// channels.
+	AllColors
+
+	// RedGreen is the red vs. green color opponent channel.
+	RedGreen
+
+	// BlueYellow is the blue vs. yellow color opponent channel.
+	BlueYellow
+)
+
+// Label describes one row or column of a [Layout].
+type Label struct {
+	// Name is the unique name of this row or column within the Layout,
+	// e.g., "LenSum", "Simple_RedGreen_On", or "45" for an angle.
+	Name string
+
+	// Feature is the type of feature.
+	Feature Features
+
+	// Polarity is the polarity of the feature, if any.
+	Polarity Polarities
+
+	// Color is the color channel of the feature.
+	Color Colors
+
+	// Angle is the orientation angle in degrees, for Orientation
+	// (0 = horizontal, increasing counter-clockwise).
+	Angle float32
+
+	// Index distinguishes multiple instances of the same feature:
+	// the EndStop direction, Disparity shift, or Motion direction.
+	Index int
+}
+
+// Layout describes the layout of a pipeline output with 4D
+// [Y][X][Row][Col] data per data-parallel item, naming each of
+// the inner Rows and Cols (e.g., feature type, polarity, color,
+// orientation angle), so that outputs can be accessed by name instead
+// of hard-coded offsets. Layouts are valid after Config.
+type Layout struct {
+	// Name is the name of the output, e.g., V1c or DoG.
+	Name string
+
+	// Scale is the name of the scale, for multi-scale pipelines
+	// (e.g., L16 in [V1cMulti]), and empty otherwise.
+	Scale string
+
+	// Y, X are the outer spatial dimensions.
+	Y, X int
+
+	// Rows are the labels of the inner rows.
+	Rows []Label
+
+	// Cols are the labels of the inner columns.
+	Cols []Label
+}
+
+// Shape returns the shape of the output per data-parallel item:
+// [Y, X, Rows, Cols]. The output tensor has an additional outer
+// NData dimension.
+func (ly *Layout) Shape() []int {
+	return []int{ly.Y, ly.X, len(ly.Rows), len(ly.Cols)}
+}
+
+// RowIndex returns the index of the row with given name,
+// or -1 if not found.
+func (ly *Layout) RowIndex(name string) int {
+	return labelIndex(ly.Rows, name)
+}
+
+// ColIndex returns the index of the column with given name,
+// or -1 if not found.
+func (ly *Layout) ColIndex(name string) int {
+	return labelIndex(ly.Cols, name)
+}
+
+// RowNames returns the names of the rows.
+func (ly *Layout) RowNames() []string {
+	return labelNames(ly.Rows)
+}
+
+// ColNames returns the names of the columns.
+func (ly *Layout) ColNames() []string {
+	return labelNames(ly.Cols)
+}
+
+// RowsWhere returns the indexes of the rows for which given function
+// returns true, e.g., all rows of a given Feature or Color.
+func (ly *Layout) RowsWhere(fun func(lb *Label) bool) []int {
+	return labelsWhere(ly.Rows, fun)
+}
+
+// ColsWhere returns the indexes of the columns for which given
+// function returns true.
+func (ly *Layout) ColsWhere(fun func(lb *Label) bool) []int {
+	return labelsWhere(ly.Cols, fun)
+}
+
+// Slice returns a view of given output tensor, which has an outer
+// NData dimension and can be larger than the layout Shape, with the
+// given row and column indexes (nil = all), as
+// [NData][Y][X][len(rows)][len(cols)].
+func (ly *Layout) Slice(out tensor.Tensor, rows, cols []int) *tensor.Sliced {
+	if rows == nil {
+		rows = sequence(len(ly.Rows))
+	}
+	if cols == nil {
+		cols = sequence(len(ly.Cols))
+	}
+	return tensor.NewSliced(out, nil, sequence(ly.Y), sequence(ly.X), rows, cols)
+}
+
+// Row returns a view of given output tensor for the row with given
+// name, as [NData][Y][X][1][Cols]. Returns nil if not found.
+func (ly *Layout) Row(out tensor.Tensor, name string) *tensor.Sliced {
+	ri := ly.RowIndex(name)
+	if ri < 0 {
+		return nil
+	}
+	return ly.Slice(out, []int{ri}, nil)
+}
+
+// Exact returns a copy of given output tensor, which has an outer
+// NData dimension and can be larger than the layout Shape,
+// with exactly the layout Shape.
+func (ly *Layout) Exact(out tensor.Tensor) *tensor.Float32 {
+	ex := tensor.NewFloat32(append([]int{out.DimSize(0)}, ly.Shape()...)...)
+	tensor.CopyFromLargerShape(ex, out)
+	return ex
+}
+
+// String returns a summary of the layout, with its shape
+// and row and column names.
+func (ly *Layout) String() string {
+	nm := ly.Name
+	if ly.Scale != "" {
+		nm += "_" + ly.Scale
+	}
+	return fmt.Sprintf("%s: %v Rows: [%s] Cols: [%s]", nm, ly.Shape(), strings.Join(ly.RowNames(), " "), strings.Join(ly.ColNames(), " "))
+}
+
+func labelIndex(lbs []Label, name string) int {
+	for i := range lbs {
+		if lbs[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func labelNames(lbs []Label) []string {
+	nms := make([]string, len(lbs))
+	for i := range lbs {
+		nms[i] = lbs[i].Name
+	}
+	return nms
+}
+
+func labelsWhere(lbs []Label, fun func(lb *Label) bool) []int {
+	var idxs []int
+	for i := range lbs {
+		if fun(&lbs[i]) {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+func sequence(n int) []int {
+	idxs := make([]int, n)
+	for i := range idxs {
+		idxs[i] = i
+	}
+	return idxs
+}
+
+//////// Standard layouts
+
+// angleCols returns the column labels for nang gabor orientation angles.
+func angleCols(nang int) []Label {
+	lbs := make([]Label, nang)
+	for i := range lbs {
+		ang := float32(i) * 180 / float32(nang)
+		lbs[i] = Label{Name: fmt.Sprintf("%g", ang), Feature: Orientation, Angle: ang}
+	}
+	return lbs
+}
+
+// polarityRows returns On, Off row labels for given feature and color,
+// with given name prefix (none if empty).
+func polarityRows(prefix string, ft Features, clr Colors) []Label {
+	if prefix != "" {
+		prefix += "_"
+	}
+	return []Label{
+		{Name: prefix + "On", Feature: ft, Polarity: OnPolarity, Color: clr},
+		{Name: prefix + "Off", Feature: ft, Polarity: OffPolarity, Color: clr},
+	}
+}
+
+// v1cRows returns the V1 complex output row labels: LenSum, 2 EndStop
+// directions, and On, Off Simple rows, for each color if split.
+// For color, the complex features are computed on AllColors.
+func v1cRows(color, split bool) []Label {
+	clr := Grey
+	if color {
+		clr = AllColors
+	}
+	rows := []Label{
+		{Name: "LenSum", Feature: LenSum, Color: clr},
+		{Name: "EndStop_Plus", Feature: EndStop, Color: clr},
+		{Name: "EndStop_Minus", Feature: EndStop, Color: clr, Index: 1},
+	}
+	if !color || !split {
+		return append(rows, polarityRows("Simple", Simple, clr)...)
+	}
+	for _, c := range []Colors{Grey, RedGreen, BlueYellow} {
+		rows = append(rows, polarityRows("Simple_"+c.String(), Simple, c)...)
+	}
+	return rows
+}
+
+// v1cLayout returns the V1 complex output layout.
+func v1cLayout(scale string, color, split bool, nang int, geom *v1vision.Geom) *Layout {
+	return &Layout{Name: "V1c", Scale: scale, Y: int(geom.Out.Y), X: int(geom.Out.X), Rows: v1cRows(color, split), Cols: angleCols(nang)}
+}
+
+// dogColorLayout returns the DoG color output layout.
+func dogColorLayout(scale string, geom *v1vision.Geom) *Layout {
+	return &Layout{Name: "DoG", Scale: scale, Y: int(geom.Out.Y), X: int(geom.Out.X), Rows: polarityRows("", DoG, AllColors),
+		Cols: []Label{{Name: "RedGreen", Feature: DoG, Color: RedGreen}, {Name: "BlueYellow", Feature: DoG, Color: BlueYellow}}}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/motion"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// MotionPath has the motion processing parameters and outputs that are
+// shared by the motion pipelines ([MotionDoG], [MotionColor], [MotionGabor]),
+// which differ only in the filtered input values that motion is computed on.
+type MotionPath struct {
+
+	// Motion filter parameters.
+	Motion motion.Params
+
+	// FullField has the integrated FullField output: [NData, 2, 2].
+	// Use [motion.Directions] for 1D indexes (is 2x2 for [L,R][D,U]).
+	// If Motion.OpticFlow, it is [NData, 4, 2] with 2 additional rows for
+	// [Expand,Contract][Clockwise,CounterClockwise].
+	FullField tensor.Float32 `display:"no-inline"`
+
+	// GetStar retrieves the star values. Otherwise, just the full-field.
+	GetStar bool
+
+	// Star has the star values, if GetStar is true,
+	// pointing to Values4D in V1.
+	// [NData, Y, X, Polarity, 4 * FilterN], where Polarity is input polarity,
+	// and 4 is for Left, Right, Down, Up, for each input filter.
+	Star *tensor.Float32 `display:"no-inline"`
+
+	// GetFlow computes the local Flow field, pooled over
+	// [motion.Params.FlowPool] regions of the Star values.
+	GetFlow bool
+
+	// FlowGeom is the geometry for pooling the Star values into Flow.
+	FlowGeom v1vision.Geom `edit:"-"`
+
+	// Flow has the local flow field, if GetFlow is true:
+	// [NData, Y, X, 2] where the last dimension is dx, dy, with
+	// positive values for Right and Up motion respectively.
+	Flow tensor.Float32 `display:"no-inline"`
+
+	// GetGrid computes the regional Grid of full-field motion values,
+	// over [motion.Params.GridY] x [motion.Params.GridX] regions.
+	GetGrid bool
+
+	// Grid has the integrated regional full-field motion values,
+	// if GetGrid is true: [NData, GridY, GridX, 2, 2] where the
+	// inner 2x2 is [L,R][D,U] as in FullField.
+	Grid tensor.Float32 `display:"no-inline"`
+
+	// starIndex is the Values4D index of the star output.
+	starIndex int
+
+	// flowIndex is the Values index of the flow output.
+	flowIndex int
+
+	// gridIndex is the Values4D index of the grid output.
+	gridIndex int
+}
+
+// starLayout returns the layout of the Star output, with given row
+// labels for the input polarities, and 4 motion direction columns
+// (Left, Right, Down, Up) for each of the given input filter labels,
+// named by the filter name (if any) and direction, e.g., RedGreen_Left.
+func (mp *MotionPath) starLayout(rows, filters []Label, geom *v1vision.Geom) *Layout {
+	cols := make([]Label, 0, 4*len(filters))
+	for _, fl := range filters {
+		for dir := motion.Left; dir <= motion.Up; dir++ {
+			lb := fl
+			lb.Feature = Motion
+			lb.Index = int(dir)
+			lb.Name = dir.String()
+			if fl.Name != "" {
+				lb.Name = fl.Name + "_" + lb.Name
+			}
+			cols = append(cols, lb)
+		}
+	}
+	return &Layout{Name: "Star", Y: int(geom.Out.Y - 1), X: int(geom.Out.X - 1), Rows: rows, Cols: cols}
+}
+
+// configMotion configures the motion processing ops on given input
+// Values index, with fn filters, using given geometry of the input values.
+func (mp *MotionPath) configMotion(v1 *v1vision.V1Vision, in, fn int, geom *v1vision.Geom) {
+	ndata := v1.NData
+	mp.FullField.SetShapeSizes(ndata, mp.Motion.IntegRows(), 2)
+
+	mp.Motion.DoGSumScalarIndex = v1.NewAggScalar(v1vision.SumScalar, in, fn, geom)
+	fastIdx := v1.NewMotionIntegrate(in, fn, mp.Motion.FastTau, mp.Motion.SlowTau, geom)
+	starIdx := v1.NewMotionStar(fastIdx, fn, mp.Motion.Gain, geom)
+	mp.Motion.FFScalarIndex = v1.NewMotionFullField(starIdx, fn, geom)
+	if mp.Motion.OpticFlow {
+		mp.Motion.OpticScalarIndex = v1.NewMotionOpticFlow(starIdx, fn, mp.Motion.FocusX, mp.Motion.FocusY, geom)
+	}
+
+	if mp.GetStar {
+		mp.starIndex = v1.NewValues4D(int(geom.Out.Y), int(geom.Out.X), 2, 4*fn)
+		v1.NewTo4D(starIdx, mp.starIndex, 2, 4*fn, 0, geom)
+	}
+
+	if mp.GetFlow {
+		fp := mp.Motion.FlowPool
+		starSize := math32.Vec2i(int(geom.Out.X-1), int(geom.Out.Y-1))
+		mp.FlowGeom.SetFilter(math32.Vec2i(0, 0), math32.Vec2i(fp, fp), math32.Vec2i(fp, fp), starSize)
+		mp.flowIndex = v1.NewMotionFlow(starIdx, fn, mp.Motion.FlowGain, &mp.FlowGeom)
+		mp.Flow.SetShapeSizes(ndata, int(mp.FlowGeom.Out.Y), int(mp.FlowGeom.Out.X), 2)
+	}
+
+	if mp.GetGrid {
+		mp.gridIndex = v1.NewMotionGrid(starIdx, fn, mp.Motion.GridY, mp.Motion.GridX, mp.Motion.DoGSumScalarIndex, mp.Motion.FullGain, mp.Motion.IntegTau, geom)
+		mp.Grid.SetShapeSizes(ndata, mp.Motion.GridY, mp.Motion.GridX, 2, 2)
+	}
+}
+
+// motionVars returns the GPU variables needed to get the motion outputs.
+func (mp *MotionPath) motionVars() []v1vision.GPUVars {
+	vals := []v1vision.GPUVars{v1vision.ScalarsVar, v1vision.ValuesVar}
+	if mp.GetStar || mp.GetGrid {
+		vals = append(vals, v1vision.Values4DVar)
+	}
+	return vals
+}
+
+// motionOutputs updates the motion outputs after running.
+func (mp *MotionPath) motionOutputs(v1 *v1vision.V1Vision) {
+	mp.Motion.FullFieldInteg(v1.NData, v1.Scalars, &mp.FullField)
+	if mp.GetStar {
+		mp.Star = v1.Values4D.SubSpace(mp.starIndex).(*tensor.Float32)
+	}
+	if mp.GetFlow {
+		mp.getFlow(v1)
+	}
+	if mp.GetGrid {
+		mp.getGrid(v1)
+	}
+}
+
+// getFlow copies the flow values into the Flow tensor.
+func (mp *MotionPath) getFlow(v1 *v1vision.V1Vision) {
+	ny := int(mp.FlowGeom.Out.Y)
+	nx := int(mp.FlowGeom.Out.X)
+	for di := range v1.NData {
+		for y := range ny {
+			for x := range nx {
+				for d := range 2 {
+					mp.Flow.Set(v1.Values.Value(mp.flowIndex, di, y, x, 0, d), di, y, x, d)
+				}
+			}
+		}
+	}
+}
+
+// getGrid copies the grid values into the Grid tensor.
+func (mp *MotionPath) getGrid(v1 *v1vision.V1Vision) {
+	for di := range v1.NData {
+		for y := range mp.Motion.GridY {
+			for x := range mp.Motion.GridX {
+				for d := range 2 {
+					for od := range 2 {
+						mp.Grid.Set(v1.Values4D.Value(mp.gridIndex, di, y, x, d, od), di, y, x, d, od)
+					}
+				}
+			}
+		}
+	}
+}
+
+// initMotion resets all motion integration values to 0.
+func (mp *MotionPath) initMotion(v1 *v1vision.V1Vision) {
+	v1.SetAsCurrent()
+	tensor.SetAllFloat64(v1.Values, 0)
+	tensor.SetAllFloat64(v1.Values4D, 0)
+	tensor.SetAllFloat64(v1.Scalars, 0)
+	tensor.SetAllFloat64(&mp.FullField, 0)
+	tensor.SetAllFloat64(&mp.Flow, 0)
+	tensor.SetAllFloat64(&mp.Grid, 0)
+	mp.Motion.Reset()
+	v1.ToGPUInfra()
+}
+
+// resetMotionItem resets all motion integration values to 0
+// for given data-parallel item index.
+func (mp *MotionPath) resetMotionItem(v1 *v1vision.V1Vision, ni int) {
+	v1.ResetItem(ni)
+	mp.Motion.ResetItem(ni)
+	tensor.SetAllFloat64(mp.FullField.SubSpace(ni), 0)
+	if mp.GetFlow {
+		tensor.SetAllFloat64(mp.Flow.SubSpace(ni), 0)
+	}
+	if mp.GetGrid {
+		tensor.SetAllFloat64(mp.Grid.SubSpace(ni), 0)
+	}
+}
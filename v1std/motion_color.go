@@ -0,0 +1,143 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/dog"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// MotionColor computes starburst-amacrine style motion processing and
+// resulting summary full-field motion values, on color-opponent
+// difference-of-gaussian (DoG) filtering of Red - Green and
+// Blue - Yellow contrasts (as in [DoGColor]), so that motion can be
+// computed for isoluminant stimuli.
+// Call Defaults and then set any custom params, then call Config.
+// Results are in FullField and other [MotionPath] outputs after Run().
+type MotionColor struct {
+	// GPU means use the GPU by default (does GPU initialization) in Config.
+	// To change what is actually used at the moment of running,
+	// set [v1vision.UseGPU].
+	GPU bool
+
+	// LGN DoG filter parameters.
+	DoG dog.Filter
+
+	// Geom is geometry of input, output.
+	Geom v1vision.Geom `edit:"-"`
+
+	// MotionPath has the motion parameters and outputs.
+	// Star has [NData, Y, X, Polarity, 8], where Polarity is DoG polarity,
+	// and 8 is Left, Right, Down, Up for Red vs. Green and then
+	// Blue vs. Yellow. See [MotionColor.Layout].
+	MotionPath
+
+	// V1 is the V1Vision filter processing system.
+	V1 v1vision.V1Vision `display:"no-inline"`
+}
+
+func (vi *MotionColor) Defaults() {
+	vi.GPU = true
+	vi.DoG.Defaults()
+	vi.DoG.Gain = 8 // color channels are weaker than grey
+	vi.DoG.OnGain = 1
+	vi.Motion.Defaults()
+	vi.SetSize(12, 4)
+}
+
+// SetSize sets the DoG filter size and geom spacing to given values.
+// Default is 12, 4, for a medium-sized filter.
+func (vi *MotionColor) SetSize(sz, spc int) {
+	vi.DoG.Spacing = spc
+	vi.DoG.Size = sz
+	vi.Geom.Set(math32.Vec2i(0, 0), math32.Vec2i(spc, spc), math32.Vec2i(sz, sz))
+}
+
+// Config configures the filtering pipeline with all the current parameters.
+// imageSize is the _content_ size of input image that is passed
+// to RunImage as an RGB Tensor (per [V1Vision.Images] standard format),
+// (i.e., exclusive of the additional border around the image = [Image.Size]).
+// ndata = number of data-parallel inputs to process in parallel.
+func (vi *MotionColor) Config(ndata int, imageSize image.Point) {
+	vi.Geom.SetImageSize(imageSize)
+
+	fn := 2 // number of color-opponent filters
+
+	vi.V1.Init(ndata)
+	img := vi.V1.NewImage(vi.Geom.In.V())
+	wrap := vi.V1.NewImage(vi.Geom.In.V())
+	lmsRG := vi.V1.NewImage(vi.Geom.In.V())
+	lmsBY := vi.V1.NewImage(vi.Geom.In.V())
+
+	vi.V1.NewWrapImage(img, 3, wrap, int(vi.Geom.Border.X), &vi.Geom)
+	vi.V1.NewLMSComponents(wrap, lmsRG, lmsBY, vi.DoG.Gain, &vi.Geom)
+
+	out := vi.V1.NewValues(int(vi.Geom.Out.Y), int(vi.Geom.Out.X), fn)
+	dogFt := vi.V1.NewDoGOnOff(&vi.DoG, &vi.Geom)
+
+	vi.V1.NewConvolveDiff(lmsRG, v1vision.Red, lmsRG, v1vision.Green, dogFt, 0, 1, out, 0, 1, vi.DoG.OnGain, &vi.Geom)
+	vi.V1.NewConvolveDiff(lmsBY, v1vision.Blue, lmsBY, v1vision.Yellow, dogFt, 0, 1, out, 1, 1, vi.DoG.OnGain, &vi.Geom)
+	vi.V1.NewLogValues(out, out, fn, 1.0, &vi.Geom)
+	vi.V1.NewNormDiv(v1vision.MaxScalar, out, out, fn, &vi.Geom)
+
+	vi.configMotion(&vi.V1, out, fn, &vi.Geom)
+
+	vi.V1.SetAsCurrent()
+	if vi.GPU {
+		vi.V1.GPUInit()
+	}
+}
+
+// RunImages runs the configured filtering pipeline
+// on given Image(s), using given [Image] handler.
+func (vi *MotionColor) RunImages(im *Image, imgs ...image.Image) {
+	im.SetImagesRGB(&vi.V1, int(vi.Geom.Border.X), imgs...)
+	vi.Run()
+}
+
+// RunTensor runs the configured filtering pipeline
+// on given RGB Image tensor.
+func (vi *MotionColor) RunTensor(tsr *tensor.Float32) {
+	itsr := vi.V1.Images.SubSpace(0).(*tensor.Float32)
+	itsr.CopyFrom(tsr)
+	vi.Run()
+}
+
+// Run runs the configured filtering pipeline.
+// image in vi.V1.Images[0] must already have been set.
+func (vi *MotionColor) Run() {
+	v1vision.UseGPU = vi.GPU
+	vi.V1.SetAsCurrent()
+	vi.V1.Run(vi.motionVars()...)
+	vi.motionOutputs(&vi.V1)
+}
+
+// Init resets all motion integration values to 0.
+func (vi *MotionColor) Init() {
+	vi.initMotion(&vi.V1)
+}
+
+// ResetItem resets all motion integration values to 0 for given
+// data-parallel item index, e.g., when starting a new video sequence
+// for that item, without affecting the other items.
+func (vi *MotionColor) ResetItem(ni int) {
+	v1vision.UseGPU = vi.GPU
+	vi.resetMotionItem(&vi.V1, ni)
+}
+
+// Layout returns the layout of the Star output (if GetStar),
+// which is valid after Config.
+// The rows are the DoG On, Off polarities, and the columns are the
+// Left, Right, Down, Up motion directions for RedGreen and then
+// BlueYellow, e.g., RedGreen_Left.
+// Note that the Star output is larger than the Layout Shape:
+// use [Layout.Exact] to get an exact copy.
+func (vi *MotionColor) Layout() *Layout {
+	return vi.starLayout(polarityRows("", DoG, AllColors), []Label{{Name: "RedGreen", Color: RedGreen}, {Name: "BlueYellow", Color: BlueYellow}}, &vi.Geom)
+}
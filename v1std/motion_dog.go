@@ -10,7 +10,6 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/lab/tensor"
 	"github.com/emer/v1vision/dog"
-	"github.com/emer/v1vision/motion"
 	"github.com/emer/v1vision/v1vision"
 )
 
@@ -28,27 +27,16 @@ type MotionDoG struct {
 	// LGN DoG filter parameters.
 	DoG dog.Filter
 
-	// Motion filter parameters.
-	Motion motion.Params
-
 	// Geom is geometry of input, output.
 	Geom v1vision.Geom `edit:"-"`
 
-	// FullField has the integrated FullField output: [NData, 2, 2].
-	// Use [motion.Directions] for 1D indexes (is 2x2 for [L,R][D,U]).
-	FullField tensor.Float32 `display:"no-inline"`
-
-	// GetStar retrieves the star values. Otherwise, just the full-field.
-	GetStar bool
+	// MotionPath has the motion parameters and outputs.
+	// Star has [NData, Y, X, Polarity, 4], where Polarity is DoG polarity,
+	// and 4 is for Left, Right, Down, Up. See [MotionDoG.Layout].
+	MotionPath
 
 	// V1 is the V1Vision filter processing system.
 	V1 v1vision.V1Vision `display:"no-inline"`
-
-	// Star has the star values, if GetStar is true,
-	// pointing to Values in V1.
-	// [NData, Y, X, Polarity, 4], where Polarity is DoG polarity, and 4 is for
-	// Left, Right, Down, Up.
-	Star *tensor.Float32 `display:"no-inline"`
 }
 
 func (vi *MotionDoG) Defaults() {
@@ -73,7 +61,6 @@ func (vi *MotionDoG) SetSize(sz, spc int) {
 // ndata = number of data-parallel inputs to process in parallel.
 func (vi *MotionDoG) Config(ndata int, imageSize image.Point) {
 	vi.Geom.SetImageSize(imageSize)
-	vi.FullField.SetShapeSizes(ndata, 2, 2)
 
 	fn := 1 // number of filters in DoG
 
@@ -86,16 +73,7 @@ func (vi *MotionDoG) Config(ndata int, imageSize image.Point) {
 	vi.V1.NewLogValues(out, out, fn, 1.0, &vi.Geom)
 	vi.V1.NewNormDiv(v1vision.MaxScalar, out, out, fn, &vi.Geom)
 
-	vi.Motion.DoGSumScalarIndex = vi.V1.NewAggScalar(v1vision.SumScalar, out, fn, &vi.Geom)
-	fastIdx := vi.V1.NewMotionIntegrate(out, fn, vi.Motion.FastTau, vi.Motion.SlowTau, &vi.Geom)
-	starIdx := vi.V1.NewMotionStar(fastIdx, fn, vi.Motion.Gain, &vi.Geom)
-	vi.Motion.FFScalarIndex = vi.V1.NewMotionFullField(starIdx, fn, &vi.Geom)
-
-	if vi.GetStar {
-		out4 := vi.V1.NewValues4D(int(vi.Geom.Out.Y), int(vi.Geom.Out.X), 2, 4)
-		vi.Star.SetShapeSizes(ndata, int(vi.Geom.Out.Y), int(vi.Geom.Out.X), 2, 4)
-		vi.V1.NewTo4D(starIdx, out4, 2, 4, 0, &vi.Geom)
-	}
+	vi.configMotion(&vi.V1, out, fn, &vi.Geom)
 
 	vi.V1.SetAsCurrent()
 	if vi.GPU {
@@ -123,24 +101,29 @@ func (vi *MotionDoG) RunTensor(tsr *tensor.Float32) {
 func (vi *MotionDoG) Run() {
 	v1vision.UseGPU = vi.GPU
 	vi.V1.SetAsCurrent()
-
-	vals := []v1vision.GPUVars{v1vision.ScalarsVar, v1vision.ValuesVar}
-	if vi.GetStar {
-		vals = append(vals, v1vision.Values4DVar)
-	}
-	vi.V1.Run(vals...)
-	vi.Motion.FullFieldInteg(vi.V1.NData, vi.V1.Scalars, &vi.FullField)
-	if vi.GetStar { // assumes star at 0
-		vi.Star = vi.V1.Values4D.SubSpace(0).(*tensor.Float32)
-	}
+	vi.V1.Run(vi.motionVars()...)
+	vi.motionOutputs(&vi.V1)
 }
 
 // Init resets all motion integration values to 0.
 func (vi *MotionDoG) Init() {
-	vi.V1.SetAsCurrent()
-	tensor.SetAllFloat64(vi.V1.Values, 0)
-	tensor.SetAllFloat64(vi.V1.Scalars, 0)
-	tensor.SetAllFloat64(&vi.FullField, 0)
-	vi.Motion.NormInteg = 0
-	vi.V1.ToGPUInfra()
+	vi.initMotion(&vi.V1)
+}
+
+// ResetItem resets all motion integration values to 0 for given
+// data-parallel item index, e.g., when starting a new video sequence
+// for that item, without affecting the other items.
+func (vi *MotionDoG) ResetItem(ni int) {
+	v1vision.UseGPU = vi.GPU
+	vi.resetMotionItem(&vi.V1, ni)
+}
+
+// Layout returns the layout of the Star output (if GetStar),
+// which is valid after Config.
+// The rows are the DoG On, Off polarities, and the columns are the
+// Left, Right, Down, Up motion directions.
+// Note that the Star output is larger than the Layout Shape:
+// use [Layout.Exact] to get an exact copy.
+func (vi *MotionDoG) Layout() *Layout {
+	return vi.starLayout(polarityRows("", DoG, Grey), []Label{{Color: Grey}}, &vi.Geom)
 }
@@ -0,0 +1,131 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/gabor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// MotionGabor computes starburst-amacrine style motion processing and
+// resulting summary full-field motion values, on greyscale oriented
+// V1 simple-cell gabor filtering, providing orientation-specific
+// motion energy.
+// Call Defaults and then set any custom params, then call Config.
+// Results are in FullField and other [MotionPath] outputs after Run().
+type MotionGabor struct {
+	// GPU means use the GPU by default (does GPU initialization) in Config.
+	// To change what is actually used at the moment of running,
+	// set [v1vision.UseGPU].
+	GPU bool
+
+	// V1 simple gabor filter parameters.
+	V1sGabor gabor.Filter
+
+	// Geom is geometry of input, output.
+	Geom v1vision.Geom `edit:"-"`
+
+	// MotionPath has the motion parameters and outputs.
+	// Star has [NData, Y, X, Polarity, 4 * NAngles], where Polarity is
+	// gabor polarity, and 4 is Left, Right, Down, Up for each angle.
+	// See [MotionGabor.Layout].
+	MotionPath
+
+	// V1 is the V1Vision filter processing system.
+	V1 v1vision.V1Vision `display:"no-inline"`
+}
+
+func (vi *MotionGabor) Defaults() {
+	vi.GPU = true
+	vi.V1sGabor.Defaults()
+	vi.Motion.Defaults()
+	vi.SetSize(12, 4)
+}
+
+// SetSize sets the V1sGabor filter size and geom spacing to given values.
+// Default is 12, 4, for a medium-sized filter.
+func (vi *MotionGabor) SetSize(sz, spc int) {
+	vi.V1sGabor.SetSize(sz, spc)
+	vi.Geom.Set(math32.Vec2i(0, 0), math32.Vec2i(spc, spc), math32.Vec2i(sz, sz))
+}
+
+// Config configures the filtering pipeline with all the current parameters.
+// imageSize is the _content_ size of input image that is passed
+// to RunImage as an RGB Tensor (per [V1Vision.Images] standard format),
+// (i.e., exclusive of the additional border around the image = [Image.Size]).
+// ndata = number of data-parallel inputs to process in parallel.
+func (vi *MotionGabor) Config(ndata int, imageSize image.Point) {
+	vi.Geom.SetImageSize(imageSize)
+
+	fn := vi.V1sGabor.NAngles
+
+	vi.V1.Init(ndata)
+	img := vi.V1.NewImage(vi.Geom.In.V())
+	wrap := vi.V1.NewImage(vi.Geom.In.V())
+
+	vi.V1.NewWrapImage(img, 0, wrap, int(vi.Geom.Border.X), &vi.Geom)
+	_, out := vi.V1.NewGabor(wrap, 0, &vi.V1sGabor, &vi.Geom)
+	vi.V1.NewLogValues(out, out, fn, 1.0, &vi.Geom)
+	vi.V1.NewNormDiv(v1vision.MaxScalar, out, out, fn, &vi.Geom)
+
+	vi.configMotion(&vi.V1, out, fn, &vi.Geom)
+
+	vi.V1.SetAsCurrent()
+	if vi.GPU {
+		vi.V1.GPUInit()
+	}
+}
+
+// RunImages runs the configured filtering pipeline
+// on given Image(s), using given [Image] handler.
+func (vi *MotionGabor) RunImages(im *Image, imgs ...image.Image) {
+	im.SetImagesGrey(&vi.V1, int(vi.Geom.Border.X), imgs...)
+	vi.Run()
+}
+
+// RunTensor runs the configured filtering pipeline
+// on given Image tensor.
+func (vi *MotionGabor) RunTensor(tsr *tensor.Float32) {
+	itsr := vi.V1.Images.SubSpace(0).(*tensor.Float32)
+	itsr.CopyFrom(tsr)
+	vi.Run()
+}
+
+// Run runs the configured filtering pipeline.
+// image in vi.V1.Images[0] must already have been set.
+func (vi *MotionGabor) Run() {
+	v1vision.UseGPU = vi.GPU
+	vi.V1.SetAsCurrent()
+	vi.V1.Run(vi.motionVars()...)
+	vi.motionOutputs(&vi.V1)
+}
+
+// Init resets all motion integration values to 0.
+func (vi *MotionGabor) Init() {
+	vi.initMotion(&vi.V1)
+}
+
+// ResetItem resets all motion integration values to 0 for given
+// data-parallel item index, e.g., when starting a new video sequence
+// for that item, without affecting the other items.
+func (vi *MotionGabor) ResetItem(ni int) {
+	v1vision.UseGPU = vi.GPU
+	vi.resetMotionItem(&vi.V1, ni)
+}
+
+// Layout returns the layout of the Star output (if GetStar),
+// which is valid after Config.
+// The rows are the gabor On, Off polarities, and the columns are the
+// Left, Right, Down, Up motion directions for each gabor angle,
+// e.g., 45_Left.
+// Note that the Star output is larger than the Layout Shape:
+// use [Layout.Exact] to get an exact copy.
+func (vi *MotionGabor) Layout() *Layout {
+	return vi.starLayout(polarityRows("", Simple, Grey), angleCols(vi.V1sGabor.NAngles), &vi.Geom)
+}
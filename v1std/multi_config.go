@@ -0,0 +1,299 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/iox/jsonx"
+	"cogentcore.org/core/base/iox/tomlx"
+	"github.com/emer/v1vision/dog"
+	"github.com/emer/v1vision/gabor"
+	"github.com/emer/v1vision/kwta"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// V1cScale configures one size of V1c filtering in a [MultiConfig].
+// See [V1cParams.Config].
+type V1cScale struct {
+	// Name is the name of this size, e.g., L16.
+	Name string
+
+	// Zoom is the zoom factor: divides effective image size.
+	Zoom float32
+
+	// Border is the border around the zoomed image, which should be
+	// consistent across all sizes, so that the padded image size is the same.
+	Border int
+
+	// Size is the V1s gabor filter size.
+	Size int
+
+	// Spacing is the V1s gabor filter spacing.
+	Spacing int
+}
+
+// DoGScale configures one size of DoG color filtering in a [MultiConfig].
+// See [DoGColorParams.Config].
+type DoGScale struct {
+	// Name is the name of this size, e.g., L16.
+	Name string
+
+	// Zoom is the zoom factor: divides effective image size.
+	Zoom float32
+
+	// Border is the border around the zoomed image, which should be
+	// consistent across all sizes, so that the padded image size is the same.
+	Border int
+
+	// Size is the DoG filter size, which is also its spacing.
+	Size int
+}
+
+// MultiConfig is a declarative configuration of a [V1cMulti] pipeline,
+// which can be saved to and loaded from TOML or JSON files, so that
+// settings can be varied without recompiling.
+// Any parameters not specified in a file retain their current values,
+// except that the V1c and DoG lists of scales replace the current
+// ones if specified.
+type MultiConfig struct {
+	// ImageSize is the size of the image content.
+	ImageSize image.Point
+
+	// SplitColor records separate rows in V1c simple summary for each color.
+	SplitColor bool
+
+	// Grey only does V1c filtering on the greyscale channel.
+	Grey bool
+
+	// ColorGain is an extra gain for color channels.
+	ColorGain float32
+
+	// V1sNeighInhib specifies neighborhood inhibition for V1s.
+	V1sNeighInhib kwta.NeighInhib
+
+	// V1sKWTA has the kwta inhibition parameters for V1s.
+	V1sKWTA kwta.KWTA
+
+	// DoGKWTA has the kwta inhibition parameters for DoG Color blobs.
+	DoGKWTA kwta.KWTA
+
+	// OutKWTA has the kwta inhibition parameters applied to the
+	// assembled V1c output of each size.
+	OutKWTA kwta.KWTA
+
+	// Gabor has the V1s gabor filter parameters shared by all V1c sizes,
+	// except for Size, Spacing and Wavelength, which are set per size.
+	Gabor gabor.Filter
+
+	// DoG has the DoG color filter parameters shared by all DoG sizes,
+	// except for Size and Spacing, which are set per size.
+	DoG dog.Filter
+
+	// V1c are the V1c sizes.
+	V1c []V1cScale
+
+	// DoGColor are the DoG color sizes.
+	DoGColor []DoGScale
+}
+
+// Defaults sets the config to the [V1cMulti] defaults with the
+// [V1cMulti.StdLowMed16DegZoom1] sizes.
+func (mc *MultiConfig) Defaults() {
+	var vi V1cMulti
+	vi.Defaults()
+	vi.StdLowMed16DegZoom1()
+	mc.From(&vi)
+}
+
+// From sets the config from the current parameters of given
+// [V1cMulti], e.g., to save its effective configuration.
+// The shared Gabor and DoG parameters are taken from the first size,
+// or the [V1cParams.Config] and [DoGColorParams.Config] defaults if none.
+func (mc *MultiConfig) From(vi *V1cMulti) {
+	var vp V1cParams
+	mc.Gabor = vp.Config("", 1, 0, 12, 4).V1sGabor
+	var dp DoGColorParams
+	mc.DoG = dp.Config("", 1, 0, 8).DoG
+	mc.ImageSize = vi.Image.Size
+	mc.SplitColor = vi.SplitColor
+	mc.Grey = vi.Grey
+	mc.ColorGain = vi.ColorGain
+	mc.V1sNeighInhib = vi.V1sNeighInhib
+	mc.V1sKWTA = vi.V1sKWTA
+	mc.DoGKWTA = vi.DoGKWTA
+	mc.OutKWTA = vi.OutKWTA
+	mc.V1c = make([]V1cScale, len(vi.V1cParams))
+	for i, vp := range vi.V1cParams {
+		if i == 0 {
+			mc.Gabor = vp.V1sGabor
+		}
+		mc.V1c[i] = V1cScale{Name: vp.Name, Zoom: vp.Zoom, Border: int(vp.V1sGeom.Border.X), Size: vp.V1sGabor.Size, Spacing: vp.V1sGabor.Spacing}
+	}
+	mc.DoGColor = make([]DoGScale, len(vi.DoGParams))
+	for i, vp := range vi.DoGParams {
+		if i == 0 {
+			mc.DoG = vp.DoG
+		}
+		mc.DoGColor[i] = DoGScale{Name: vp.Name, Zoom: vp.Zoom, Border: int(vp.Geom.Border.X), Size: vp.DoG.Size}
+	}
+}
+
+// Validate returns an error if the config is not valid, including
+// if the padded image sizes are not the same for all sizes,
+// which is required by [V1cMulti.Config].
+func (mc *MultiConfig) Validate() error {
+	var errs []error
+	if mc.ImageSize.X <= 0 || mc.ImageSize.Y <= 0 {
+		errs = append(errs, fmt.Errorf("ImageSize must be > 0: %v", mc.ImageSize))
+	}
+	if len(mc.V1c) == 0 {
+		errs = append(errs, fmt.Errorf("at least one V1c size is required"))
+	}
+	if mc.Gabor.NAngles != 4 {
+		errs = append(errs, fmt.Errorf("Gabor.NAngles must be 4, not: %d", mc.Gabor.NAngles))
+	}
+	names := map[string]bool{}
+	check := func(kind, name string, zoom float32, border, size, spacing int) {
+		nm := kind + " " + name
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("%s size Name must be set", kind))
+		case names[nm]:
+			errs = append(errs, fmt.Errorf("%s: duplicate Name", nm))
+		}
+		names[nm] = true
+		if zoom <= 0 || border < 0 || size <= 0 || spacing <= 0 {
+			errs = append(errs, fmt.Errorf("%s: Zoom, Size and Spacing must be > 0 and Border >= 0", nm))
+		}
+	}
+	for _, sc := range mc.V1c {
+		check("V1c", sc.Name, sc.Zoom, sc.Border, sc.Size, sc.Spacing)
+	}
+	for _, sc := range mc.DoGColor {
+		check("DoG", sc.Name, sc.Zoom, sc.Border, sc.Size, sc.Size)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var vi V1cMulti
+	mc.set(&vi)
+	inSz := image.Point{}
+	checkIn := func(nm string, geom *v1vision.Geom) {
+		sz := image.Point{int(geom.In.X), int(geom.In.Y)}
+		if inSz == (image.Point{}) {
+			inSz = sz
+		} else if sz != inSz {
+			errs = append(errs, fmt.Errorf("%s: padded image size %v != %v: Border must be consistent with Zoom across sizes", nm, sz, inSz))
+		}
+	}
+	for _, vp := range vi.V1cParams {
+		vp.SetImageSize(mc.ImageSize)
+		checkIn("V1c "+vp.Name, &vp.V1sGeom)
+	}
+	for _, vp := range vi.DoGParams {
+		vp.SetImageSize(mc.ImageSize)
+		checkIn("DoG "+vp.Name, &vp.Geom)
+	}
+	return errors.Join(errs...)
+}
+
+// Apply validates the config and sets the parameters of given
+// [V1cMulti] from it, replacing any existing sizes.
+// Config must be called after this.
+func (mc *MultiConfig) Apply(vi *V1cMulti) error {
+	if err := mc.Validate(); err != nil {
+		return err
+	}
+	mc.set(vi)
+	return nil
+}
+
+// set sets the parameters of given [V1cMulti] from the config.
+func (mc *MultiConfig) set(vi *V1cMulti) {
+	vi.Image.Size = mc.ImageSize
+	vi.SplitColor = mc.SplitColor
+	vi.Grey = mc.Grey
+	vi.ColorGain = mc.ColorGain
+	vi.V1sNeighInhib = mc.V1sNeighInhib
+	mc.update()
+	vi.V1sKWTA = mc.V1sKWTA
+	vi.DoGKWTA = mc.DoGKWTA
+	vi.OutKWTA = mc.OutKWTA
+	vi.V1cParams = nil
+	for _, sc := range mc.V1c {
+		vp := vi.AddV1cParams().Config(sc.Name, sc.Zoom, sc.Border, sc.Size, sc.Spacing)
+		vp.V1sGabor = mc.Gabor
+		vp.V1sGabor.SetSize(sc.Size, sc.Spacing)
+	}
+	vi.DoGParams = nil
+	for _, sc := range mc.DoGColor {
+		vp := vi.AddDoGParams().Config(sc.Name, sc.Zoom, sc.Border, sc.Size)
+		vp.DoG = mc.DoG
+		vp.DoG.Size = sc.Size
+		vp.DoG.Spacing = sc.Size
+	}
+}
+
+// update updates the derived kwta parameters, which are not saved.
+func (mc *MultiConfig) update() {
+	mc.V1sKWTA.Update()
+	mc.DoGKWTA.Update()
+	mc.OutKWTA.Update()
+}
+
+// Open opens the config from a TOML or JSON file, per the file
+// extension, on top of the current values, and validates it.
+func (mc *MultiConfig) Open(filename string) error {
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".toml":
+		err = tomlx.Open(mc, filename)
+	case ".json":
+		err = jsonx.Open(mc, filename)
+	default:
+		return fmt.Errorf("config file must be .toml or .json: %q", filename)
+	}
+	if err != nil {
+		return err
+	}
+	mc.update()
+	return mc.Validate()
+}
+
+// Save saves the config to a TOML or JSON file, per the file extension.
+func (mc *MultiConfig) Save(filename string) error {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".toml":
+		return tomlx.Save(mc, filename)
+	case ".json":
+		return jsonx.SaveIndent(mc, filename)
+	}
+	return fmt.Errorf("config file must be .toml or .json: %q", filename)
+}
+
+// OpenConfig opens a [MultiConfig] from a TOML or JSON file, on top of the
+// current parameters, and applies it. Config must be called after this.
+func (vi *V1cMulti) OpenConfig(filename string) error {
+	var mc MultiConfig
+	mc.From(vi)
+	if err := mc.Open(filename); err != nil {
+		return err
+	}
+	return mc.Apply(vi)
+}
+
+// SaveConfig saves the current effective parameters as a [MultiConfig]
+// to a TOML or JSON file.
+func (vi *V1cMulti) SaveConfig(filename string) error {
+	var mc MultiConfig
+	mc.From(vi)
+	return mc.Save(filename)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiPreset is a named [V1cMulti] configuration of the image size
+// and the V1c and DoG color filter sizes, selectable by name with
+// [V1cMulti.SetPreset].
+type MultiPreset struct {
+	// Name is the name of the preset.
+	Name string
+
+	// Doc describes the preset, including its output shapes,
+	// as [Y, X, Rows, Cols] per image.
+	Doc string
+
+	// Config adds the filter sizes to the V1cMulti, and sets
+	// the image size and any other parameters.
+	Config func(vi *V1cMulti)
+}
+
+// MultiPresets are the available [V1cMulti] presets. The Grey variants
+// only do greyscale V1c filtering (see [V1cMulti.Grey]), with 5 rows
+// instead of 9, and no DoG color filtering.
+var MultiPresets = []*MultiPreset{
+	{Name: "LowMed16DegZoom1", Config: (*V1cMulti).StdLowMed16DegZoom1,
+		Doc: "16 degree FOV, 128x128: V1c L16, M8: [8 8 9 4]; M16, H8: [16 16 9 4]; DoG L16, L8: [8 8 2 2]; M16, M8: [16 16 2 2]"},
+	{Name: "LowMed16DegNoDoG", Config: (*V1cMulti).StdLowMed16DegNoDoG,
+		Doc: "16 degree FOV, 128x128: V1c L16: [8 8 9 4]; M16: [16 16 9 4]"},
+	{Name: "LowMedHigh16DegZoom1", Config: (*V1cMulti).StdLowMedHigh16DegZoom1,
+		Doc: "16 degree FOV, 128x128: V1c L16, M8: [8 8 9 4]; M16, H8: [16 16 9 4]; H16: [32 32 9 4]; DoG L16, L8: [8 8 2 2]; M16, M8: [16 16 2 2]"},
+	{Name: "LowMedHigh8Deg", Config: (*V1cMulti).StdLowMedHigh8Deg,
+		Doc: "8 degree FOV, 64x64: V1c L8: [4 4 9 4]; M8: [8 8 9 4]; H8: [16 16 9 4]; DoG L8: [4 4 2 2]; M8: [8 8 2 2]"},
+	{Name: "LowMed32DegZoom1", Config: (*V1cMulti).StdLowMed32DegZoom1,
+		Doc: "32 degree FOV, 256x256: V1c L32, M16: [16 16 9 4]; M32, H16: [32 32 9 4]; DoG L32, L16: [16 16 2 2]; M32, M16: [32 32 2 2]"},
+	{Name: "LowMed16DegZoom1Grey", Config: greyPreset((*V1cMulti).StdLowMed16DegZoom1),
+		Doc: "16 degree FOV, 128x128: V1c L16, M8: [8 8 5 4]; M16, H8: [16 16 5 4]"},
+	{Name: "LowMedHigh16DegZoom1Grey", Config: greyPreset((*V1cMulti).StdLowMedHigh16DegZoom1),
+		Doc: "16 degree FOV, 128x128: V1c L16, M8: [8 8 5 4]; M16, H8: [16 16 5 4]; H16: [32 32 5 4]"},
+	{Name: "LowMedHigh8DegGrey", Config: greyPreset((*V1cMulti).StdLowMedHigh8Deg),
+		Doc: "8 degree FOV, 64x64: V1c L8: [4 4 5 4]; M8: [8 8 5 4]; H8: [16 16 5 4]"},
+	{Name: "LowMed32DegZoom1Grey", Config: greyPreset((*V1cMulti).StdLowMed32DegZoom1),
+		Doc: "32 degree FOV, 256x256: V1c L32, M16: [16 16 5 4]; M32, H16: [32 32 5 4]"},
+}
+
+// greyPreset returns a greyscale-only version of given preset config,
+// without DoG color filtering.
+func greyPreset(config func(vi *V1cMulti)) func(vi *V1cMulti) {
+	return func(vi *V1cMulti) {
+		config(vi)
+		vi.Grey = true
+		vi.DoGParams = nil
+	}
+}
+
+// MultiPresetNames returns the names of the [MultiPresets].
+func MultiPresetNames() []string {
+	nms := make([]string, len(MultiPresets))
+	for i, mp := range MultiPresets {
+		nms[i] = mp.Name
+	}
+	return nms
+}
+
+// MultiPresetByName returns the [MultiPresets] entry with given name,
+// or an error if not found.
+func MultiPresetByName(name string) (*MultiPreset, error) {
+	for _, mp := range MultiPresets {
+		if mp.Name == name {
+			return mp, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown V1cMulti preset: %q, must be one of: %s", name, strings.Join(MultiPresetNames(), ", "))
+}
+
+// SetPreset configures the filter sizes and image size from the
+// [MultiPresets] entry with given name, replacing any existing
+// sizes. Other parameters are not affected, except for Grey,
+// which is set per the preset. Config must be called after this.
+func (vi *V1cMulti) SetPreset(name string) error {
+	mp, err := MultiPresetByName(name)
+	if err != nil {
+		return err
+	}
+	vi.V1cParams = nil
+	vi.DoGParams = nil
+	vi.Grey = false
+	mp.Config(vi)
+	return nil
+}
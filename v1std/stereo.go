@@ -0,0 +1,175 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/gabor"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// Stereo computes binocular disparity energy-model responses on
+// greyscale left and right eye images, using gabor quadrature pair
+// filters, over a range of position and / or phase disparities.
+// Call Defaults and then set any custom params, then call Config.
+// Results are in Output tensor after Run().
+type Stereo struct {
+	// GPU means use the GPU by default (does GPU initialization) in Config.
+	// To change what is actually used at the moment of running,
+	// set [v1vision.UseGPU].
+	GPU bool
+
+	// Gabor filter parameters for the quadrature pairs.
+	// The Phase is not used: even and odd phase filters are
+	// always used.
+	Gabor gabor.Filter
+
+	// NDisparities is the number of disparities to compute,
+	// centered on zero disparity. An odd number is recommended,
+	// so that zero disparity is included. See [Stereo.Shift].
+	NDisparities int
+
+	// DispStep is the position disparity step, in pixels, between each
+	// disparity: the right eye image is shifted horizontally by this
+	// amount for each step away from the center.
+	DispStep int
+
+	// PhaseStep is the phase disparity step, in degrees, between each
+	// disparity: the right eye filter phase is shifted by this amount
+	// for each step away from the center.
+	PhaseStep float32
+
+	// Gain is a multiplier on the binocular energy output.
+	Gain float32
+
+	// Geom is geometry of input, output.
+	Geom v1vision.Geom `edit:"-"`
+
+	// V1 is the V1Vision filter processing system.
+	V1 v1vision.V1Vision `display:"no-inline"`
+
+	// Output has the resulting binocular energy outputs, pointing to
+	// Values4D in V1: [NData, Y, X, NDisparities, NAngles].
+	// See [Stereo.Layout] for the names.
+	Output *tensor.Float32 `display:"no-inline"`
+}
+
+func (vi *Stereo) Defaults() {
+	vi.GPU = true
+	vi.Gabor.Defaults()
+	vi.NDisparities = 5
+	vi.DispStep = 1
+	vi.PhaseStep = 0
+	vi.Gain = 1
+	vi.SetSize(8, 2)
+}
+
+// SetSize sets the Gabor filter size and geom spacing to given values.
+// Default is 8, 2.
+func (vi *Stereo) SetSize(sz, spc int) {
+	vi.Gabor.SetSize(sz, spc)
+	vi.Geom.Set(math32.Vec2i(0, 0), math32.Vec2i(spc, spc), math32.Vec2i(sz, sz))
+}
+
+// Shift returns the horizontal position shift in pixels of the right
+// eye image for given disparity index. Positive values correspond to
+// features in the right image being to the right of those in the left.
+func (vi *Stereo) Shift(di int) int {
+	return int(v1vision.BinocularShift(int32(di), int32(vi.NDisparities), int32(vi.DispStep)))
+}
+
+// PhaseShift returns the horizontal disparity in pixels equivalent
+// to the phase disparity for given disparity index, for vertically
+// oriented filters (the phase shift as a proportion of the Wavelength).
+// Positive values have the same sense as in [Stereo.Shift].
+func (vi *Stereo) PhaseShift(di int) float32 {
+	phs := (float32(di) - 0.5*float32(vi.NDisparities-1)) * vi.PhaseStep
+	return phs / 360 * vi.Gabor.Wavelength
+}
+
+// Config configures the filtering pipeline with all the current parameters.
+// imageSize is the _content_ size of input images that are passed
+// to RunImage as RGB Tensors (per [V1Vision.Images] standard format),
+// (i.e., exclusive of the additional border around the image = [Image.Size]).
+// The border is set to accommodate the maximum disparity shift.
+// ndata = number of data-parallel inputs to process in parallel.
+func (vi *Stereo) Config(ndata int, imageSize image.Point) {
+	maxShift := max(-vi.Shift(0), vi.Shift(vi.NDisparities-1))
+	bord := int(vi.Geom.FilterRt.X) + maxShift
+	vi.Geom.Border.SetV(math32.Vec2i(bord, bord))
+	vi.Geom.SetImageSize(imageSize)
+
+	nang := vi.Gabor.NAngles
+	nd := vi.NDisparities
+
+	vi.V1.Init(ndata)
+	left := vi.V1.NewImage(vi.Geom.In.V())
+	right := vi.V1.NewImage(vi.Geom.In.V())
+	wrapL := vi.V1.NewImage(vi.Geom.In.V())
+	wrapR := vi.V1.NewImage(vi.Geom.In.V())
+
+	vi.V1.NewWrapImage(left, 0, wrapL, bord, &vi.Geom)
+	vi.V1.NewWrapImage(right, 0, wrapR, bord, &vi.Geom)
+
+	ftyp := vi.V1.NewBinocularFilters(&vi.Gabor, nd, vi.PhaseStep)
+	vi.V1.NewBinocularEnergy(wrapL, wrapR, 0, ftyp, nang, nd, vi.DispStep, vi.Gain, &vi.Geom)
+
+	vi.V1.SetAsCurrent()
+	if vi.GPU {
+		vi.V1.GPUInit()
+	}
+}
+
+// RunImages runs the configured filtering pipeline
+// on given left and right eye Image(s), using given [Image] handler.
+func (vi *Stereo) RunImages(im *Image, left, right []image.Image) {
+	im.SetImagesGreyIndex(&vi.V1, 0, int(vi.Geom.Border.X), left...)
+	im.SetImagesGreyIndex(&vi.V1, 1, int(vi.Geom.Border.X), right...)
+	vi.Run()
+}
+
+// RunTensors runs the configured filtering pipeline
+// on given left and right eye Image tensors.
+func (vi *Stereo) RunTensors(left, right *tensor.Float32) {
+	vi.V1.Images.SubSpace(0).(*tensor.Float32).CopyFrom(left)
+	vi.V1.Images.SubSpace(1).(*tensor.Float32).CopyFrom(right)
+	vi.Run()
+}
+
+// Run runs the configured filtering pipeline.
+// images in vi.V1.Images[0] (left) and [1] (right) must already have been set.
+func (vi *Stereo) Run() {
+	v1vision.UseGPU = vi.GPU
+	vi.V1.SetAsCurrent()
+	vi.V1.Run(v1vision.Values4DVar)
+	vi.Output = vi.V1.Values4D.SubSpace(0).(*tensor.Float32)
+}
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are the disparities, with Index the position shift in pixels
+// from [Stereo.Shift], named by the shift (e.g., D-2 .. D2) and / or
+// the phase shift in degrees if PhaseStep is set (e.g., P-90 .. P90),
+// and the columns are the gabor angles.
+func (vi *Stereo) Layout() *Layout {
+	nd := vi.NDisparities
+	rows := make([]Label, nd)
+	for di := range rows {
+		shift := vi.Shift(di)
+		var nms []string
+		if vi.DispStep != 0 || vi.PhaseStep == 0 {
+			nms = append(nms, fmt.Sprintf("D%d", shift))
+		}
+		if vi.PhaseStep != 0 {
+			nms = append(nms, fmt.Sprintf("P%g", (float32(di)-0.5*float32(nd-1))*vi.PhaseStep))
+		}
+		rows[di] = Label{Name: strings.Join(nms, "_"), Feature: Disparity, Color: Grey, Index: shift}
+	}
+	return &Layout{Name: "Stereo", Y: int(vi.Geom.Out.Y), X: int(vi.Geom.Out.X), Rows: rows, Cols: angleCols(vi.Gabor.NAngles)}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/dog"
+	"github.com/emer/v1vision/temporal"
+	"github.com/emer/v1vision/v1vision"
+)
+
+// TemporalDoG computes sustained (parvocellular-like) and transient
+// (magnocellular-like) channels from successive video frames,
+// on greyscale difference-of-gaussian (DoG) filtering.
+// The filter state persists across Run calls, and can be reset
+// for individual data-parallel items using ResetItem.
+// Call Defaults and then set any custom params, then call Config.
+// Results are in Output tensor after Run().
+type TemporalDoG struct {
+	// GPU means use the GPU by default (does GPU initialization) in Config.
+	// To change what is actually used at the moment of running,
+	// set [v1vision.UseGPU].
+	GPU bool
+
+	// LGN DoG filter parameters.
+	DoG dog.Filter
+
+	// Temporal filter parameters.
+	Temporal temporal.Params
+
+	// Geom is geometry of input, output.
+	Geom v1vision.Geom `edit:"-"`
+
+	// V1 is the V1Vision filter processing system.
+	V1 v1vision.V1Vision `display:"no-inline"`
+
+	// Output has the resulting temporal filter outputs, pointing to
+	// Values4D in V1: [NData, Y, X, 4, 1], where the 4 rows are:
+	// sustained On, Off, and transient On, Off.
+	// See [TemporalDoG.Layout] for the names.
+	Output *tensor.Float32 `display:"no-inline"`
+}
+
+func (vi *TemporalDoG) Defaults() {
+	vi.GPU = true
+	vi.DoG.Defaults()
+	vi.Temporal.Defaults()
+	vi.SetSize(12, 4)
+}
+
+// SetSize sets the DoG filter size and geom spacing to given values.
+// Default is 12, 4, for a medium-sized filter.
+func (vi *TemporalDoG) SetSize(sz, spc int) {
+	vi.DoG.Spacing = spc
+	vi.DoG.Size = sz
+	vi.Geom.Set(math32.Vec2i(0, 0), math32.Vec2i(spc, spc), math32.Vec2i(sz, sz))
+}
+
+// Config configures the filtering pipeline with all the current parameters.
+// imageSize is the _content_ size of input image that is passed
+// to RunImage as an RGB Tensor (per [V1Vision.Images] standard format),
+// (i.e., exclusive of the additional border around the image = [Image.Size]).
+// ndata = number of data-parallel inputs to process in parallel.
+func (vi *TemporalDoG) Config(ndata int, imageSize image.Point) {
+	vi.Geom.SetImageSize(imageSize)
+
+	fn := 1 // number of filters in DoG
+	tp := &vi.Temporal
+
+	vi.V1.Init(ndata)
+	img := vi.V1.NewImage(vi.Geom.In.V())
+	wrap := vi.V1.NewImage(vi.Geom.In.V())
+
+	vi.V1.NewWrapImage(img, 0, wrap, int(vi.Geom.Border.X), &vi.Geom)
+	_, out := vi.V1.NewDoG(wrap, 0, &vi.DoG, &vi.Geom)
+	vi.V1.NewLogValues(out, out, fn, 1.0, &vi.Geom)
+	vi.V1.NewNormDiv(v1vision.MaxScalar, out, out, fn, &vi.Geom)
+
+	sout := vi.V1.NewTemporalFilter(out, fn, v1vision.Exponential, tp.SustainedTau, 0, tp.SustainedGain, &vi.Geom)
+	tout := vi.V1.NewTemporalFilter(out, fn, v1vision.Biphasic, tp.FastTau, tp.SlowTau, tp.TransientGain, &vi.Geom)
+
+	ly := vi.Layout()
+	out4 := vi.V1.NewValues4D(int(vi.Geom.Out.Y), int(vi.Geom.Out.X), len(ly.Rows), fn)
+	vi.V1.NewTo4D(sout, out4, 2, fn, ly.RowIndex("Sustained_On"), &vi.Geom)
+	vi.V1.NewTo4D(tout, out4, 2, fn, ly.RowIndex("Transient_On"), &vi.Geom)
+
+	vi.V1.SetAsCurrent()
+	if vi.GPU {
+		vi.V1.GPUInit()
+	}
+}
+
+// RunImages runs the configured filtering pipeline
+// on given Image(s), using given [Image] handler.
+func (vi *TemporalDoG) RunImages(im *Image, imgs ...image.Image) {
+	im.SetImagesGrey(&vi.V1, int(vi.Geom.Border.X), imgs...)
+	vi.Run()
+}
+
+// RunTensor runs the configured filtering pipeline
+// on given Image tensor.
+func (vi *TemporalDoG) RunTensor(tsr *tensor.Float32) {
+	itsr := vi.V1.Images.SubSpace(0).(*tensor.Float32)
+	itsr.CopyFrom(tsr)
+	vi.Run()
+}
+
+// Run runs the configured filtering pipeline.
+// image in vi.V1.Images[0] must already have been set.
+func (vi *TemporalDoG) Run() {
+	v1vision.UseGPU = vi.GPU
+	vi.V1.SetAsCurrent()
+	vi.V1.Run(v1vision.Values4DVar)
+	vi.Output = vi.V1.Values4D.SubSpace(0).(*tensor.Float32)
+}
+
+// ResetItem resets the temporal filter state for given
+// data-parallel item index, or all items if ni < 0.
+func (vi *TemporalDoG) ResetItem(ni int) {
+	v1vision.UseGPU = vi.GPU
+	vi.V1.ResetItem(ni)
+}
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are Sustained_On, Sustained_Off, Transient_On, Transient_Off,
+// with one Grey column.
+func (vi *TemporalDoG) Layout() *Layout {
+	rows := append(polarityRows("Sustained", Sustained, Grey), polarityRows("Transient", Transient, Grey)...)
+	return &Layout{Name: "TemporalDoG", Y: int(vi.Geom.Out.Y), X: int(vi.Geom.Out.X), Rows: rows, Cols: []Label{{Name: "Grey", Feature: DoG, Color: Grey}}}
+}
@@ -6,18 +6,68 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGColor", IDName: "do-g-color", Doc: "DoGColor does color difference-of-gaussian (DoG) filtering,\non Red - Green and Blue - Yellow opponent color contrasts,\nso that activity reflects presence of a color beyond grey baseline.\nThese capture the activity of the blob chroma sensitive cells.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "DoG", Doc: "LGN DoG filter parameters. Generally have larger fields,\nand no spatial tuning (i.e., OnSigma == OffSigma), consistent\nwith blob cells."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting DoG filter outputs, pointing to Values in V1.\n[Y, X, Polarity, Feature], where Polarity = On (0) vs Off (1) stronger.\nFeature: 0 = Red vs. Green; 1 = Blue vs. Yellow."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGColor", IDName: "do-g-color", Doc: "DoGColor does color difference-of-gaussian (DoG) filtering,\non Red - Green and Blue - Yellow opponent color contrasts,\nso that activity reflects presence of a color beyond grey baseline.\nThese capture the activity of the blob chroma sensitive cells.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "Mask", Doc: "Mask excludes the invalid regions of the input images (e.g., from\na Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]\nregions, so that padding does not generate spurious edge responses."}, {Name: "DoG", Doc: "LGN DoG filter parameters. Generally have larger fields,\nand no spatial tuning (i.e., OnSigma == OffSigma), consistent\nwith blob cells."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "KWTA", Doc: "kwta parameters, providing more contrast across colors."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting DoG filter outputs, pointing to Values in V1.\n[Y, X, Polarity, Feature], where Polarity = On (0) vs Off (1) stronger.\nFeature: 0 = Red vs. Green; 1 = Blue vs. Yellow.\nSee [DoGColor.Layout] for the names."}, {Name: "outIdx"}, {Name: "maskIndex", Doc: "maskIndex is the Images index of the valid-region mask, if Mask."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGGrey", IDName: "do-g-grey", Doc: "DoGGrey does greyscale difference-of-gaussian (DoG) filtering.\nOutput is log-max-normalized.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "DoG", Doc: "LGN DoG filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting DoG filter outputs, pointing to Values in V1.\n[Y, X, Polarity, 1], where Polarity = On (0) vs Off (1) stronger."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGGrey", IDName: "do-g-grey", Doc: "DoGGrey does greyscale difference-of-gaussian (DoG) filtering.\nOutput is log-max-normalized.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "Mask", Doc: "Mask excludes the invalid regions of the input images (e.g., from\na Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]\nregions, so that padding does not generate spurious edge responses."}, {Name: "DoG", Doc: "LGN DoG filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting DoG filter outputs, pointing to Values in V1.\n[Y, X, Polarity, 1], where Polarity = On (0) vs Off (1) stronger.\nSee [DoGGrey.Layout] for the names."}, {Name: "maskIndex", Doc: "maskIndex is the Images index of the valid-region mask, if Mask."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Image", IDName: "image", Doc: "Image manages conversion of a bitmap image into tensor formats for\nsubsequent processing by filters.", Directives: []types.Directive{{Tool: "go", Directive: "generate", Args: []string{"core", "generate", "-add-types"}}}, Fields: []types.Field{{Name: "File", Doc: "File is the name of image file to operate on"}, {Name: "Size", Doc: "Size is the target image size to use. Images will be rescaled to this size."}, {Name: "Image", Doc: "Image is the current input image, as Go [image.Image]."}, {Name: "Tsr", Doc: "Tsr is the current input image as an RGB tensor.\nThis points into the V1Vision.Images input image 0."}, {Name: "LMS", Doc: "LMS components + opponents tensor version of image"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.FrameSource", IDName: "frame-source", Doc: "FrameSource is a source of sequential video frames,\ne.g., [DirFrames], [GIFFrames], [Y4MFrames] or [RawFrames].\nUse [Frames] to deliver frames from multiple sources\nbatched across data-parallel items.", Methods: []types.Method{{Name: "NumFrames", Doc: "NumFrames returns the total number of frames.", Returns: []string{"int"}}, {Name: "Frame", Doc: "Frame returns the frame at given index.", Args: []string{"i"}, Returns: []string{"Image", "error"}}, {Name: "FrameRate", Doc: "FrameRate returns the native frame rate in frames per second,\nor 0 if not known.", Returns: []string{"float32"}}, {Name: "Close", Doc: "Close closes any open files.", Returns: []string{"error"}}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cColor", IDName: "v1c-color", Doc: "V1cColor does color V1 complex (V1c) filtering, starting with\nsimple cells (V1s) and adding length sum and end stopping.\nKWTA inhibition operates on the V1s step.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "SplitColor", Doc: "SplitColor records separate rows in V1c simple summary for each color.\nOtherwise records the max across all colors."}, {Name: "ColorGain", Doc: "ColorGain is an extra gain for color channels,\nwhich are lower contrast in general."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters"}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Output", Doc: "Output has the resulting V1c filter outputs, pointing to Values4D in V1.\nInner Y, X dimensions are 5 x 4, where the 4 are the gabor angles\n(0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,\nand 2 polarities of V1simple."}, {Name: "fadeOpIdx"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Frames", IDName: "frames", Doc: "Frames delivers frames from a [FrameSource] for each data-parallel\nitem, in batches of NData images for [Image] RunImages methods,\ne.g., in [MotionDoG]. Frames are delivered at the given Rate,\nby skipping or repeating source frames according to their native\n[FrameSource.FrameRate], and sources can be looped.", Fields: []types.Field{{Name: "Sources", Doc: "Sources are the frame sources for each data-parallel item."}, {Name: "Rate", Doc: "Rate is the rate in frames per second at which frames are delivered.\nSource frames are skipped or repeated to match their native frame rate.\nIf 0, or the source frame rate is not known, each source frame is\ndelivered in turn."}, {Name: "Loop", Doc: "Loop restarts each source from the first frame after its last frame,\nand the item is included in Restarted.\nOtherwise, the last frame is repeated until all sources are Done."}, {Name: "Step", Doc: "Step is the number of frames delivered since the last Init."}, {Name: "Index", Doc: "Index are the source frame indexes of the current frames."}, {Name: "Restarted", Doc: "Restarted records the items that started over from the first\nframe of their source on the current frame, due to Loop\n(and all items for the first frame), for which any temporal\nintegration state should be reset (e.g., ResetItem in [MotionDoG])."}, {Name: "Done", Doc: "Done is set when all of the sources have reached their last frame\nwithout Loop."}, {Name: "start", Doc: "start are the Step values at which each item was last restarted."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cGrey", IDName: "v1c-grey", Doc: "V1cGrey does greyscale V1 complex (V1c) filtering, starting with\nsimple cells (V1s) and adding length sum and end stopping.\nKWTA inhibition operates on the V1s step.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters"}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Output", Doc: "Output has the resulting V1c filter outputs, pointing to Values4D in V1.\nInner Y, X dimensions are 5 x 4, where the 4 are the gabor angles\n(0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,\nand 2 polarities of V1simple."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.ImageRunner", IDName: "image-runner", Doc: "ImageRunner is implemented by the standard pipelines that\nprocess image inputs, e.g., [MotionDoG].", Methods: []types.Method{{Name: "RunImages", Args: []string{"im", "imgs"}}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cParams", IDName: "v1c-params", Doc: "V1cParams has the parameters for a given size of V1c.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size in setting params."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "Output", Doc: "Output contains this 4D filter output, in correct shape."}, {Name: "OutIdx", Doc: "Values4D index of output."}, {Name: "gaborIdx"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.ItemResetter", IDName: "item-resetter", Doc: "ItemResetter is implemented by the standard pipelines with temporal\nintegration state that can be reset for each data-parallel item,\ne.g., [MotionDoG].", Methods: []types.Method{{Name: "ResetItem", Args: []string{"ni"}}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGColorParams", IDName: "do-g-color-params", Doc: "DoGColorParams has the parameters for a given size of DoG color.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size."}, {Name: "DoG", Doc: "DoG color filter parameters. Generally have larger fields,\nand no spatial tuning (i.e., OnSigma == OffSigma), consistent\nwith blob cells."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size in setting params."}, {Name: "Geom", Doc: "geometry of DoG color contrast outputs."}, {Name: "Output", Doc: "Output contains this 4D filter output, in correct shape."}, {Name: "OutIdx", Doc: "Values4D indexes of output."}, {Name: "dogIdx"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DirFrames", IDName: "dir-frames", Doc: "DirFrames is a [FrameSource] for a directory of numbered image files,\nin the order of the last number in their file names (e.g., frame_9.png\nbefore frame_10.png), which are opened as needed.", Fields: []types.Field{{Name: "Files", Doc: "Files are the image file names, in frame order."}, {Name: "FPS", Doc: "FPS is the frame rate, in frames per second, if known."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cMulti", IDName: "v1c-multi", Doc: "V1cMulti does color V1 complex (V1c) filtering and DoG color filtering\nacross multiple different resolutions and filter sizes.\nV1c starts with simple cells (V1s) and adds length sum and end stopping.\nKWTA inhibition operates on the V1s step. DoG does Red-Green and Blue-Yellow\ncolor contrasts, capturing the chromatic response properties of color blob cells.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "SplitColor", Doc: "SplitColor records separate rows in V1c simple summary for each color.\nOtherwise records the max across all colors."}, {Name: "ColorGain", Doc: "ColorGain is an extra gain for color channels,\nwhich are lower contrast in general."}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "V1cParams", Doc: "V1cParams has the configured geometries for different V1c sizes."}, {Name: "DoGParams", Doc: "DoGParams has the configured geometries for different DoG color\nsizes."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Image", Doc: "Image manages images."}, {Name: "fadeOpIdx"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.GIFFrames", IDName: "gif-frames", Doc: "GIFFrames is a [FrameSource] for an animated GIF file,\nwhich is fully decoded into full-size frames when opened.", Fields: []types.Field{{Name: "Frames", Doc: "Frames are the full frame images, composited according\nto the GIF frame disposal methods."}, {Name: "FPS", Doc: "FPS is the frame rate, in frames per second, from the average\nframe delay, or 0 if no delays are specified."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Image", IDName: "image", Doc: "Image manages conversion of bitmap images into tensor formats for\nsubsequent processing by filters.", Directives: []types.Directive{{Tool: "go", Directive: "generate", Args: []string{"core", "generate", "-add-types"}}}, Fields: []types.Field{{Name: "File", Doc: "File is the name of image file to operate on"}, {Name: "Size", Doc: "Size is the target image size to use. Images will be rescaled to this size."}, {Name: "Fit", Doc: "Fit is how images of a different size are fit into the target Size."}, {Name: "ItemFit", Doc: "ItemFit optionally specifies a different Fit for each data-parallel\nimage item, overriding Fit for the items it has."}, {Name: "Valid", Doc: "Valid are the valid regions of each of the current Images,\nin image coordinates with Y = 0 at the top, excluding any padding\nfrom a Letterbox Fit. See [Image.SetMask]."}, {Name: "Images", Doc: "Images are the current input image(s), as Go [image.Image]."}, {Name: "Tsr", Doc: "Tsr are the current input image(s) as an RGB tensor.\nThis points into the V1Vision.Images input image."}, {Name: "Upload", Doc: "Upload uses [v1vision.V1Vision.UploadImages] to convert the images\ninto the tensor on the GPU, which is much faster, but the Tsr CPU\ncopy of the images is not updated when running on the GPU."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.FitModes", IDName: "fit-modes", Doc: "FitModes are the ways of fitting an image of a different size\ninto the target [Image.Size]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Features", IDName: "features", Doc: "Features are the types of features in the rows and columns\nof a pipeline output [Layout]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Polarities", IDName: "polarities", Doc: "Polarities are the polarities of features in a [Layout]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Colors", IDName: "colors", Doc: "Colors are the color channels of features in a [Layout]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Label", IDName: "label", Doc: "Label describes one row or column of a [Layout].", Fields: []types.Field{{Name: "Name", Doc: "Name is the unique name of this row or column within the Layout,\ne.g., \"LenSum\", \"Simple_RedGreen_On\", or \"45\" for an angle."}, {Name: "Feature", Doc: "Feature is the type of feature."}, {Name: "Polarity", Doc: "Polarity is the polarity of the feature, if any."}, {Name: "Color", Doc: "Color is the color channel of the feature."}, {Name: "Angle", Doc: "Angle is the orientation angle in degrees, for Orientation\n(0 = horizontal, increasing counter-clockwise)."}, {Name: "Index", Doc: "Index distinguishes multiple instances of the same feature:\nthe EndStop direction, Disparity shift, or Motion direction."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Layout", IDName: "layout", Doc: "Layout describes the layout of a pipeline output with 4D\n[Y][X][Row][Col] data per data-parallel item, naming each of\nthe inner Rows and Cols (e.g., feature type, polarity, color,\norientation angle), so that outputs can be accessed by name instead\nof hard-coded offsets. Layouts are valid after Config.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the output, e.g., V1c or DoG."}, {Name: "Scale", Doc: "Scale is the name of the scale, for multi-scale pipelines\n(e.g., L16 in [V1cMulti]), and empty otherwise."}, {Name: "Y", Doc: "Y, X are the outer spatial dimensions."}, {Name: "X", Doc: "Y, X are the outer spatial dimensions."}, {Name: "Rows", Doc: "Rows are the labels of the inner rows."}, {Name: "Cols", Doc: "Cols are the labels of the inner columns."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MotionPath", IDName: "motion-path", Doc: "MotionPath has the motion processing parameters and outputs that are\nshared by the motion pipelines ([MotionDoG], [MotionColor], [MotionGabor]),\nwhich differ only in the filtered input values that motion is computed on.", Fields: []types.Field{{Name: "Motion", Doc: "Motion filter parameters."}, {Name: "FullField", Doc: "FullField has the integrated FullField output: [NData, 2, 2].\nUse [motion.Directions] for 1D indexes (is 2x2 for [L,R][D,U]).\nIf Motion.OpticFlow, it is [NData, 4, 2] with 2 additional rows for\n[Expand,Contract][Clockwise,CounterClockwise]."}, {Name: "GetStar", Doc: "GetStar retrieves the star values. Otherwise, just the full-field."}, {Name: "Star", Doc: "Star has the star values, if GetStar is true,\npointing to Values4D in V1.\n[NData, Y, X, Polarity, 4 * FilterN], where Polarity is input polarity,\nand 4 is for Left, Right, Down, Up, for each input filter."}, {Name: "GetFlow", Doc: "GetFlow computes the local Flow field, pooled over\n[motion.Params.FlowPool] regions of the Star values."}, {Name: "FlowGeom", Doc: "FlowGeom is the geometry for pooling the Star values into Flow."}, {Name: "Flow", Doc: "Flow has the local flow field, if GetFlow is true:\n[NData, Y, X, 2] where the last dimension is dx, dy, with\npositive values for Right and Up motion respectively."}, {Name: "GetGrid", Doc: "GetGrid computes the regional Grid of full-field motion values,\nover [motion.Params.GridY] x [motion.Params.GridX] regions."}, {Name: "Grid", Doc: "Grid has the integrated regional full-field motion values,\nif GetGrid is true: [NData, GridY, GridX, 2, 2] where the\ninner 2x2 is [L,R][D,U] as in FullField."}, {Name: "starIndex", Doc: "starIndex is the Values4D index of the star output."}, {Name: "flowIndex", Doc: "flowIndex is the Values index of the flow output."}, {Name: "gridIndex", Doc: "gridIndex is the Values4D index of the grid output."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MotionColor", IDName: "motion-color", Doc: "MotionColor computes starburst-amacrine style motion processing and\nresulting summary full-field motion values, on color-opponent\ndifference-of-gaussian (DoG) filtering of Red - Green and\nBlue - Yellow contrasts (as in [DoGColor]), so that motion can be\ncomputed for isoluminant stimuli.\nCall Defaults and then set any custom params, then call Config.\nResults are in FullField and other [MotionPath] outputs after Run().", Embeds: []types.Field{{Name: "MotionPath", Doc: "MotionPath has the motion parameters and outputs.\nStar has [NData, Y, X, Polarity, 8], where Polarity is DoG polarity,\nand 8 is Left, Right, Down, Up for Red vs. Green and then\nBlue vs. Yellow. See [MotionColor.Layout]."}}, Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "DoG", Doc: "LGN DoG filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MotionDoG", IDName: "motion-do-g", Doc: "MotionDoG computes starburst-amacrine style motion processing and\nresulting summary full-field motion values, on greyscale\ndifference-of-gaussian (DoG) filtering.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Embeds: []types.Field{{Name: "MotionPath", Doc: "MotionPath has the motion parameters and outputs.\nStar has [NData, Y, X, Polarity, 4], where Polarity is DoG polarity,\nand 4 is for Left, Right, Down, Up. See [MotionDoG.Layout]."}}, Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "DoG", Doc: "LGN DoG filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MotionGabor", IDName: "motion-gabor", Doc: "MotionGabor computes starburst-amacrine style motion processing and\nresulting summary full-field motion values, on greyscale oriented\nV1 simple-cell gabor filtering, providing orientation-specific\nmotion energy.\nCall Defaults and then set any custom params, then call Config.\nResults are in FullField and other [MotionPath] outputs after Run().", Embeds: []types.Field{{Name: "MotionPath", Doc: "MotionPath has the motion parameters and outputs.\nStar has [NData, Y, X, Polarity, 4 * NAngles], where Polarity is\ngabor polarity, and 4 is Left, Right, Down, Up for each angle.\nSee [MotionGabor.Layout]."}}, Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cScale", IDName: "v1c-scale", Doc: "V1cScale configures one size of V1c filtering in a [MultiConfig].\nSee [V1cParams.Config].", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size, e.g., L16."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size."}, {Name: "Border", Doc: "Border is the border around the zoomed image, which should be\nconsistent across all sizes, so that the padded image size is the same."}, {Name: "Size", Doc: "Size is the V1s gabor filter size."}, {Name: "Spacing", Doc: "Spacing is the V1s gabor filter spacing."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGScale", IDName: "do-g-scale", Doc: "DoGScale configures one size of DoG color filtering in a [MultiConfig].\nSee [DoGColorParams.Config].", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size, e.g., L16."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size."}, {Name: "Border", Doc: "Border is the border around the zoomed image, which should be\nconsistent across all sizes, so that the padded image size is the same."}, {Name: "Size", Doc: "Size is the DoG filter size, which is also its spacing."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MultiConfig", IDName: "multi-config", Doc: "MultiConfig is a declarative configuration of a [V1cMulti] pipeline,\nwhich can be saved to and loaded from TOML or JSON files, so that\nsettings can be varied without recompiling.\nAny parameters not specified in a file retain their current values,\nexcept that the V1c and DoG lists of scales replace the current\nones if specified.", Fields: []types.Field{{Name: "ImageSize", Doc: "ImageSize is the size of the image content."}, {Name: "SplitColor", Doc: "SplitColor records separate rows in V1c simple summary for each color."}, {Name: "Grey", Doc: "Grey only does V1c filtering on the greyscale channel."}, {Name: "ColorGain", Doc: "ColorGain is an extra gain for color channels."}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "DoGKWTA", Doc: "DoGKWTA has the kwta inhibition parameters for DoG Color blobs."}, {Name: "OutKWTA", Doc: "OutKWTA has the kwta inhibition parameters applied to the\nassembled V1c output of each size."}, {Name: "Gabor", Doc: "Gabor has the V1s gabor filter parameters shared by all V1c sizes,\nexcept for Size, Spacing and Wavelength, which are set per size."}, {Name: "DoG", Doc: "DoG has the DoG color filter parameters shared by all DoG sizes,\nexcept for Size and Spacing, which are set per size."}, {Name: "V1c", Doc: "V1c are the V1c sizes."}, {Name: "DoGColor", Doc: "DoGColor are the DoG color sizes."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.MultiPreset", IDName: "multi-preset", Doc: "MultiPreset is a named [V1cMulti] configuration of the image size\nand the V1c and DoG color filter sizes, selectable by name with\n[V1cMulti.SetPreset].", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the preset."}, {Name: "Doc", Doc: "Doc describes the preset, including its output shapes,\nas [Y, X, Rows, Cols] per image."}, {Name: "Config", Doc: "Config adds the filter sizes to the V1cMulti, and sets\nthe image size and any other parameters."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Stereo", IDName: "stereo", Doc: "Stereo computes binocular disparity energy-model responses on\ngreyscale left and right eye images, using gabor quadrature pair\nfilters, over a range of position and / or phase disparities.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "Gabor", Doc: "Gabor filter parameters for the quadrature pairs.\nThe Phase is not used: even and odd phase filters are\nalways used."}, {Name: "NDisparities", Doc: "NDisparities is the number of disparities to compute,\ncentered on zero disparity. An odd number is recommended,\nso that zero disparity is included. See [Stereo.Shift]."}, {Name: "DispStep", Doc: "DispStep is the position disparity step, in pixels, between each\ndisparity: the right eye image is shifted horizontally by this\namount for each step away from the center."}, {Name: "PhaseStep", Doc: "PhaseStep is the phase disparity step, in degrees, between each\ndisparity: the right eye filter phase is shifted by this amount\nfor each step away from the center."}, {Name: "Gain", Doc: "Gain is a multiplier on the binocular energy output."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting binocular energy outputs, pointing to\nValues4D in V1: [NData, Y, X, NDisparities, NAngles].\nSee [Stereo.Layout] for the names."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.TemporalDoG", IDName: "temporal-do-g", Doc: "TemporalDoG computes sustained (parvocellular-like) and transient\n(magnocellular-like) channels from successive video frames,\non greyscale difference-of-gaussian (DoG) filtering.\nThe filter state persists across Run calls, and can be reset\nfor individual data-parallel items using ResetItem.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run().", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "DoG", Doc: "LGN DoG filter parameters."}, {Name: "Temporal", Doc: "Temporal filter parameters."}, {Name: "Geom", Doc: "Geom is geometry of input, output."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system."}, {Name: "Output", Doc: "Output has the resulting temporal filter outputs, pointing to\nValues4D in V1: [NData, Y, X, 4, 1], where the 4 rows are:\nsustained On, Off, and transient On, Off.\nSee [TemporalDoG.Layout] for the names."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cColor", IDName: "v1c-color", Doc: "V1cColor does color V1 complex (V1c) filtering, starting with\nsimple cells (V1s) and adding length sum and end stopping.\nKWTA inhibition operates on the V1s step.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "Mask", Doc: "Mask excludes the invalid regions of the input images (e.g., from\na Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]\nregions, so that padding does not generate spurious edge responses."}, {Name: "SplitColor", Doc: "SplitColor records separate rows in V1c simple summary for each color.\nOtherwise records the max across all colors."}, {Name: "ColorGain", Doc: "ColorGain is an extra gain for color channels,\nwhich are lower contrast in general."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters"}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Output", Doc: "Output has the resulting V1c filter outputs, pointing to Values4D in V1.\nInner Y, X dimensions are 5 x 4, where the 4 are the gabor angles\n(0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,\nand 2 polarities of V1simple, with 2 polarities for each of 3 colors\nif SplitColor (9 rows). See [V1cColor.Layout] for the names."}, {Name: "maskIndex", Doc: "maskIndex is the Images index of the valid-region mask, if Mask."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cGrey", IDName: "v1c-grey", Doc: "V1cGrey does greyscale V1 complex (V1c) filtering, starting with\nsimple cells (V1s) and adding length sum and end stopping.\nKWTA inhibition operates on the V1s step.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "Mask", Doc: "Mask excludes the invalid regions of the input images (e.g., from\na Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]\nregions, so that padding does not generate spurious edge responses."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters"}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code.\nIt is part of the V1sKWTA inhibition, and is only used with it."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "V1sTopK", Doc: "V1sTopK has exact top-k selection parameters for V1s, as an\nalternative to V1sKWTA, which must be off if either Pool or\nLayer is On. See [V1cGrey.Validate]."}, {Name: "V1sSpikes", Doc: "V1sSpikes has the optional spiking output parameters for V1s,\ngenerating Poisson spikes from the KWTA activations.\nRequires V1sKWTA to be On, and thus cannot be used with V1sTopK."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Output", Doc: "Output has the resulting V1c filter outputs, pointing to Values4D in V1.\nInner Y, X dimensions are 5 x 4, where the 4 are the gabor angles\n(0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,\nand 2 polarities of V1simple. See [V1cGrey.Layout] for the names."}, {Name: "Spikes", Doc: "Spikes has the V1s spike counts if V1sSpikes.On,\ncopied from Values in V1: [NData, Y, X, Polarity, Angle]."}, {Name: "SpikeTrains", Doc: "SpikeTrains has the V1s per-cycle spike trains if V1sSpikes.On\nand V1sSpikes.Trains, computed on the CPU from the spike counts inputs:\n[NData, Y, X, Cycles, Polarity * Angles + Angle]."}, {Name: "spikes", Doc: "spikes is the Spikes in the correct shape."}, {Name: "spikeTrains", Doc: "spikeTrains is the SpikeTrains in the correct shape."}, {Name: "outIndex", Doc: "outIndex is the Values4D index of the output."}, {Name: "spikesIndex", Doc: "spikesIndex is the Values index of the spike counts."}, {Name: "maskIndex", Doc: "maskIndex is the Images index of the valid-region mask, if Mask."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cParams", IDName: "v1c-params", Doc: "V1cParams has the parameters for a given size of V1c.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size."}, {Name: "V1sGabor", Doc: "V1 simple gabor filter parameters."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size in setting params."}, {Name: "V1sGeom", Doc: "geometry of input, output for V1 simple-cell processing."}, {Name: "V1cGeom", Doc: "geometry of input, output for V1 complex-cell processing from V1s inputs."}, {Name: "Output", Doc: "Output contains this 4D filter output, in correct shape.\nSee [V1cParams.Layout] for the names of the rows and columns."}, {Name: "OutIdx", Doc: "Values4D index of output."}, {Name: "gaborIdx"}, {Name: "grey", Doc: "grey is [V1cMulti.Grey] as of the last Config."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.DoGColorParams", IDName: "do-g-color-params", Doc: "DoGColorParams has the parameters for a given size of DoG color.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of this size."}, {Name: "DoG", Doc: "DoG color filter parameters. Generally have larger fields,\nand no spatial tuning (i.e., OnSigma == OffSigma), consistent\nwith blob cells."}, {Name: "Zoom", Doc: "Zoom is the zoom factor: divides effective image size in setting params."}, {Name: "Geom", Doc: "geometry of DoG color contrast outputs."}, {Name: "Output", Doc: "Output contains this 4D filter output, in correct shape.\nSee [DoGColorParams.Layout] for the names of the rows and columns."}, {Name: "OutIdx", Doc: "Values4D indexes of output."}, {Name: "dogIdx"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.V1cMulti", IDName: "v1c-multi", Doc: "V1cMulti does color V1 complex (V1c) filtering and DoG color filtering\nacross multiple different resolutions and filter sizes.\nV1c starts with simple cells (V1s) and adds length sum and end stopping.\nKWTA inhibition operates on the V1s step. DoG does Red-Green and Blue-Yellow\ncolor contrasts, capturing the chromatic response properties of color blob cells.\nCall Defaults and then set any custom params, then call Config.\nResults are in Output tensor after Run(), which has a 4D shape.", Fields: []types.Field{{Name: "GPU", Doc: "GPU means use the GPU by default (does GPU initialization) in Config.\nTo change what is actually used at the moment of running,\nset [v1vision.UseGPU]."}, {Name: "SplitColor", Doc: "SplitColor records separate rows in V1c simple summary for each color.\nOtherwise records the max across all colors."}, {Name: "Grey", Doc: "Grey only does V1c filtering on the greyscale (luminance) channel,\nas in [V1cGrey], so SplitColor does not apply. Any DoGParams\nstill do DoG color filtering, so greyscale configurations\ngenerally have none."}, {Name: "ColorGain", Doc: "ColorGain is an extra gain for color channels,\nwhich are lower contrast in general."}, {Name: "V1sNeighInhib", Doc: "V1sNeighInhib specifies neighborhood inhibition for V1s.\nEach unit gets inhibition from same feature in nearest orthogonal\nneighbors. Reduces redundancy of feature code."}, {Name: "V1sKWTA", Doc: "V1sKWTA has the kwta inhibition parameters for V1s."}, {Name: "DoGKWTA", Doc: "DoGKWTA has the kwta inhibition parameters for DoG Color blobs."}, {Name: "OutKWTA", Doc: "OutKWTA has the kwta inhibition parameters applied to the\nassembled V1c output of each size, where each pool has all of\nthe feature rows (length-sum, end-stop, and V1s polarity and color)\nat each location. Off by default."}, {Name: "V1cParams", Doc: "V1cParams has the configured geometries for different V1c sizes."}, {Name: "DoGParams", Doc: "DoGParams has the configured geometries for different DoG color\nsizes."}, {Name: "V1", Doc: "V1 is the V1Vision filter processing system"}, {Name: "Image", Doc: "Image manages images."}, {Name: "outKWTAIdx", Doc: "outKWTAIdx is the KWTAs index of OutKWTA."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.Y4MFrames", IDName: "y4-m-frames", Doc: "Y4MFrames is a [FrameSource] for an uncompressed YUV4MPEG2 (.y4m)\nvideo file, with 8 bit 4:2:0, 4:2:2, 4:4:4 or mono color.\nFrames are read from the file as needed, as [image.YCbCr]\n(or [image.Gray] for mono).", Fields: []types.Field{{Name: "Width", Doc: "Width, Height are the frame size."}, {Name: "Height", Doc: "Width, Height are the frame size."}, {Name: "FPS", Doc: "FPS is the frame rate, in frames per second."}, {Name: "Colorspace", Doc: "Colorspace is the Y4M colorspace (C parameter), e.g., 420jpeg."}, {Name: "Mono", Doc: "Mono is true for mono (greyscale) video."}, {Name: "Ratio", Doc: "Ratio is the chroma subsample ratio, if not Mono."}, {Name: "file", Doc: "file is the open video file."}, {Name: "offsets", Doc: "offsets are the file offsets of the frame data for each frame."}, {Name: "frameSize", Doc: "frameSize is the size of the data for each frame in bytes."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.RawVideoFormats", IDName: "raw-video-formats", Doc: "RawVideoFormats are the pixel formats of headerless raw video files,\nfor [RawFrames]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/v1vision/v1std.RawFrames", IDName: "raw-frames", Doc: "RawFrames is a [FrameSource] for a headerless raw video file of\nsequential uncompressed frames (e.g., from ffmpeg -f rawvideo),\nfor which the frame size, pixel format and frame rate must be given.\nFrames are read from the file as needed.", Fields: []types.Field{{Name: "Width", Doc: "Width, Height are the frame size."}, {Name: "Height", Doc: "Width, Height are the frame size."}, {Name: "Format", Doc: "Format is the pixel format."}, {Name: "FPS", Doc: "FPS is the frame rate, in frames per second, if known."}, {Name: "file", Doc: "file is the open video file."}, {Name: "nFrames", Doc: "nFrames is the number of complete frames in the file."}}})
@@ -25,6 +25,11 @@ type V1cColor struct {
 	// set [v1vision.UseGPU].
 	GPU bool
 
+	// Mask excludes the invalid regions of the input images (e.g., from
+	// a Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]
+	// regions, so that padding does not generate spurious edge responses.
+	Mask bool
+
 	// SplitColor records separate rows in V1c simple summary for each color.
 	// Otherwise records the max across all colors.
 	SplitColor bool
@@ -56,8 +61,12 @@ type V1cColor struct {
 	// Output has the resulting V1c filter outputs, pointing to Values4D in V1.
 	// Inner Y, X dimensions are 5 x 4, where the 4 are the gabor angles
 	// (0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,
-	// and 2 polarities of V1simple.
+	// and 2 polarities of V1simple, with 2 polarities for each of 3 colors
+	// if SplitColor (9 rows). See [V1cColor.Layout] for the names.
 	Output *tensor.Float32 `display:"no-inline"`
+
+	// maskIndex is the Images index of the valid-region mask, if Mask.
+	maskIndex int
 }
 
 func (vi *V1cColor) Defaults() {
@@ -91,6 +100,9 @@ func (vi *V1cColor) Config(ndata int, imageSize image.Point) {
 	kwtaIdx := 0
 	img := vi.V1.NewImage(vi.V1sGeom.In.V())
 	wrap := vi.V1.NewImage(vi.V1sGeom.In.V())
+	if vi.Mask {
+		vi.maskIndex = vi.V1.NewMaskImage(vi.V1sGeom.In.V())
+	}
 	lms := vi.V1.NewImage(vi.V1sGeom.In.V())
 
 	avgIdx := vi.V1.NewEdgeAvg(img, 3, int(vi.V1sGeom.Border.X), &vi.V1sGeom)
@@ -106,7 +118,11 @@ func (vi *V1cColor) Config(ndata int, imageSize image.Point) {
 	lmsMap := [3]int{1, int(v1vision.RedGreen), int(v1vision.BlueYellow)}
 	var v1sIdxs [3]int
 	for irgb := range 3 {
+		if vi.Mask {
+			vi.V1.SetMaskImage(vi.maskIndex)
+		}
 		out := vi.V1.NewConvolveImage(lms, lmsMap[irgb], ftyp, nang, vi.V1sGabor.Gain, &vi.V1sGeom)
+		vi.V1.ClearMaskImage()
 		v1out := out
 		if vi.V1sKWTA.On.IsTrue() {
 			ninh := 0
@@ -129,24 +145,21 @@ func (vi *V1cColor) Config(ndata int, imageSize image.Point) {
 	esout := vi.V1.NewEndStop4(pmpout, lsout, nang, &vi.V1cGeom)
 
 	// To4D
-	out4Rows := 5
-	if vi.SplitColor {
-		out4Rows = 9
-	}
-	out4 := vi.V1.NewValues4D(int(vi.V1cGeom.Out.Y), int(vi.V1cGeom.Out.X), out4Rows, nang)
-	vi.V1.NewTo4D(lsout, out4, 1, nang, 0, &vi.V1cGeom)
-	vi.V1.NewTo4D(esout, out4, 2, nang, 1, &vi.V1cGeom)
+	ly := vi.Layout()
+	out4 := vi.V1.NewValues4D(int(vi.V1cGeom.Out.Y), int(vi.V1cGeom.Out.X), len(ly.Rows), nang)
+	vi.V1.NewTo4D(lsout, out4, 1, nang, ly.RowIndex("LenSum"), &vi.V1cGeom)
+	vi.V1.NewTo4D(esout, out4, 2, nang, ly.RowIndex("EndStop_Plus"), &vi.V1cGeom)
 	if vi.SplitColor {
 		poutg := vi.V1.NewMaxPool(v1sIdxs[0], 2, nang, &vi.V1cGeom)
 		poutrg := vi.V1.NewMaxPool(v1sIdxs[1], 2, nang, &vi.V1cGeom)
 		poutby := vi.V1.NewMaxPool(v1sIdxs[2], 2, nang, &vi.V1cGeom)
 
-		vi.V1.NewTo4D(poutg, out4, 2, nang, 3, &vi.V1cGeom)
-		vi.V1.NewTo4D(poutrg, out4, 2, nang, 5, &vi.V1cGeom)
-		vi.V1.NewTo4D(poutby, out4, 2, nang, 7, &vi.V1cGeom)
+		vi.V1.NewTo4D(poutg, out4, 2, nang, ly.RowIndex("Simple_Grey_On"), &vi.V1cGeom)
+		vi.V1.NewTo4D(poutrg, out4, 2, nang, ly.RowIndex("Simple_RedGreen_On"), &vi.V1cGeom)
+		vi.V1.NewTo4D(poutby, out4, 2, nang, ly.RowIndex("Simple_BlueYellow_On"), &vi.V1cGeom)
 	} else {
 		pout := vi.V1.NewMaxPool(mcout, 2, nang, &vi.V1cGeom)
-		vi.V1.NewTo4D(pout, out4, 2, nang, 3, &vi.V1cGeom)
+		vi.V1.NewTo4D(pout, out4, 2, nang, ly.RowIndex("Simple_On"), &vi.V1cGeom)
 	}
 
 	vi.V1.SetAsCurrent()
@@ -161,6 +174,17 @@ func (vi *V1cColor) RunImages(im *Image, imgs ...image.Image) {
 	vi.V1.SetAsCurrent()
 	v1vision.UseGPU = vi.GPU
 	im.SetImagesRGB(&vi.V1, int(vi.V1sGeom.Border.X), imgs...)
+	if vi.Mask {
+		im.SetMask(&vi.V1, vi.maskIndex, int(vi.V1sGeom.Border.X))
+	}
 	vi.V1.Run(v1vision.Values4DVar)
 	vi.Output = vi.V1.Values4D.SubSpace(0).(*tensor.Float32)
 }
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are LenSum, EndStop_Plus, EndStop_Minus, and Simple_On,
+// Simple_Off, or if SplitColor, Simple_<Color>_On, _Off for each of
+// Grey, RedGreen, BlueYellow. The columns are the gabor angles.
+func (vi *V1cColor) Layout() *Layout {
+	return v1cLayout("", true, vi.SplitColor, vi.V1sGabor.NAngles, &vi.V1cGeom)
+}
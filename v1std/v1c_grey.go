@@ -5,8 +5,10 @@
 package v1std
 
 import (
+	"fmt"
 	"image"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/math32"
 	"cogentcore.org/lab/tensor"
 	"github.com/emer/v1vision/gabor"
@@ -25,17 +27,33 @@ type V1cGrey struct {
 	// set [v1vision.UseGPU].
 	GPU bool
 
+	// Mask excludes the invalid regions of the input images (e.g., from
+	// a Letterbox [Image.Fit]) from the filtering, using the [Image.Valid]
+	// regions, so that padding does not generate spurious edge responses.
+	Mask bool
+
 	// V1 simple gabor filter parameters
 	V1sGabor gabor.Filter
 
 	// V1sNeighInhib specifies neighborhood inhibition for V1s.
 	// Each unit gets inhibition from same feature in nearest orthogonal
 	// neighbors. Reduces redundancy of feature code.
+	// It is part of the V1sKWTA inhibition, and is only used with it.
 	V1sNeighInhib kwta.NeighInhib
 
 	// V1sKWTA has the kwta inhibition parameters for V1s.
 	V1sKWTA kwta.KWTA
 
+	// V1sTopK has exact top-k selection parameters for V1s, as an
+	// alternative to V1sKWTA, which must be off if either Pool or
+	// Layer is On. See [V1cGrey.Validate].
+	V1sTopK kwta.TopK
+
+	// V1sSpikes has the optional spiking output parameters for V1s,
+	// generating Poisson spikes from the KWTA activations.
+	// Requires V1sKWTA to be On, and thus cannot be used with V1sTopK.
+	V1sSpikes kwta.Spikes
+
 	// geometry of input, output for V1 simple-cell processing.
 	V1sGeom v1vision.Geom `edit:"-"`
 
@@ -48,8 +66,32 @@ type V1cGrey struct {
 	// Output has the resulting V1c filter outputs, pointing to Values4D in V1.
 	// Inner Y, X dimensions are 5 x 4, where the 4 are the gabor angles
 	// (0, 45, 90, 135) and the 5 are: 1 length-sum, 2 directions of end-stop,
-	// and 2 polarities of V1simple.
+	// and 2 polarities of V1simple. See [V1cGrey.Layout] for the names.
 	Output *tensor.Float32 `display:"no-inline"`
+
+	// Spikes has the V1s spike counts if V1sSpikes.On,
+	// copied from Values in V1: [NData, Y, X, Polarity, Angle].
+	Spikes *tensor.Float32 `display:"no-inline"`
+
+	// SpikeTrains has the V1s per-cycle spike trains if V1sSpikes.On
+	// and V1sSpikes.Trains, computed on the CPU from the spike counts inputs:
+	// [NData, Y, X, Cycles, Polarity * Angles + Angle].
+	SpikeTrains *tensor.Float32 `display:"no-inline"`
+
+	// spikes is the Spikes in the correct shape.
+	spikes tensor.Float32
+
+	// spikeTrains is the SpikeTrains in the correct shape.
+	spikeTrains tensor.Float32
+
+	// outIndex is the Values4D index of the output.
+	outIndex int
+
+	// spikesIndex is the Values index of the spike counts.
+	spikesIndex int
+
+	// maskIndex is the Images index of the valid-region mask, if Mask.
+	maskIndex int
 }
 
 func (vi *V1cGrey) Defaults() {
@@ -57,6 +99,8 @@ func (vi *V1cGrey) Defaults() {
 	vi.V1sGabor.Defaults()
 	vi.V1sNeighInhib.Defaults()
 	vi.V1sKWTA.Defaults()
+	vi.V1sTopK.Defaults()
+	vi.V1sSpikes.Defaults()
 	vi.SetSize(12, 4)
 }
 
@@ -74,6 +118,7 @@ func (vi *V1cGrey) SetSize(sz, spc int) {
 // The resulting Geom.Border field can be passed to [Image] methods.
 // ndata = number of data-parallel inputs to process in parallel.
 func (vi *V1cGrey) Config(ndata int, imageSize image.Point) {
+	errors.Log(vi.Validate())
 	vi.V1sGeom.SetImageSize(imageSize)
 
 	vi.V1.Init(ndata)
@@ -81,21 +126,34 @@ func (vi *V1cGrey) Config(ndata int, imageSize image.Point) {
 	kwtaIdx := 0
 	img := vi.V1.NewImage(vi.V1sGeom.In.V())
 	wrap := vi.V1.NewImage(vi.V1sGeom.In.V())
+	if vi.Mask {
+		vi.maskIndex = vi.V1.NewMaskImage(vi.V1sGeom.In.V())
+	}
 
 	vi.V1.NewWrapImage(img, 0, wrap, int(vi.V1sGeom.Border.X), &vi.V1sGeom)
 
 	nang := vi.V1sGabor.NAngles
 
 	// V1s simple
+	if vi.Mask {
+		vi.V1.SetMaskImage(vi.maskIndex)
+	}
 	_, out := vi.V1.NewGabor(wrap, 0, &vi.V1sGabor, &vi.V1sGeom)
+	vi.V1.ClearMaskImage()
 	v1out := out
-	if vi.V1sKWTA.On.IsTrue() {
+	if vi.V1sTopK.On() {
+		v1out = vi.V1.NewTopK(out, nang, &vi.V1sTopK, &vi.V1sGeom)
+	} else if vi.V1sKWTA.On.IsTrue() {
 		ninh := 0
 		if vi.V1sNeighInhib.On {
 			ninh = vi.V1.NewNeighInhib4(out, nang, vi.V1sNeighInhib.Gi, &vi.V1sGeom)
 		}
 		inh := vi.V1.NewInhibs(int(vi.V1sGeom.Out.Y), int(vi.V1sGeom.Out.X))
 		v1out = vi.V1.NewKWTA(out, ninh, nang, kwtaIdx, inh, &vi.V1sGeom)
+		if vi.V1sSpikes.On {
+			vi.spikesIndex = vi.V1.NewPoissonSpikes(v1out, nang, &vi.V1sSpikes, &vi.V1sGeom)
+			vi.spikes.SetShapeSizes(ndata, int(vi.V1sGeom.Out.Y), int(vi.V1sGeom.Out.X), 2, nang)
+		}
 	}
 
 	// V1c complex
@@ -107,10 +165,12 @@ func (vi *V1cGrey) Config(ndata int, imageSize image.Point) {
 	esout := vi.V1.NewEndStop4(pmpout, lsout, nang, &vi.V1cGeom)
 
 	// To4D
-	out4 := vi.V1.NewValues4D(int(vi.V1cGeom.Out.Y), int(vi.V1cGeom.Out.X), 5, nang)
-	vi.V1.NewTo4D(lsout, out4, 1, nang, 0, &vi.V1cGeom)
-	vi.V1.NewTo4D(esout, out4, 2, nang, 1, &vi.V1cGeom)
-	vi.V1.NewTo4D(pout, out4, 2, nang, 3, &vi.V1cGeom)
+	ly := vi.Layout()
+	out4 := vi.V1.NewValues4D(int(vi.V1cGeom.Out.Y), int(vi.V1cGeom.Out.X), len(ly.Rows), nang)
+	vi.outIndex = out4
+	vi.V1.NewTo4D(lsout, out4, 1, nang, ly.RowIndex("LenSum"), &vi.V1cGeom)
+	vi.V1.NewTo4D(esout, out4, 2, nang, ly.RowIndex("EndStop_Plus"), &vi.V1cGeom)
+	vi.V1.NewTo4D(pout, out4, 2, nang, ly.RowIndex("Simple_On"), &vi.V1cGeom)
 
 	vi.V1.SetAsCurrent()
 	if vi.GPU {
@@ -118,12 +178,55 @@ func (vi *V1cGrey) Config(ndata int, imageSize image.Point) {
 	}
 }
 
+// Validate returns an error if the V1s inhibition parameters are
+// inconsistent: V1sTopK is an alternative to V1sKWTA (including
+// V1sNeighInhib), so they cannot both be on, and V1sSpikes requires
+// the V1sKWTA rate-code activations. Config logs this error, and uses
+// V1sTopK instead of V1sKWTA and V1sSpikes if it is on.
+func (vi *V1cGrey) Validate() error {
+	var errs []error
+	if vi.V1sTopK.On() && vi.V1sKWTA.On.IsTrue() {
+		errs = append(errs, fmt.Errorf("V1cGrey: V1sTopK and V1sKWTA are alternatives: V1sKWTA.On must be off to use V1sTopK"))
+	}
+	if vi.V1sSpikes.On && !vi.V1sKWTA.On.IsTrue() {
+		errs = append(errs, fmt.Errorf("V1cGrey: V1sSpikes requires V1sKWTA.On"))
+	}
+	return errors.Join(errs...)
+}
+
 // RunImages runs the configured filtering pipeline.
 // on given Image(s), using given [Image] handler.
 func (vi *V1cGrey) RunImages(im *Image, imgs ...image.Image) {
 	vi.V1.SetAsCurrent()
 	v1vision.UseGPU = vi.GPU
 	im.SetImagesGrey(&vi.V1, int(vi.V1sGeom.Border.X), imgs...)
-	vi.V1.Run(v1vision.Values4DVar)
-	vi.Output = vi.V1.Values4D.SubSpace(0).(*tensor.Float32)
+	if vi.Mask {
+		im.SetMask(&vi.V1, vi.maskIndex, int(vi.V1sGeom.Border.X))
+	}
+	if !vi.spikesOn() {
+		vi.V1.Run(v1vision.Values4DVar)
+		vi.Output = vi.V1.Values4D.SubSpace(vi.outIndex).(*tensor.Float32)
+		return
+	}
+	vi.V1.Run(v1vision.Values4DVar, v1vision.ValuesVar)
+	vi.Output = vi.V1.Values4D.SubSpace(vi.outIndex).(*tensor.Float32)
+	tensor.CopyFromLargerShape(&vi.spikes, vi.V1.Values.SubSpace(vi.spikesIndex))
+	vi.Spikes = &vi.spikes
+	if !vi.V1sSpikes.Trains {
+		return
+	}
+	errors.Log(vi.V1.PoissonSpikeTrains(vi.spikesIndex, &vi.spikeTrains))
+	vi.SpikeTrains = &vi.spikeTrains
+}
+
+// Layout returns the layout of the Output, which is valid after Config.
+// The rows are LenSum, EndStop_Plus, EndStop_Minus, Simple_On, Simple_Off,
+// and the columns are the gabor angles.
+func (vi *V1cGrey) Layout() *Layout {
+	return v1cLayout("", false, false, vi.V1sGabor.NAngles, &vi.V1cGeom)
+}
+
+// spikesOn returns true if the spiking output stage is configured.
+func (vi *V1cGrey) spikesOn() bool {
+	return vi.V1sKWTA.On.IsTrue() && !vi.V1sTopK.On() && vi.V1sSpikes.On
 }
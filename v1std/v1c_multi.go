@@ -33,12 +33,16 @@ type V1cParams struct {
 	V1cGeom v1vision.Geom `edit:"-"`
 
 	// Output contains this 4D filter output, in correct shape.
+	// See [V1cParams.Layout] for the names of the rows and columns.
 	Output tensor.Float32
 
 	// Values4D index of output.
 	OutIdx int
 
 	gaborIdx int
+
+	// grey is [V1cMulti.Grey] as of the last Config.
+	grey bool
 }
 
 // Config configures geometry and filter sizes. The border is used directly, and
@@ -66,8 +70,13 @@ func (vp *V1cParams) V1Config(vi *V1cMulti, lms, kwtaIdx int) {
 	vi.V1.GaborToFilter(ftyp, &vp.V1sGabor)
 	inh := vi.V1.NewInhibs(int(vp.V1sGeom.Out.Y), int(vp.V1sGeom.Out.X))
 	lmsMap := [3]int{1, int(v1vision.RedGreen), int(v1vision.BlueYellow)}
+	nclr := 3
+	if vi.Grey {
+		nclr = 1
+	}
+	vp.grey = vi.Grey
 	var v1sIdxs [3]int
-	for irgb := range 3 {
+	for irgb := range nclr {
 		out := vi.V1.NewConvolveImage(lms, lmsMap[irgb], ftyp, nang, vp.V1sGabor.Gain, &vp.V1sGeom)
 		v1out := out
 		if vi.V1sKWTA.On.IsTrue() {
@@ -79,9 +88,12 @@ func (vp *V1cParams) V1Config(vi *V1cMulti, lms, kwtaIdx int) {
 		}
 		v1sIdxs[irgb] = v1out
 	}
-	mcout := vi.V1.NewValues(int(vp.V1sGeom.Out.Y), int(vp.V1sGeom.Out.X), nang)
-	vi.V1.NewMaxCopy(v1sIdxs[0], v1sIdxs[1], mcout, nang, &vp.V1sGeom)
-	vi.V1.NewMaxCopy(v1sIdxs[2], mcout, mcout, nang, &vp.V1sGeom)
+	mcout := v1sIdxs[0]
+	if !vi.Grey {
+		mcout = vi.V1.NewValues(int(vp.V1sGeom.Out.Y), int(vp.V1sGeom.Out.X), nang)
+		vi.V1.NewMaxCopy(v1sIdxs[0], v1sIdxs[1], mcout, nang, &vp.V1sGeom)
+		vi.V1.NewMaxCopy(v1sIdxs[2], mcout, mcout, nang, &vp.V1sGeom)
+	}
 
 	// V1c complex
 	vp.V1cGeom.SetFilter(math32.Vec2i(0, 0), math32.Vec2i(2, 2), math32.Vec2i(2, 2), vp.V1sGeom.Out.V())
@@ -91,25 +103,44 @@ func (vp *V1cParams) V1Config(vi *V1cMulti, lms, kwtaIdx int) {
 	esout := vi.V1.NewEndStop4(pmpout, lsout, nang, &vp.V1cGeom)
 
 	// To4D
-	out4Rows := vi.Out4Rows()
+	ly := vp.Layout(vi.SplitColor)
+	out4Rows := len(ly.Rows)
 	out4 := vi.V1.NewValues4D(int(vp.V1cGeom.Out.Y), int(vp.V1cGeom.Out.X), out4Rows, nang)
 	vp.OutIdx = out4
-	vp.Output.SetShapeSizes(vi.V1.NData, int(vp.V1cGeom.Out.Y), int(vp.V1cGeom.Out.X), out4Rows, nang)
+	vp.Output.SetShapeSizes(append([]int{vi.V1.NData}, ly.Shape()...)...)
 
-	vi.V1.NewTo4D(lsout, out4, 1, nang, 0, &vp.V1cGeom)
-	vi.V1.NewTo4D(esout, out4, 2, nang, 1, &vp.V1cGeom)
-	if vi.SplitColor {
+	vi.V1.NewTo4D(lsout, out4, 1, nang, ly.RowIndex("LenSum"), &vp.V1cGeom)
+	vi.V1.NewTo4D(esout, out4, 2, nang, ly.RowIndex("EndStop_Plus"), &vp.V1cGeom)
+	if vi.SplitColor && !vi.Grey {
 		poutg := vi.V1.NewMaxPool(v1sIdxs[0], 2, nang, &vp.V1cGeom)
 		poutrg := vi.V1.NewMaxPool(v1sIdxs[1], 2, nang, &vp.V1cGeom)
 		poutby := vi.V1.NewMaxPool(v1sIdxs[2], 2, nang, &vp.V1cGeom)
 
-		vi.V1.NewTo4D(poutg, out4, 2, nang, 3, &vp.V1cGeom)
-		vi.V1.NewTo4D(poutrg, out4, 2, nang, 5, &vp.V1cGeom)
-		vi.V1.NewTo4D(poutby, out4, 2, nang, 7, &vp.V1cGeom)
+		vi.V1.NewTo4D(poutg, out4, 2, nang, ly.RowIndex("Simple_Grey_On"), &vp.V1cGeom)
+		vi.V1.NewTo4D(poutrg, out4, 2, nang, ly.RowIndex("Simple_RedGreen_On"), &vp.V1cGeom)
+		vi.V1.NewTo4D(poutby, out4, 2, nang, ly.RowIndex("Simple_BlueYellow_On"), &vp.V1cGeom)
 	} else {
 		pout := vi.V1.NewMaxPool(mcout, 2, nang, &vp.V1cGeom)
-		vi.V1.NewTo4D(pout, out4, 2, nang, 3, &vp.V1cGeom)
+		vi.V1.NewTo4D(pout, out4, 2, nang, ly.RowIndex("Simple_On"), &vp.V1cGeom)
+	}
+	vp.outKWTA(vi, out4Rows, nang)
+}
+
+// Layout returns the layout of the Output for given
+// [V1cMulti.SplitColor] setting, which is valid after Config.
+// See [V1cColor.Layout], or [V1cGrey.Layout] if [V1cMulti.Grey].
+func (vp *V1cParams) Layout(splitColor bool) *Layout {
+	return v1cLayout(vp.Name, !vp.grey, splitColor, vp.V1sGabor.NAngles, &vp.V1cGeom)
+}
+
+// outKWTA adds KWTA inhibition over the assembled Values4D output,
+// if [V1cMulti.OutKWTA] is on, updating OutIdx to the result.
+func (vp *V1cParams) outKWTA(vi *V1cMulti, out4Rows, nang int) {
+	if vi.OutKWTA.On.IsFalse() {
+		return
 	}
+	inh := vi.V1.NewInhibs(int(vp.V1cGeom.Out.Y), int(vp.V1cGeom.Out.X))
+	vp.OutIdx = vi.V1.NewKWTA4D(vp.OutIdx, out4Rows, nang, vi.outKWTAIdx, inh, &vp.V1cGeom)
 }
 
 func (vp *V1cParams) UpdateFilter(vi *V1cMulti) {
@@ -141,6 +172,7 @@ type DoGColorParams struct {
 	Geom v1vision.Geom `edit:"-"`
 
 	// Output contains this 4D filter output, in correct shape.
+	// See [DoGColorParams.Layout] for the names of the rows and columns.
 	Output tensor.Float32
 
 	// Values4D indexes of output.
@@ -183,10 +215,17 @@ func (vp *DoGColorParams) V1Config(vi *V1cMulti, lmsRG, lmsBY, kwtaIdx int) {
 	}
 
 	// To4D
-	out4 := vi.V1.NewValues4D(int(vp.Geom.Out.Y), int(vp.Geom.Out.X), 2, 2)
+	ly := vp.Layout()
+	out4 := vi.V1.NewValues4D(int(vp.Geom.Out.Y), int(vp.Geom.Out.X), len(ly.Rows), len(ly.Cols))
 	vp.OutIdx = out4
-	vp.Output.SetShapeSizes(vi.V1.NData, int(vp.Geom.Out.Y), int(vp.Geom.Out.X), 2, 2)
-	vi.V1.NewTo4D(out, out4, 2, 2, 0, &vp.Geom)
+	vp.Output.SetShapeSizes(append([]int{vi.V1.NData}, ly.Shape()...)...)
+	vi.V1.NewTo4D(out, out4, 2, 2, ly.RowIndex("On"), &vp.Geom)
+}
+
+// Layout returns the layout of the Output, which is valid after Config.
+// See [DoGColor.Layout].
+func (vp *DoGColorParams) Layout() *Layout {
+	return dogColorLayout(vp.Name, &vp.Geom)
 }
 
 func (vp *DoGColorParams) UpdateFilter(vi *V1cMulti) {
@@ -218,6 +257,12 @@ type V1cMulti struct {
 	// Otherwise records the max across all colors.
 	SplitColor bool
 
+	// Grey only does V1c filtering on the greyscale (luminance) channel,
+	// as in [V1cGrey], so SplitColor does not apply. Any DoGParams
+	// still do DoG color filtering, so greyscale configurations
+	// generally have none.
+	Grey bool
+
 	// ColorGain is an extra gain for color channels,
 	// which are lower contrast in general.
 	ColorGain float32 `default:"8"`
@@ -233,6 +278,12 @@ type V1cMulti struct {
 	// DoGKWTA has the kwta inhibition parameters for DoG Color blobs.
 	DoGKWTA kwta.KWTA
 
+	// OutKWTA has the kwta inhibition parameters applied to the
+	// assembled V1c output of each size, where each pool has all of
+	// the feature rows (length-sum, end-stop, and V1s polarity and color)
+	// at each location. Off by default.
+	OutKWTA kwta.KWTA
+
 	// V1cParams has the configured geometries for different V1c sizes.
 	V1cParams []*V1cParams
 
@@ -245,6 +296,9 @@ type V1cMulti struct {
 
 	// Image manages images.
 	Image Image
+
+	// outKWTAIdx is the KWTAs index of OutKWTA.
+	outKWTAIdx int
 }
 
 func (vi *V1cMulti) Defaults() {
@@ -257,6 +311,8 @@ func (vi *V1cMulti) Defaults() {
 	vi.DoGKWTA.Defaults()
 	vi.DoGKWTA.Layer.On.SetBool(false) // non-spatial, mainly for differentiation within pools
 	vi.DoGKWTA.Pool.Gi = 1.2
+	vi.OutKWTA.Defaults()
+	vi.OutKWTA.On.SetBool(false)
 }
 
 func (vi *V1cMulti) AddV1cParams() *V1cParams {
@@ -275,7 +331,11 @@ func (vi *V1cMulti) AddDoGParams() *DoGColorParams {
 // field of view (FOV), with Low and Medium resolution V1c filters
 // and 1 level of spatial zoom (8 degrees),
 // Along with corresponding low and medium resolution color DoGs.
-// This operates on 128x128 image content.
+// This operates on 128x128 image content. Output shapes, as
+// [Y, X, Rows, Cols] (9 V1c rows with SplitColor, else 5):
+//
+//	V1c L16, M8: [8, 8, 9, 4]; M16, H8: [16, 16, 9, 4]
+//	DoG L16, L8: [8, 8, 2, 2]; M16, M8: [16, 16, 2, 2]
 func (vi *V1cMulti) StdLowMed16DegZoom1() {
 	vi.Image.Size = image.Point{128, 128}
 	// target full wrap/pad image size = 128 + 12 * 2 = 152
@@ -296,7 +356,9 @@ func (vi *V1cMulti) StdLowMed16DegZoom1() {
 
 // StdLowMed16DegNoDoG configures a standard 16 degree parafovial
 // field of view (FOV), with Low and Medium resolution V1c filters.
-// This operates on 128x128 image content.
+// This operates on 128x128 image content. Output shapes:
+//
+//	V1c L16: [8, 8, 9, 4]; M16: [16, 16, 9, 4]
 func (vi *V1cMulti) StdLowMed16DegNoDoG() {
 	vi.Image.Size = image.Point{128, 128}
 	// target full wrap/pad image size = 128 + 12 * 2 = 152
@@ -304,12 +366,93 @@ func (vi *V1cMulti) StdLowMed16DegNoDoG() {
 	vi.AddV1cParams().Config("M16", 1, 12, 12, 4) // 128 / 4 = 32
 }
 
+// StdLowMedHigh16DegZoom1 is [V1cMulti.StdLowMed16DegZoom1] with
+// the addition of High resolution V1c filters over the full 16 degree
+// FOV, as in the full-size LVis model. This operates on 128x128
+// image content. Output shapes:
+//
+//	V1c L16, M8: [8, 8, 9, 4]; M16, H8: [16, 16, 9, 4]; H16: [32, 32, 9, 4]
+//	DoG L16, L8: [8, 8, 2, 2]; M16, M8: [16, 16, 2, 2]
+func (vi *V1cMulti) StdLowMedHigh16DegZoom1() {
+	vi.Image.Size = image.Point{128, 128}
+	// target full wrap/pad image size = 128 + 12 * 2 = 152
+	vi.AddV1cParams().Config("L16", 1, 12, 24, 8) // 128 / 8 = 16
+	vi.AddV1cParams().Config("M16", 1, 12, 12, 4) // 128 / 4 = 32
+	vi.AddV1cParams().Config("H16", 1, 12, 6, 2)  // 128 / 2 = 64
+
+	// 64 + 44*2 = 152
+	vi.AddV1cParams().Config("M8", 2, 44, 12, 4)
+	vi.AddV1cParams().Config("H8", 2, 44, 6, 2)
+
+	vi.AddDoGParams().Config("L16", 1, 12, 16)
+	vi.AddDoGParams().Config("M16", 1, 12, 8)
+
+	vi.AddDoGParams().Config("L8", 2, 44, 8)
+	vi.AddDoGParams().Config("M8", 2, 44, 4)
+}
+
+// StdLowMedHigh8Deg configures a small 8 degree foveal field of view
+// (FOV), at the same resolution per degree as the 16 degree FOV,
+// with Low, Medium and High resolution V1c filters and low and
+// medium resolution color DoGs. This operates on 64x64 image content.
+// Output shapes:
+//
+//	V1c L8: [4, 4, 9, 4]; M8: [8, 8, 9, 4]; H8: [16, 16, 9, 4]
+//	DoG L8: [4, 4, 2, 2]; M8: [8, 8, 2, 2]
+func (vi *V1cMulti) StdLowMedHigh8Deg() {
+	vi.Image.Size = image.Point{64, 64}
+	// target full wrap/pad image size = 64 + 12 * 2 = 88
+	vi.AddV1cParams().Config("L8", 1, 12, 24, 8) // 64 / 8 = 8
+	vi.AddV1cParams().Config("M8", 1, 12, 12, 4) // 64 / 4 = 16
+	vi.AddV1cParams().Config("H8", 1, 12, 6, 2)  // 64 / 2 = 32
+
+	vi.AddDoGParams().Config("L8", 1, 12, 16)
+	vi.AddDoGParams().Config("M8", 1, 12, 8)
+}
+
+// StdLowMed32DegZoom1 configures a wide 32 degree field of view (FOV),
+// at the same resolution per degree as the 16 degree FOV, with Low
+// and Medium resolution V1c filters, and 1 level of spatial zoom
+// (16 degrees) with Medium and High resolution V1c filters,
+// along with corresponding color DoGs.
+// This operates on 256x256 image content. Output shapes:
+//
+//	V1c L32, M16: [16, 16, 9, 4]; M32, H16: [32, 32, 9, 4]
+//	DoG L32, L16: [16, 16, 2, 2]; M32, M16: [32, 32, 2, 2]
+func (vi *V1cMulti) StdLowMed32DegZoom1() {
+	vi.Image.Size = image.Point{256, 256}
+	// target full wrap/pad image size = 256 + 12 * 2 = 280
+	vi.AddV1cParams().Config("L32", 1, 12, 24, 8) // 256 / 8 = 32
+	vi.AddV1cParams().Config("M32", 1, 12, 12, 4) // 256 / 4 = 64
+
+	// 128 + 76*2 = 280
+	vi.AddV1cParams().Config("M16", 2, 76, 12, 4)
+	vi.AddV1cParams().Config("H16", 2, 76, 6, 2)
+
+	vi.AddDoGParams().Config("L32", 1, 12, 16)
+	vi.AddDoGParams().Config("M32", 1, 12, 8)
+
+	vi.AddDoGParams().Config("L16", 2, 76, 8)
+	vi.AddDoGParams().Config("M16", 2, 76, 4)
+}
+
+// Out4Rows returns the number of rows in the V1c outputs,
+// per the V1cParams [V1cParams.Layout].
 func (vi *V1cMulti) Out4Rows() int {
-	out4Rows := 5
-	if vi.SplitColor {
-		out4Rows = 9
+	return len(v1cRows(!vi.Grey, vi.SplitColor))
+}
+
+// Layouts returns the layouts of the V1cParams outputs followed by
+// the DoGParams outputs, which are valid after Config.
+func (vi *V1cMulti) Layouts() []*Layout {
+	var lys []*Layout
+	for _, vp := range vi.V1cParams {
+		lys = append(lys, vp.Layout(vi.SplitColor))
+	}
+	for _, vp := range vi.DoGParams {
+		lys = append(lys, vp.Layout())
 	}
-	return out4Rows
+	return lys
 }
 
 // Config configures the filtering pipeline with all the current parameters.
@@ -329,6 +472,8 @@ func (vi *V1cMulti) Config(ndata int) {
 	v1sKwtaIdx := 0
 	*vi.V1.NewKWTAParams() = vi.DoGKWTA
 	dogKwtaIdx := 1
+	*vi.V1.NewKWTAParams() = vi.OutKWTA
+	vi.outKWTAIdx = 2
 	img := vi.V1.NewImage(inSz)
 	wrap := vi.V1.NewImage(inSz)
 	lmsOp := vi.V1.NewImage(inSz)
@@ -0,0 +1,264 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1std
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//////// Y4MFrames
+
+// Y4MFrames is a [FrameSource] for an uncompressed YUV4MPEG2 (.y4m)
+// video file, with 8 bit 4:2:0, 4:2:2, 4:4:4 or mono color.
+// Frames are read from the file as needed, as [image.YCbCr]
+// (or [image.Gray] for mono).
+type Y4MFrames struct {
+	// Width, Height are the frame size.
+	Width, Height int
+
+	// FPS is the frame rate, in frames per second.
+	FPS float32
+
+	// Colorspace is the Y4M colorspace (C parameter), e.g., 420jpeg.
+	Colorspace string
+
+	// Mono is true for mono (greyscale) video.
+	Mono bool
+
+	// Ratio is the chroma subsample ratio, if not Mono.
+	Ratio image.YCbCrSubsampleRatio
+
+	// file is the open video file.
+	file *os.File
+
+	// offsets are the file offsets of the frame data for each frame.
+	offsets []int64
+
+	// frameSize is the size of the data for each frame in bytes.
+	frameSize int
+}
+
+// OpenY4MFrames opens a YUV4MPEG2 (.y4m) video file as a [Y4MFrames],
+// indexing the frames in the file, which remains open until Close.
+func OpenY4MFrames(filename string) (*Y4MFrames, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	yf := &Y4MFrames{file: f}
+	if err := yf.index(bufio.NewReader(f)); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("v1std.OpenY4MFrames: %s: %w", filename, err)
+	}
+	return yf, nil
+}
+
+// index parses the header and records the offsets of the frames.
+func (yf *Y4MFrames) index(br *bufio.Reader) error {
+	hdr, err := br.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	flds := strings.Fields(hdr)
+	if len(flds) == 0 || flds[0] != "YUV4MPEG2" {
+		return fmt.Errorf("not a YUV4MPEG2 file")
+	}
+	yf.Colorspace = "420jpeg"
+	yf.FPS = 25
+	for _, fld := range flds[1:] {
+		val := fld[1:]
+		switch fld[0] {
+		case 'W':
+			yf.Width, err = strconv.Atoi(val)
+		case 'H':
+			yf.Height, err = strconv.Atoi(val)
+		case 'C':
+			yf.Colorspace = val
+		case 'F':
+			num, den, _ := strings.Cut(val, ":")
+			n, nerr := strconv.Atoi(num)
+			d, derr := strconv.Atoi(den)
+			if nerr == nil && derr == nil && d > 0 {
+				yf.FPS = float32(n) / float32(d)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if yf.Width <= 0 || yf.Height <= 0 {
+		return fmt.Errorf("invalid frame size: %d x %d", yf.Width, yf.Height)
+	}
+	w, h := yf.Width, yf.Height
+	cw, ch := (w+1)/2, (h+1)/2
+	switch yf.Colorspace {
+	case "420", "420jpeg", "420paldv", "420mpeg2":
+		yf.Ratio = image.YCbCrSubsampleRatio420
+		yf.frameSize = w*h + 2*cw*ch
+	case "422":
+		yf.Ratio = image.YCbCrSubsampleRatio422
+		yf.frameSize = w*h + 2*cw*h
+	case "444":
+		yf.Ratio = image.YCbCrSubsampleRatio444
+		yf.frameSize = 3 * w * h
+	case "mono":
+		yf.Mono = true
+		yf.frameSize = w * h
+	default:
+		return fmt.Errorf("unsupported colorspace: %s", yf.Colorspace)
+	}
+	off := int64(len(hdr))
+	for {
+		fhdr, err := br.ReadString('\n')
+		if err == io.EOF && fhdr == "" {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(fhdr, "FRAME") {
+			return fmt.Errorf("invalid frame header at offset: %d", off)
+		}
+		off += int64(len(fhdr))
+		n, _ := br.Discard(yf.frameSize)
+		if n < yf.frameSize { // truncated last frame
+			break
+		}
+		yf.offsets = append(yf.offsets, off)
+		off += int64(n)
+	}
+	if len(yf.offsets) == 0 {
+		return fmt.Errorf("no frames")
+	}
+	return nil
+}
+
+func (yf *Y4MFrames) NumFrames() int     { return len(yf.offsets) }
+func (yf *Y4MFrames) FrameRate() float32 { return yf.FPS }
+
+func (yf *Y4MFrames) Close() error {
+	return yf.file.Close()
+}
+
+func (yf *Y4MFrames) Frame(i int) (image.Image, error) {
+	buf := make([]byte, yf.frameSize)
+	if _, err := yf.file.ReadAt(buf, yf.offsets[i]); err != nil {
+		return nil, err
+	}
+	rect := image.Rect(0, 0, yf.Width, yf.Height)
+	if yf.Mono {
+		return &image.Gray{Pix: buf, Stride: yf.Width, Rect: rect}, nil
+	}
+	img := image.NewYCbCr(rect, yf.Ratio)
+	ny, nc := len(img.Y), len(img.Cb)
+	copy(img.Y, buf[:ny])
+	copy(img.Cb, buf[ny:ny+nc])
+	copy(img.Cr, buf[ny+nc:])
+	return img, nil
+}
+
+//////// RawFrames
+
+// RawVideoFormats are the pixel formats of headerless raw video files,
+// for [RawFrames].
+type RawVideoFormats int32 //enums:enum
+
+const (
+	// RawRGB24 has 3 bytes per pixel, for R, G, B.
+	RawRGB24 RawVideoFormats = iota
+
+	// RawGray8 has 1 grey byte per pixel.
+	RawGray8
+)
+
+// BytesPerPixel returns the number of bytes per pixel for the format.
+func (rf RawVideoFormats) BytesPerPixel() int {
+	if rf == RawGray8 {
+		return 1
+	}
+	return 3
+}
+
+// RawFrames is a [FrameSource] for a headerless raw video file of
+// sequential uncompressed frames (e.g., from ffmpeg -f rawvideo),
+// for which the frame size, pixel format and frame rate must be given.
+// Frames are read from the file as needed.
+type RawFrames struct {
+	// Width, Height are the frame size.
+	Width, Height int
+
+	// Format is the pixel format.
+	Format RawVideoFormats
+
+	// FPS is the frame rate, in frames per second, if known.
+	FPS float32
+
+	// file is the open video file.
+	file *os.File
+
+	// nFrames is the number of complete frames in the file.
+	nFrames int
+}
+
+// OpenRawFrames opens a headerless raw video file as a [RawFrames],
+// with given frame size, pixel format and frame rate (0 if not known).
+// The file remains open until Close.
+func OpenRawFrames(filename string, width, height int, format RawVideoFormats, fps float32) (*RawFrames, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("v1std.OpenRawFrames: invalid frame size: %d x %d", width, height)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rf := &RawFrames{Width: width, Height: height, Format: format, FPS: fps, file: f}
+	rf.nFrames = int(st.Size() / int64(rf.frameSize()))
+	if rf.nFrames == 0 {
+		f.Close()
+		return nil, fmt.Errorf("v1std.OpenRawFrames: no frames in: %s", filename)
+	}
+	return rf, nil
+}
+
+// frameSize returns the size of each frame in bytes.
+func (rf *RawFrames) frameSize() int {
+	return rf.Width * rf.Height * rf.Format.BytesPerPixel()
+}
+
+func (rf *RawFrames) NumFrames() int     { return rf.nFrames }
+func (rf *RawFrames) FrameRate() float32 { return rf.FPS }
+
+func (rf *RawFrames) Close() error {
+	return rf.file.Close()
+}
+
+func (rf *RawFrames) Frame(i int) (image.Image, error) {
+	fsz := rf.frameSize()
+	buf := make([]byte, fsz)
+	if _, err := rf.file.ReadAt(buf, int64(i)*int64(fsz)); err != nil {
+		return nil, err
+	}
+	rect := image.Rect(0, 0, rf.Width, rf.Height)
+	if rf.Format == RawGray8 {
+		return &image.Gray{Pix: buf, Stride: rf.Width, Rect: rect}, nil
+	}
+	img := image.NewNRGBA(rect)
+	for pi := range rf.Width * rf.Height {
+		copy(img.Pix[4*pi:4*pi+3], buf[3*pi:3*pi+3])
+		img.Pix[4*pi+3] = 255
+	}
+	return img, nil
+}
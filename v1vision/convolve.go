@@ -13,6 +13,7 @@ package v1vision
 // The input Image *must* have border (padding) so that filters are
 // applied without any bounds checking: wrapping etc is all
 // done in the padding process, which is much more efficient.
+// Uses the mask image from [V1Vision.SetMaskImage] if set.
 func (vv *V1Vision) NewConvolveImage(in, irgb, ftyp, fn int, gain float32, geom *Geom) int {
 	op := vv.NewOp()
 	op.Op = ConvolveImage
@@ -24,6 +25,7 @@ func (vv *V1Vision) NewConvolveImage(in, irgb, ftyp, fn int, gain float32, geom
 	op.FilterType = int32(ftyp)
 	op.FilterN = int32(fn)
 	op.FloatArg1 = gain
+	op.InImage2 = int32(vv.maskImage)
 	op.Geom = *geom
 	return out
 }
@@ -37,6 +39,7 @@ func (vv *V1Vision) NewConvolveImage(in, irgb, ftyp, fn int, gain float32, geom
 // The input Image *must* have border (padding) so that filters are
 // applied without any bounds checking: wrapping etc is all
 // done in the padding process, which is much more efficient.
+// Uses the mask image from [V1Vision.SetMaskImage] if set.
 func (vv *V1Vision) NewConvolveDiff(in1, rgb1, in2, rgb2, ftyp, fidx1, fidx2, out, outfi int, gain, gainOn float32, geom *Geom) int {
 	op := vv.NewOp()
 	op.Op = ConvolveDiff
@@ -52,6 +55,7 @@ func (vv *V1Vision) NewConvolveDiff(in1, rgb1, in2, rgb2, ftyp, fidx1, fidx2, ou
 	op.FloatArg2 = gainOn
 	op.OutValue = int32(out)
 	op.OutScalar = int32(outfi)
+	op.InImage2 = int32(vv.maskImage)
 	op.Geom = *geom
 	return out
 }
@@ -73,11 +77,15 @@ func (op *Op) ConvolveImage(i, ni int32) {
 	fyn := int(op.Geom.FilterSize.Y)
 	fxn := int(op.Geom.FilterSize.X)
 	sum := float32(0)
-	for fy := range fyn {
-		for fx := range fxn {
-			iv := Images.Value(int(op.InImage), int(ni), int(op.InImageRGB), int(yi+fy), int(xi+fx))
-			fv := Filters.Value(int(op.FilterType), int(fi), int(fy), int(fx))
-			sum += fv * iv
+	if op.InImage2 >= 0 {
+		sum = op.ConvolveMasked(op.InImage, op.InImageRGB, fi, yi, xi, ni)
+	} else {
+		for fy := range fyn {
+			for fx := range fxn {
+				iv := Images.Value(int(op.InImage), int(ni), int(op.InImageRGB), int(yi+fy), int(xi+fx))
+				fv := Filters.Value(int(op.FilterType), int(fi), int(fy), int(fx))
+				sum += fv * iv
+			}
 		}
 	}
 	sum *= op.FloatArg1
@@ -105,14 +113,19 @@ func (op *Op) ConvolveDiff(i, ni int32) {
 	fxn := int(op.Geom.FilterSize.X)
 	sumOn := float32(0)
 	sumOff := float32(0)
-	for fy := range fyn {
-		for fx := range fxn {
-			iv1 := Images.Value(int(op.InImage), int(ni), int(op.InImageRGB), int(yi+fy), int(xi+fx))
-			iv2 := Images.Value(int(op.InValue2), int(ni), int(op.OutImage2), int(yi+fy), int(xi+fx))
-			fv1 := Filters.Value(int(op.FilterType), int(op.FilterN), int(fy), int(fx))
-			fv2 := Filters.Value(int(op.FilterType), int(op.IntArg1), int(fy), int(fx))
-			sumOn += fv1 * iv1
-			sumOff += fv2 * iv2
+	if op.InImage2 >= 0 {
+		sumOn = op.ConvolveMasked(op.InImage, op.InImageRGB, op.FilterN, yi, xi, ni)
+		sumOff = op.ConvolveMasked(op.InValue2, op.OutImage2, op.IntArg1, yi, xi, ni)
+	} else {
+		for fy := range fyn {
+			for fx := range fxn {
+				iv1 := Images.Value(int(op.InImage), int(ni), int(op.InImageRGB), int(yi+fy), int(xi+fx))
+				iv2 := Images.Value(int(op.InValue2), int(ni), int(op.OutImage2), int(yi+fy), int(xi+fx))
+				fv1 := Filters.Value(int(op.FilterType), int(op.FilterN), int(fy), int(fx))
+				fv2 := Filters.Value(int(op.FilterType), int(op.IntArg1), int(fy), int(fx))
+				sumOn += fv1 * iv1
+				sumOff += fv2 * iv2
+			}
 		}
 	}
 	diff := op.FloatArg1 * (op.FloatArg2*sumOn - sumOff)
@@ -125,4 +138,36 @@ func (op *Op) ConvolveDiff(i, ni int32) {
 	}
 }
 
+// ConvolveMasked returns the convolution of given image, rgb, and filter
+// index at given starting image location, using the InImage2 mask image,
+// where invalid (< 0.5) pixels are replaced by the mean of the valid
+// pixels under the filter, and returning 0 if the center is invalid.
+func (op *Op) ConvolveMasked(img, rgb, fi int32, yi, xi int, ni int32) float32 {
+	cy := yi + int(op.Geom.FilterLt.Y)
+	cx := xi + int(op.Geom.FilterLt.X)
+	if Images.Value(int(op.InImage2), int(ni), int(0), int(cy), int(cx)) < 0.5 {
+		return 0.0
+	}
+	fyn := int(op.Geom.FilterSize.Y)
+	fxn := int(op.Geom.FilterSize.X)
+	sum := float32(0)
+	isum := float32(0) // sum of filter over invalid
+	vsum := float32(0) // sum of valid image values
+	nv := 0
+	for fy := range fyn {
+		for fx := range fxn {
+			fv := Filters.Value(int(op.FilterType), int(fi), int(fy), int(fx))
+			if Images.Value(int(op.InImage2), int(ni), int(0), int(yi+fy), int(xi+fx)) < 0.5 {
+				isum += fv
+				continue
+			}
+			iv := Images.Value(int(img), int(ni), int(rgb), int(yi+fy), int(xi+fx))
+			sum += fv * iv
+			vsum += iv
+			nv++
+		}
+	}
+	return sum + isum*vsum/float32(nv)
+}
+
 //gosl:end
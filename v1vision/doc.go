@@ -18,6 +18,10 @@ tensor.Float32 that is required for doing the convolution.
 MaxPool function does Max-pooling over filtered results to reduce
 dimensionality, consistent with standard DCNN approaches.
 
+UnPool and DeconvImage provide a reverse pass from filtered (and pooled)
+results back into image space, using the argmax indexes recorded by
+MaxPool and the same filters, to visualize the retained information.
+
 Geom manages the geometry for going from an input image to the
 filtered output of that image.
 
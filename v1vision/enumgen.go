@@ -6,20 +6,20 @@ import (
 	"cogentcore.org/core/enums"
 )
 
-var _GPUVarsValues = []GPUVars{0, 1, 2, 3, 4, 5, 6, 7}
+var _GPUVarsValues = []GPUVars{0, 1, 2, 3, 4, 5, 6, 7, 8}
 
 // GPUVarsN is the highest valid value for type GPUVars, plus one.
 //
 //gosl:start
-const GPUVarsN GPUVars = 8
+const GPUVarsN GPUVars = 9
 
 //gosl:end
 
-var _GPUVarsValueMap = map[string]GPUVars{`CurOpVar`: 0, `KWTAsVar`: 1, `FiltersVar`: 2, `ImagesVar`: 3, `ValuesVar`: 4, `Values4DVar`: 5, `ScalarsVar`: 6, `InhibsVar`: 7}
+var _GPUVarsValueMap = map[string]GPUVars{`CurOpVar`: 0, `KWTAsVar`: 1, `FiltersVar`: 2, `ImagesVar`: 3, `RawImagesVar`: 4, `ValuesVar`: 5, `Values4DVar`: 6, `ScalarsVar`: 7, `InhibsVar`: 8}
 
-var _GPUVarsDescMap = map[GPUVars]string{0: ``, 1: ``, 2: ``, 3: ``, 4: ``, 5: ``, 6: ``, 7: ``}
+var _GPUVarsDescMap = map[GPUVars]string{0: ``, 1: ``, 2: ``, 3: ``, 4: ``, 5: ``, 6: ``, 7: ``, 8: ``}
 
-var _GPUVarsMap = map[GPUVars]string{0: `CurOpVar`, 1: `KWTAsVar`, 2: `FiltersVar`, 3: `ImagesVar`, 4: `ValuesVar`, 5: `Values4DVar`, 6: `ScalarsVar`, 7: `InhibsVar`}
+var _GPUVarsMap = map[GPUVars]string{0: `CurOpVar`, 1: `KWTAsVar`, 2: `FiltersVar`, 3: `ImagesVar`, 4: `RawImagesVar`, 5: `ValuesVar`, 6: `Values4DVar`, 7: `ScalarsVar`, 8: `InhibsVar`}
 
 // String returns the string representation of this GPUVars value.
 func (i GPUVars) String() string { return enums.String(i, _GPUVarsMap) }
@@ -51,20 +51,20 @@ func (i GPUVars) MarshalText() ([]byte, error) { return []byte(i.String()), nil
 // UnmarshalText implements the [encoding.TextUnmarshaler] interface.
 func (i *GPUVars) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "GPUVars") }
 
-var _InhibVarsValues = []InhibVars{0, 1, 2, 3, 4, 5, 6, 7, 8}
+var _InhibVarsValues = []InhibVars{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
 
 // InhibVarsN is the highest valid value for type InhibVars, plus one.
 //
 //gosl:start
-const InhibVarsN InhibVars = 9
+const InhibVarsN InhibVars = 10
 
 //gosl:end
 
-var _InhibVarsValueMap = map[string]InhibVars{`FFi`: 0, `FBi`: 1, `Gi`: 2, `GiOrig`: 3, `LayGi`: 4, `GeAvg`: 5, `GeMax`: 6, `ActAvg`: 7, `ActMax`: 8}
+var _InhibVarsValueMap = map[string]InhibVars{`FFi`: 0, `FBi`: 1, `Gi`: 2, `GiOrig`: 3, `LayGi`: 4, `GeAvg`: 5, `GeMax`: 6, `ActAvg`: 7, `ActMax`: 8, `DelActMax`: 9}
 
-var _InhibVarsDescMap = map[InhibVars]string{0: `computed feedforward inhibition`, 1: `computed feedback inhibition (total)`, 2: `overall value of the inhibition. This is what is added into the unit Gi inhibition level (along with any synaptic unit-driven inhibition)`, 3: `original value of the inhibition (before pool or other effects)`, 4: `for pools, this is the layer-level inhibition that is MAX&#39;d with the pool-level inhibition to produce the net inhibition.`, 5: `average Ge excitatory conductance values, which drive FF inhibition`, 6: `max Ge excitatory conductance values, which drive FF inhibition`, 7: `average Act activation values, which drive FB inhibition`, 8: `max Act activation values, which drive FB inhibition`}
+var _InhibVarsDescMap = map[InhibVars]string{0: `computed feedforward inhibition`, 1: `computed feedback inhibition (total)`, 2: `overall value of the inhibition. This is what is added into the unit Gi inhibition level (along with any synaptic unit-driven inhibition)`, 3: `original value of the inhibition (before pool or other effects)`, 4: `for pools, this is the layer-level inhibition that is MAX&#39;d with the pool-level inhibition to produce the net inhibition.`, 5: `average Ge excitatory conductance values, which drive FF inhibition`, 6: `max Ge excitatory conductance values, which drive FF inhibition`, 7: `average Act activation values, which drive FB inhibition`, 8: `max Act activation values, which drive FB inhibition`, 9: `max absolute change in Act activation values on the last iteration, for stopping iterations early.`}
 
-var _InhibVarsMap = map[InhibVars]string{0: `FFi`, 1: `FBi`, 2: `Gi`, 3: `GiOrig`, 4: `LayGi`, 5: `GeAvg`, 6: `GeMax`, 7: `ActAvg`, 8: `ActMax`}
+var _InhibVarsMap = map[InhibVars]string{0: `FFi`, 1: `FBi`, 2: `Gi`, 3: `GiOrig`, 4: `LayGi`, 5: `GeAvg`, 6: `GeMax`, 7: `ActAvg`, 8: `ActMax`, 9: `DelActMax`}
 
 // String returns the string representation of this InhibVars value.
 func (i InhibVars) String() string { return enums.String(i, _InhibVarsMap) }
@@ -98,20 +98,20 @@ func (i *InhibVars) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "InhibVars")
 }
 
-var _OperationsValues = []Operations{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23}
+var _OperationsValues = []Operations{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37}
 
 // OperationsN is the highest valid value for type Operations, plus one.
 //
 //gosl:start
-const OperationsN Operations = 24
+const OperationsN Operations = 38
 
 //gosl:end
 
-var _OperationsValueMap = map[string]Operations{`NoOp`: 0, `WrapPad`: 1, `EdgeAvg`: 2, `FadePad`: 3, `LMSOpponents`: 4, `LMSComponents`: 5, `ConvolveImage`: 6, `ConvolveDiff`: 7, `LogValues`: 8, `MaxScalar`: 9, `SumScalar`: 10, `MeanScalar`: 11, `NormDiv`: 12, `NeighInhib4`: 13, `KWTAInhib`: 14, `MaxPool`: 15, `MaxPolarity`: 16, `MaxCopy`: 17, `LenSum4`: 18, `EndStop4`: 19, `To4D`: 20, `MotionIntegrate`: 21, `MotionStar`: 22, `MotionFullField`: 23}
+var _OperationsValueMap = map[string]Operations{`NoOp`: 0, `WrapPad`: 1, `EdgeAvg`: 2, `FadePad`: 3, `LMSOpponents`: 4, `LMSComponents`: 5, `ConvolveImage`: 6, `ConvolveDiff`: 7, `LogValues`: 8, `MaxScalar`: 9, `SumScalar`: 10, `MeanScalar`: 11, `NormDiv`: 12, `NeighInhib4`: 13, `KWTAInhib`: 14, `KWTAInhib4D`: 15, `MaxPool`: 16, `MaxPolarity`: 17, `MaxCopy`: 18, `LenSum4`: 19, `EndStop4`: 20, `To4D`: 21, `MotionIntegrate`: 22, `MotionStar`: 23, `MotionFullField`: 24, `MotionFlow`: 25, `MotionOpticFlow`: 26, `MotionGrid`: 27, `TemporalFilter`: 28, `ResetValues`: 29, `ResetValues4D`: 30, `BinocularEnergy`: 31, `PoissonSpikes`: 32, `TopKPool`: 33, `TopKLayer`: 34, `UnPool`: 35, `DeconvImage`: 36, `UnpackImage`: 37}
 
-var _OperationsDescMap = map[Operations]string{0: ``, 1: `WrapPad wraps given padding width of float32 image around sides i.e., padding for left side of image is the (mirrored) bits from the right side of image, etc. InImage -&gt; OutImage, over InImageRGB (if 3, does all).`, 2: `EdgeAvg computes the average r,g,b values around the edges of an image, storing into Scalars. These are then used for FadePad.`, 3: `FadePad wraps given padding width of float32 image around sides i.e., padding for left side of image is the (mirrored) bits from the right side of image, etc, and fades result toward average edge value (passed in as arg). InImage -&gt; OutImage, over InImageRGB (if 3, does all).`, 4: `LMSOpponents computes Long-Medium-Short (RGB) perceptually-based color opponent values from InImage -&gt; OutImage. 0 = RedGreen (L-M), 1 = White-Black (grey), 2 = BlueYellow (S-(LM)),`, 5: `LMSComponents computes Long-Medium-Short (RGB) perceptually-based color component values from InImage -&gt; OutImage1, OutImage2. For each image, the organization of components is designed to align with the RGB components, using grey to fill in the extra bit. Image1: 0 = Red (L), 1 = Green (M), 2 = Grey Image2: 0 = Yellow (LM), 1 = Grey, 2 = Blue (S),`, 6: `ConvolveImage applies a filter to Image, writing to Values. InImage -&gt; OutValue, using FilterType, FilterN`, 7: `ConvolveDiff applies two different filters to two different [Image, component] inputs, computing their difference, with positive values in 0 and negative values in 1 polarity, at given feature dimension (innermost Values dimension). This is used to compute e.g., on-center DoG to one color component minus off-center to another component.`, 8: `LogValues sets values to 1 + log of values * Gain. InValue -&gt; OutValue (can be the same).`, 9: `MaxScalar computes Max over values. InValue = values, OutScalar = result.`, 10: `SumScalar computes Sum over values InValue = values, OutScalar = result.`, 11: `MeanScalar computes Mean over values InValue = values, OutScalar = result.`, 12: `NormDiv normalizes values by scalar InValue -&gt; OutValue (can be same), InScalar = norm factor.`, 13: `NeighInhib4 computes neighbor inhibition, as an optional preliminary step prior to KWTA. Currently only works with 4 angles (n features=4). Each unit gets inhibition from same feature in nearest orthogonal neighbors. Reduces redundancy of feature code.`, 14: `KWTAInhib computes k-winners-take-all inhibition, rate-code version, based on overall levels of activity, over multiple iterations.`, 15: `MaxPool performs max-pooling over given pool size and spacing, effectively reducing the dimensionality of the output by the spacing factor. Size must = spacing or 2 * spacing.`, 16: `MaxPolarity performs max-pooling over the polarity (on vs. off) dimension.`, 17: `MaxCopy performs simple max over 2 different values, for aggregating different channels (e.g., colors) into a summary, without changing the dimensionality.`, 18: `LenSum4 performs V1 complex-cell length-summing, extending the receptive field along the orientation angle one step. Works on output from [MaxPolarity] (first polarity dimension), only for the 4 angles case.`, 19: `EndStop4 performs V1 complex-cell end-stop, detecting an orthoginal angle at the end of a length-sum line. Only for the 4 angles case.`, 20: `To4D copies from Values to Values4D for aggregating final results across multiple feature dimensions (e.g., for assembling full V1 complex).`, 21: `MotionIntegrate does fast and slow motion integration from values to values: InValue -&gt; OutValue (should be different)`, 22: `MotionStar computes starburst-style motion on integrated fast and slow input values. Result is 4 * FilterN filter outputs, for Left, Right, Down, Up motion directions. InValue -&gt; OutValue (different, X and Y are -1 in output).`, 23: `MotionFullField computes full-field summary of output from MotionStar, into 4 Scalars for Left, Right, Down, Up. Opposite directions compete. OutScalar[0-3] = instantaneous full-field values per this frame OutScalar[4-7] = integrated full-field values over time`}
+var _OperationsDescMap = map[Operations]string{0: ``, 1: `WrapPad wraps given padding width of float32 image around sides i.e., padding for left side of image is the (mirrored) bits from the right side of image, etc. InImage -&gt; OutImage, over InImageRGB (if 3, does all).`, 2: `EdgeAvg computes the average r,g,b values around the edges of an image, storing into Scalars. These are then used for FadePad.`, 3: `FadePad wraps given padding width of float32 image around sides i.e., padding for left side of image is the (mirrored) bits from the right side of image, etc, and fades result toward average edge value (passed in as arg). InImage -&gt; OutImage, over InImageRGB (if 3, does all).`, 4: `LMSOpponents computes Long-Medium-Short (RGB) perceptually-based color opponent values from InImage -&gt; OutImage. 0 = RedGreen (L-M), 1 = White-Black (grey), 2 = BlueYellow (S-(LM)),`, 5: `LMSComponents computes Long-Medium-Short (RGB) perceptually-based color component values from InImage -&gt; OutImage1, OutImage2. For each image, the organization of components is designed to align with the RGB components, using grey to fill in the extra bit. Image1: 0 = Red (L), 1 = Green (M), 2 = Grey Image2: 0 = Yellow (LM), 1 = Grey, 2 = Blue (S),`, 6: `ConvolveImage applies a filter to Image, writing to Values. InImage -&gt; OutValue, using FilterType, FilterN`, 7: `ConvolveDiff applies two different filters to two different [Image, component] inputs, computing their difference, with positive values in 0 and negative values in 1 polarity, at given feature dimension (innermost Values dimension). This is used to compute e.g., on-center DoG to one color component minus off-center to another component.`, 8: `LogValues sets values to 1 + log of values * Gain. InValue -&gt; OutValue (can be the same).`, 9: `MaxScalar computes Max over values. InValue = values, OutScalar = result.`, 10: `SumScalar computes Sum over values InValue = values, OutScalar = result.`, 11: `MeanScalar computes Mean over values InValue = values, OutScalar = result.`, 12: `NormDiv normalizes values by scalar InValue -&gt; OutValue (can be same), InScalar = norm factor.`, 13: `NeighInhib4 computes neighbor inhibition, as an optional preliminary step prior to KWTA. Currently only works with 4 angles (n features=4). Each unit gets inhibition from same feature in nearest orthogonal neighbors. Reduces redundancy of feature code.`, 14: `KWTAInhib computes k-winners-take-all inhibition, rate-code version, based on overall levels of activity, over multiple iterations.`, 15: `KWTAInhib4D computes k-winners-take-all inhibition, rate-code version, on Values4D data, where each pool is the [UnitY][UnitX] values at each [PoolY][PoolX] location: InValue -&gt; OutValue4D (both Values4D).`, 16: `MaxPool performs max-pooling over given pool size and spacing, effectively reducing the dimensionality of the output by the spacing factor. Size must = spacing or 2 * spacing. If IntArg2 &gt; 0, the index of the max value within each pool is recorded in OutValue+1, for use in UnPool.`, 17: `MaxPolarity performs max-pooling over the polarity (on vs. off) dimension.`, 18: `MaxCopy performs simple max over 2 different values, for aggregating different channels (e.g., colors) into a summary, without changing the dimensionality.`, 19: `LenSum4 performs V1 complex-cell length-summing, extending the receptive field along the orientation angle one step. Works on output from [MaxPolarity] (first polarity dimension), only for the 4 angles case.`, 20: `EndStop4 performs V1 complex-cell end-stop, detecting an orthoginal angle at the end of a length-sum line. Only for the 4 angles case.`, 21: `To4D copies from Values to Values4D for aggregating final results across multiple feature dimensions (e.g., for assembling full V1 complex).`, 22: `MotionIntegrate does fast and slow motion integration from values to values: InValue -&gt; OutValue (should be different)`, 23: `MotionStar computes starburst-style motion on integrated fast and slow input values. Result is 4 * FilterN filter outputs, for Left, Right, Down, Up motion directions. InValue -&gt; OutValue (different, X and Y are -1 in output).`, 24: `MotionFullField computes full-field summary of output from MotionStar, into 4 Scalars for Left, Right, Down, Up. Opposite directions compete. OutScalar[0-3] = instantaneous full-field values per this frame OutScalar[4-7] = integrated full-field values over time`, 25: `MotionFlow computes a local flow field from the output of MotionStar, pooled over regions per Geom FilterSize, Spacing. Result is signed dx, dy in OutValue [Y][X][0][0-1], with positive values for Right and Up motion.`, 26: `MotionOpticFlow computes full-field optic flow summary of output from MotionStar, by projecting the local motion at each point onto radial and tangential templates around a focus point (FloatArg1, FloatArg2 = X, Y as proportion of size), into 4 Scalars for Expand, Contract, Clockwise, CounterClockwise.`, 27: `MotionGrid computes regional full-field summaries of output from MotionStar, over a grid of regions (Geom.FilterSize), with opponent competition and normalization by the InScalar sum of input activity. The normalization state is integrated over time in OutValue, and the opponent grid outputs are integrated over time into OutValue4D [GridY][GridX][2][2] for [Left,Right][Down,Up], as for full-field.`, 28: `TemporalFilter applies a stateful temporal filter kernel ([TemporalKernels] in IntArg1) to values across successive runs: InValue -&gt; OutValue, with OutValue+1 (and +2) holding filter state.`, 29: `ResetValues sets InValue to zero, for NData item in IntArg1 (all if &lt; 0). Used for resetting state outside of the Ops sequence.`, 30: `ResetValues4D sets OutValue4D to zero, for NData item in IntArg1 (all if &lt; 0). Used for resetting state outside of the Ops sequence.`, 31: `BinocularEnergy computes binocular disparity energy-model responses from left (InImage) and right (InImage2) images, using gabor quadrature-pair filters, over a range of position and phase disparities, writing to OutValue4D [Y][X][disparity][angle].`, 32: `PoissonSpikes generates Poisson spikes from rate-code activations in InValue (e.g., output of [KWTAInhib]), with probability per cycle of FloatArg1 * activation, over IntArg1 cycles, using IntArg2 as the random seed. Spike counts go to OutValue. Per-cycle spike trains can be computed on the CPU with [V1Vision.PoissonSpikeTrains].`, 33: `TopKPool does exact top-k selection within each pool ([Polarity][FilterN] at each location), setting all but the IntArg1 largest values to 0. InValue -&gt; OutValue.`, 34: `TopKLayer does exact top-k selection over the entire layer, setting all but the IntArg1 largest values to 0, except that values tied with the k-th largest are also kept. This is done by a bitwise search for the k-th largest value, counting values at or above each candidate threshold into InValue2 [Y] and OutScalar+0..2, followed by one thresholding pass. InValue -&gt; OutValue.`, 35: `UnPool performs inverse max-pooling of InValue -&gt; OutValue, placing each pooled value at the location of its max within the pool, per the argmax values in InValue2 recorded by MaxPool, or at all locations in the pool if InValue2 &lt; 0. Geom is the same as used for MaxPool, with OutValue at Geom.In size.`, 36: `DeconvImage performs reverse convolution of InValue values, as the output of ConvolveImage with the same filters and Geom, accumulating the sum of filter * activation (on - off polarity) into OutImage at OutImage2 color component.`, 37: `UnpackImage converts packed uint32 pixels in RawImages, in [RawFormats] IntArg2, into OutImage, with IntArg1 padding and flipping Y unless IntArg3 (TopZero) is set. Writes all RGB components if InImageRGB = 3, else greyscale to that component.`}
 
-var _OperationsMap = map[Operations]string{0: `NoOp`, 1: `WrapPad`, 2: `EdgeAvg`, 3: `FadePad`, 4: `LMSOpponents`, 5: `LMSComponents`, 6: `ConvolveImage`, 7: `ConvolveDiff`, 8: `LogValues`, 9: `MaxScalar`, 10: `SumScalar`, 11: `MeanScalar`, 12: `NormDiv`, 13: `NeighInhib4`, 14: `KWTAInhib`, 15: `MaxPool`, 16: `MaxPolarity`, 17: `MaxCopy`, 18: `LenSum4`, 19: `EndStop4`, 20: `To4D`, 21: `MotionIntegrate`, 22: `MotionStar`, 23: `MotionFullField`}
+var _OperationsMap = map[Operations]string{0: `NoOp`, 1: `WrapPad`, 2: `EdgeAvg`, 3: `FadePad`, 4: `LMSOpponents`, 5: `LMSComponents`, 6: `ConvolveImage`, 7: `ConvolveDiff`, 8: `LogValues`, 9: `MaxScalar`, 10: `SumScalar`, 11: `MeanScalar`, 12: `NormDiv`, 13: `NeighInhib4`, 14: `KWTAInhib`, 15: `KWTAInhib4D`, 16: `MaxPool`, 17: `MaxPolarity`, 18: `MaxCopy`, 19: `LenSum4`, 20: `EndStop4`, 21: `To4D`, 22: `MotionIntegrate`, 23: `MotionStar`, 24: `MotionFullField`, 25: `MotionFlow`, 26: `MotionOpticFlow`, 27: `MotionGrid`, 28: `TemporalFilter`, 29: `ResetValues`, 30: `ResetValues4D`, 31: `BinocularEnergy`, 32: `PoissonSpikes`, 33: `TopKPool`, 34: `TopKLayer`, 35: `UnPool`, 36: `DeconvImage`, 37: `UnpackImage`}
 
 // String returns the string representation of this Operations value.
 func (i Operations) String() string { return enums.String(i, _OperationsMap) }
@@ -144,3 +144,97 @@ func (i Operations) MarshalText() ([]byte, error) { return []byte(i.String()), n
 func (i *Operations) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "Operations")
 }
+
+var _TemporalKernelsValues = []TemporalKernels{0, 1}
+
+// TemporalKernelsN is the highest valid value for type TemporalKernels, plus one.
+//
+//gosl:start
+const TemporalKernelsN TemporalKernels = 2
+
+//gosl:end
+
+var _TemporalKernelsValueMap = map[string]TemporalKernels{`Exponential`: 0, `Biphasic`: 1}
+
+var _TemporalKernelsDescMap = map[TemporalKernels]string{0: `Exponential is a low-pass exponential integration of the input, producing a sustained response.`, 1: `Biphasic is a band-pass kernel computed as the difference between a fast and a slow exponential integration of the input, producing a transient response to both onsets and offsets.`}
+
+var _TemporalKernelsMap = map[TemporalKernels]string{0: `Exponential`, 1: `Biphasic`}
+
+// String returns the string representation of this TemporalKernels value.
+func (i TemporalKernels) String() string { return enums.String(i, _TemporalKernelsMap) }
+
+// SetString sets the TemporalKernels value from its string representation,
+// and returns an error if the string is invalid.
+func (i *TemporalKernels) SetString(s string) error {
+	return enums.SetString(i, s, _TemporalKernelsValueMap, "TemporalKernels")
+}
+
+// Int64 returns the TemporalKernels value as an int64.
+func (i TemporalKernels) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the TemporalKernels value from an int64.
+func (i *TemporalKernels) SetInt64(in int64) { *i = TemporalKernels(in) }
+
+// Desc returns the description of the TemporalKernels value.
+func (i TemporalKernels) Desc() string { return enums.Desc(i, _TemporalKernelsDescMap) }
+
+// TemporalKernelsValues returns all possible values for the type TemporalKernels.
+func TemporalKernelsValues() []TemporalKernels { return _TemporalKernelsValues }
+
+// Values returns all possible values for the type TemporalKernels.
+func (i TemporalKernels) Values() []enums.Enum { return enums.Values(_TemporalKernelsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i TemporalKernels) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *TemporalKernels) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "TemporalKernels")
+}
+
+var _RawFormatsValues = []RawFormats{0, 1, 2}
+
+// RawFormatsN is the highest valid value for type RawFormats, plus one.
+//
+//gosl:start
+const RawFormatsN RawFormats = 3
+
+//gosl:end
+
+var _RawFormatsValueMap = map[string]RawFormats{`RawRGBA`: 0, `RawNRGBA`: 1, `RawGray`: 2}
+
+var _RawFormatsDescMap = map[RawFormats]string{0: `RawRGBA has alpha-premultiplied R, G, B, A bytes, from lowest to highest, as in [image.RGBA].`, 1: `RawNRGBA has non-alpha-premultiplied R, G, B, A bytes, from lowest to highest, as in [image.NRGBA].`, 2: `RawGray has a single grey byte in the lowest byte, as in [image.Gray].`}
+
+var _RawFormatsMap = map[RawFormats]string{0: `RawRGBA`, 1: `RawNRGBA`, 2: `RawGray`}
+
+// String returns the string representation of this RawFormats value.
+func (i RawFormats) String() string { return enums.String(i, _RawFormatsMap) }
+
+// SetString sets the RawFormats value from its string representation,
+// and returns an error if the string is invalid.
+func (i *RawFormats) SetString(s string) error {
+	return enums.SetString(i, s, _RawFormatsValueMap, "RawFormats")
+}
+
+// Int64 returns the RawFormats value as an int64.
+func (i RawFormats) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the RawFormats value from an int64.
+func (i *RawFormats) SetInt64(in int64) { *i = RawFormats(in) }
+
+// Desc returns the description of the RawFormats value.
+func (i RawFormats) Desc() string { return enums.Desc(i, _RawFormatsDescMap) }
+
+// RawFormatsValues returns all possible values for the type RawFormats.
+func RawFormatsValues() []RawFormats { return _RawFormatsValues }
+
+// Values returns all possible values for the type RawFormats.
+func (i RawFormats) Values() []enums.Enum { return enums.Values(_RawFormatsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i RawFormats) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *RawFormats) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "RawFormats")
+}
@@ -43,10 +43,11 @@ const (
 	KWTAsVar GPUVars = 1
 	FiltersVar GPUVars = 2
 	ImagesVar GPUVars = 3
-	ValuesVar GPUVars = 4
-	Values4DVar GPUVars = 5
-	ScalarsVar GPUVars = 6
-	InhibsVar GPUVars = 7
+	RawImagesVar GPUVars = 4
+	ValuesVar GPUVars = 5
+	Values4DVar GPUVars = 6
+	ScalarsVar GPUVars = 7
+	InhibsVar GPUVars = 8
 )
 
 // Tensor stride variables
@@ -97,6 +98,8 @@ func GPUInit() {
 			var vr *gpu.Var
 			_ = vr
 			vr = sgp.Add("Images", gpu.Float32, 1, gpu.ComputeShader)
+			vr = sgp.Add("RawImages", gpu.Uint32, 1, gpu.ComputeShader)
+			vr.ReadOnly = true
 			vr = sgp.Add("Values", gpu.Float32, 1, gpu.ComputeShader)
 			vr = sgp.Add("Values4D", gpu.Float32, 1, gpu.ComputeShader)
 			vr = sgp.Add("Scalars", gpu.Float32, 1, gpu.ComputeShader)
@@ -109,6 +112,7 @@ func GPUInit() {
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(1, "Filters")
 		pl.AddVarUsed(2, "Images")
+		pl.AddVarUsed(2, "RawImages")
 		pl.AddVarUsed(2, "Scalars")
 		pl.AddVarUsed(2, "Values")
 		pl.AddVarUsed(2, "Values4D")
@@ -126,6 +130,7 @@ func GPUInit() {
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(2, "Inhibs")
 		pl.AddVarUsed(2, "Values")
+		pl.AddVarUsed(2, "Values4D")
 		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/KWTAIterLayerX.wgsl", sy)
 		pl.AddVarUsed(0, "TensorStrides")
 		pl.AddVarUsed(0, "CurOp")
@@ -135,12 +140,14 @@ func GPUInit() {
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(2, "Inhibs")
 		pl.AddVarUsed(0, "KWTAs")
+		pl.AddVarUsed(2, "Scalars")
 		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/KWTAIterPool.wgsl", sy)
 		pl.AddVarUsed(0, "TensorStrides")
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(2, "Inhibs")
 		pl.AddVarUsed(0, "KWTAs")
 		pl.AddVarUsed(2, "Values")
+		pl.AddVarUsed(2, "Values4D")
 		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/MaxScalarX.wgsl", sy)
 		pl.AddVarUsed(0, "TensorStrides")
 		pl.AddVarUsed(0, "CurOp")
@@ -164,6 +171,15 @@ func GPUInit() {
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(2, "Scalars")
 		pl.AddVarUsed(2, "Values")
+		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/MotionOpticFlowX.wgsl", sy)
+		pl.AddVarUsed(0, "TensorStrides")
+		pl.AddVarUsed(0, "CurOp")
+		pl.AddVarUsed(2, "Values")
+		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/MotionOpticFlowY.wgsl", sy)
+		pl.AddVarUsed(0, "TensorStrides")
+		pl.AddVarUsed(0, "CurOp")
+		pl.AddVarUsed(2, "Scalars")
+		pl.AddVarUsed(2, "Values")
 		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/SumScalarX.wgsl", sy)
 		pl.AddVarUsed(0, "TensorStrides")
 		pl.AddVarUsed(0, "CurOp")
@@ -173,6 +189,20 @@ func GPUInit() {
 		pl.AddVarUsed(0, "CurOp")
 		pl.AddVarUsed(2, "Scalars")
 		pl.AddVarUsed(2, "Values")
+		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/TopKLayerCountX.wgsl", sy)
+		pl.AddVarUsed(0, "TensorStrides")
+		pl.AddVarUsed(0, "CurOp")
+		pl.AddVarUsed(2, "Scalars")
+		pl.AddVarUsed(2, "Values")
+		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/TopKLayerCountY.wgsl", sy)
+		pl.AddVarUsed(0, "TensorStrides")
+		pl.AddVarUsed(0, "CurOp")
+		pl.AddVarUsed(2, "Scalars")
+		pl.AddVarUsed(2, "Values")
+		pl = gpu.NewComputePipelineShaderFS(shaders, "shaders/TopKLayerInit.wgsl", sy)
+		pl.AddVarUsed(0, "TensorStrides")
+		pl.AddVarUsed(0, "CurOp")
+		pl.AddVarUsed(2, "Scalars")
 		sy.Config()
 	}
 }
@@ -696,6 +726,90 @@ func RunOneMotionFullFieldY(n int, syncVars ...GPUVars) {
 		RunMotionFullFieldYCPU(n)
 	}
 }
+// RunMotionOpticFlowX runs the MotionOpticFlowX kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// Can call multiple Run* kernels in a row, which are then all launched
+// in the same command submission on the GPU, which is by far the most efficient.
+// MUST call RunDone (with optional vars to sync) after all Run calls.
+// Alternatively, a single-shot RunOneMotionOpticFlowX call does Run and Done for a
+// single run-and-sync case.
+func RunMotionOpticFlowX(n int) {
+	if UseGPU {
+		RunMotionOpticFlowXGPU(n)
+	} else {
+		RunMotionOpticFlowXCPU(n)
+	}
+}
+
+// RunMotionOpticFlowXGPU runs the MotionOpticFlowX kernel on the GPU. See [RunMotionOpticFlowX] for more info.
+func RunMotionOpticFlowXGPU(n int) {
+	sy := GPUSystem
+	pl := sy.ComputePipelines["MotionOpticFlowX"]
+	ce, _ := sy.BeginComputePass()
+	pl.Dispatch1D(ce, n, 64)
+}
+
+// RunMotionOpticFlowXCPU runs the MotionOpticFlowX kernel on the CPU.
+func RunMotionOpticFlowXCPU(n int) {
+	gpu.VectorizeFunc(0, n, MotionOpticFlowX)
+}
+
+// RunOneMotionOpticFlowX runs the MotionOpticFlowX kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// This version then calls RunDone with the given variables to sync
+// after the Run, for a single-shot Run-and-Done call. If multiple kernels
+// can be run in sequence, it is much more efficient to do multiple Run*
+// calls followed by a RunDone call.
+func RunOneMotionOpticFlowX(n int, syncVars ...GPUVars) {
+	if UseGPU {
+		RunMotionOpticFlowXGPU(n)
+		RunDone(syncVars...)
+	} else {
+		RunMotionOpticFlowXCPU(n)
+	}
+}
+// RunMotionOpticFlowY runs the MotionOpticFlowY kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// Can call multiple Run* kernels in a row, which are then all launched
+// in the same command submission on the GPU, which is by far the most efficient.
+// MUST call RunDone (with optional vars to sync) after all Run calls.
+// Alternatively, a single-shot RunOneMotionOpticFlowY call does Run and Done for a
+// single run-and-sync case.
+func RunMotionOpticFlowY(n int) {
+	if UseGPU {
+		RunMotionOpticFlowYGPU(n)
+	} else {
+		RunMotionOpticFlowYCPU(n)
+	}
+}
+
+// RunMotionOpticFlowYGPU runs the MotionOpticFlowY kernel on the GPU. See [RunMotionOpticFlowY] for more info.
+func RunMotionOpticFlowYGPU(n int) {
+	sy := GPUSystem
+	pl := sy.ComputePipelines["MotionOpticFlowY"]
+	ce, _ := sy.BeginComputePass()
+	pl.Dispatch1D(ce, n, 64)
+}
+
+// RunMotionOpticFlowYCPU runs the MotionOpticFlowY kernel on the CPU.
+func RunMotionOpticFlowYCPU(n int) {
+	gpu.VectorizeFunc(0, n, MotionOpticFlowY)
+}
+
+// RunOneMotionOpticFlowY runs the MotionOpticFlowY kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// This version then calls RunDone with the given variables to sync
+// after the Run, for a single-shot Run-and-Done call. If multiple kernels
+// can be run in sequence, it is much more efficient to do multiple Run*
+// calls followed by a RunDone call.
+func RunOneMotionOpticFlowY(n int, syncVars ...GPUVars) {
+	if UseGPU {
+		RunMotionOpticFlowYGPU(n)
+		RunDone(syncVars...)
+	} else {
+		RunMotionOpticFlowYCPU(n)
+	}
+}
 // RunSumScalarX runs the SumScalarX kernel with given number of elements,
 // on either the CPU or GPU depending on the UseGPU variable.
 // Can call multiple Run* kernels in a row, which are then all launched
@@ -780,6 +894,132 @@ func RunOneSumScalarY(n int, syncVars ...GPUVars) {
 		RunSumScalarYCPU(n)
 	}
 }
+// RunTopKLayerCountX runs the TopKLayerCountX kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// Can call multiple Run* kernels in a row, which are then all launched
+// in the same command submission on the GPU, which is by far the most efficient.
+// MUST call RunDone (with optional vars to sync) after all Run calls.
+// Alternatively, a single-shot RunOneTopKLayerCountX call does Run and Done for a
+// single run-and-sync case.
+func RunTopKLayerCountX(n int) {
+	if UseGPU {
+		RunTopKLayerCountXGPU(n)
+	} else {
+		RunTopKLayerCountXCPU(n)
+	}
+}
+
+// RunTopKLayerCountXGPU runs the TopKLayerCountX kernel on the GPU. See [RunTopKLayerCountX] for more info.
+func RunTopKLayerCountXGPU(n int) {
+	sy := GPUSystem
+	pl := sy.ComputePipelines["TopKLayerCountX"]
+	ce, _ := sy.BeginComputePass()
+	pl.Dispatch1D(ce, n, 64)
+}
+
+// RunTopKLayerCountXCPU runs the TopKLayerCountX kernel on the CPU.
+func RunTopKLayerCountXCPU(n int) {
+	gpu.VectorizeFunc(0, n, TopKLayerCountX)
+}
+
+// RunOneTopKLayerCountX runs the TopKLayerCountX kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// This version then calls RunDone with the given variables to sync
+// after the Run, for a single-shot Run-and-Done call. If multiple kernels
+// can be run in sequence, it is much more efficient to do multiple Run*
+// calls followed by a RunDone call.
+func RunOneTopKLayerCountX(n int, syncVars ...GPUVars) {
+	if UseGPU {
+		RunTopKLayerCountXGPU(n)
+		RunDone(syncVars...)
+	} else {
+		RunTopKLayerCountXCPU(n)
+	}
+}
+// RunTopKLayerCountY runs the TopKLayerCountY kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// Can call multiple Run* kernels in a row, which are then all launched
+// in the same command submission on the GPU, which is by far the most efficient.
+// MUST call RunDone (with optional vars to sync) after all Run calls.
+// Alternatively, a single-shot RunOneTopKLayerCountY call does Run and Done for a
+// single run-and-sync case.
+func RunTopKLayerCountY(n int) {
+	if UseGPU {
+		RunTopKLayerCountYGPU(n)
+	} else {
+		RunTopKLayerCountYCPU(n)
+	}
+}
+
+// RunTopKLayerCountYGPU runs the TopKLayerCountY kernel on the GPU. See [RunTopKLayerCountY] for more info.
+func RunTopKLayerCountYGPU(n int) {
+	sy := GPUSystem
+	pl := sy.ComputePipelines["TopKLayerCountY"]
+	ce, _ := sy.BeginComputePass()
+	pl.Dispatch1D(ce, n, 64)
+}
+
+// RunTopKLayerCountYCPU runs the TopKLayerCountY kernel on the CPU.
+func RunTopKLayerCountYCPU(n int) {
+	gpu.VectorizeFunc(0, n, TopKLayerCountY)
+}
+
+// RunOneTopKLayerCountY runs the TopKLayerCountY kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// This version then calls RunDone with the given variables to sync
+// after the Run, for a single-shot Run-and-Done call. If multiple kernels
+// can be run in sequence, it is much more efficient to do multiple Run*
+// calls followed by a RunDone call.
+func RunOneTopKLayerCountY(n int, syncVars ...GPUVars) {
+	if UseGPU {
+		RunTopKLayerCountYGPU(n)
+		RunDone(syncVars...)
+	} else {
+		RunTopKLayerCountYCPU(n)
+	}
+}
+// RunTopKLayerInit runs the TopKLayerInit kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// Can call multiple Run* kernels in a row, which are then all launched
+// in the same command submission on the GPU, which is by far the most efficient.
+// MUST call RunDone (with optional vars to sync) after all Run calls.
+// Alternatively, a single-shot RunOneTopKLayerInit call does Run and Done for a
+// single run-and-sync case.
+func RunTopKLayerInit(n int) {
+	if UseGPU {
+		RunTopKLayerInitGPU(n)
+	} else {
+		RunTopKLayerInitCPU(n)
+	}
+}
+
+// RunTopKLayerInitGPU runs the TopKLayerInit kernel on the GPU. See [RunTopKLayerInit] for more info.
+func RunTopKLayerInitGPU(n int) {
+	sy := GPUSystem
+	pl := sy.ComputePipelines["TopKLayerInit"]
+	ce, _ := sy.BeginComputePass()
+	pl.Dispatch1D(ce, n, 64)
+}
+
+// RunTopKLayerInitCPU runs the TopKLayerInit kernel on the CPU.
+func RunTopKLayerInitCPU(n int) {
+	gpu.VectorizeFunc(0, n, TopKLayerInit)
+}
+
+// RunOneTopKLayerInit runs the TopKLayerInit kernel with given number of elements,
+// on either the CPU or GPU depending on the UseGPU variable.
+// This version then calls RunDone with the given variables to sync
+// after the Run, for a single-shot Run-and-Done call. If multiple kernels
+// can be run in sequence, it is much more efficient to do multiple Run*
+// calls followed by a RunDone call.
+func RunOneTopKLayerInit(n int, syncVars ...GPUVars) {
+	if UseGPU {
+		RunTopKLayerInitGPU(n)
+		RunDone(syncVars...)
+	} else {
+		RunTopKLayerInitCPU(n)
+	}
+}
 // RunDone must be called after Run* calls to start compute kernels.
 // This actually submits the kernel jobs to the GPU, and adds commands
 // to synchronize the given variables back from the GPU to the CPU.
@@ -817,6 +1057,9 @@ func ToGPU(vars ...GPUVars) {
 		case ImagesVar:
 			v, _ := syVars.ValueByIndex(2, "Images", 0)
 			gpu.SetValueFrom(v, Images.Values)
+		case RawImagesVar:
+			v, _ := syVars.ValueByIndex(2, "RawImages", 0)
+			gpu.SetValueFrom(v, RawImages.Values)
 		case ValuesVar:
 			v, _ := syVars.ValueByIndex(2, "Values", 0)
 			gpu.SetValueFrom(v, Values.Values)
@@ -850,7 +1093,7 @@ func ToGPUTensorStrides() {
 	}
 	sy := GPUSystem
 	syVars := sy.Vars()
-	TensorStrides.SetShapeSizes(60)
+	TensorStrides.SetShapeSizes(70)
 	TensorStrides.SetInt1D(Filters.Shape().Strides[0], 0)
 	TensorStrides.SetInt1D(Filters.Shape().Strides[1], 1)
 	TensorStrides.SetInt1D(Filters.Shape().Strides[2], 2)
@@ -860,25 +1103,28 @@ func ToGPUTensorStrides() {
 	TensorStrides.SetInt1D(Images.Shape().Strides[2], 12)
 	TensorStrides.SetInt1D(Images.Shape().Strides[3], 13)
 	TensorStrides.SetInt1D(Images.Shape().Strides[4], 14)
-	TensorStrides.SetInt1D(Values.Shape().Strides[0], 20)
-	TensorStrides.SetInt1D(Values.Shape().Strides[1], 21)
-	TensorStrides.SetInt1D(Values.Shape().Strides[2], 22)
-	TensorStrides.SetInt1D(Values.Shape().Strides[3], 23)
-	TensorStrides.SetInt1D(Values.Shape().Strides[4], 24)
-	TensorStrides.SetInt1D(Values.Shape().Strides[5], 25)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[0], 30)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[1], 31)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[2], 32)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[3], 33)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[4], 34)
-	TensorStrides.SetInt1D(Values4D.Shape().Strides[5], 35)
-	TensorStrides.SetInt1D(Scalars.Shape().Strides[0], 40)
-	TensorStrides.SetInt1D(Scalars.Shape().Strides[1], 41)
-	TensorStrides.SetInt1D(Inhibs.Shape().Strides[0], 50)
-	TensorStrides.SetInt1D(Inhibs.Shape().Strides[1], 51)
-	TensorStrides.SetInt1D(Inhibs.Shape().Strides[2], 52)
-	TensorStrides.SetInt1D(Inhibs.Shape().Strides[3], 53)
-	TensorStrides.SetInt1D(Inhibs.Shape().Strides[4], 54)
+	TensorStrides.SetInt1D(RawImages.Shape().Strides[0], 20)
+	TensorStrides.SetInt1D(RawImages.Shape().Strides[1], 21)
+	TensorStrides.SetInt1D(RawImages.Shape().Strides[2], 22)
+	TensorStrides.SetInt1D(Values.Shape().Strides[0], 30)
+	TensorStrides.SetInt1D(Values.Shape().Strides[1], 31)
+	TensorStrides.SetInt1D(Values.Shape().Strides[2], 32)
+	TensorStrides.SetInt1D(Values.Shape().Strides[3], 33)
+	TensorStrides.SetInt1D(Values.Shape().Strides[4], 34)
+	TensorStrides.SetInt1D(Values.Shape().Strides[5], 35)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[0], 40)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[1], 41)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[2], 42)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[3], 43)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[4], 44)
+	TensorStrides.SetInt1D(Values4D.Shape().Strides[5], 45)
+	TensorStrides.SetInt1D(Scalars.Shape().Strides[0], 50)
+	TensorStrides.SetInt1D(Scalars.Shape().Strides[1], 51)
+	TensorStrides.SetInt1D(Inhibs.Shape().Strides[0], 60)
+	TensorStrides.SetInt1D(Inhibs.Shape().Strides[1], 61)
+	TensorStrides.SetInt1D(Inhibs.Shape().Strides[2], 62)
+	TensorStrides.SetInt1D(Inhibs.Shape().Strides[3], 63)
+	TensorStrides.SetInt1D(Inhibs.Shape().Strides[4], 64)
 	v, _ := syVars.ValueByIndex(0, "TensorStrides", 0)
 	gpu.SetValueFrom(v, TensorStrides.Values)
 }
@@ -901,6 +1147,9 @@ func ReadFromGPU(vars ...GPUVars) {
 		case ImagesVar:
 			v, _ := syVars.ValueByIndex(2, "Images", 0)
 			v.GPUToRead(sy.CommandEncoder)
+		case RawImagesVar:
+			v, _ := syVars.ValueByIndex(2, "RawImages", 0)
+			v.GPUToRead(sy.CommandEncoder)
 		case ValuesVar:
 			v, _ := syVars.ValueByIndex(2, "Values", 0)
 			v.GPUToRead(sy.CommandEncoder)
@@ -939,6 +1188,10 @@ func SyncFromGPU(vars ...GPUVars) {
 			v, _ := syVars.ValueByIndex(2, "Images", 0)
 			v.ReadSync()
 			gpu.ReadToBytes(v, Images.Values)
+		case RawImagesVar:
+			v, _ := syVars.ValueByIndex(2, "RawImages", 0)
+			v.ReadSync()
+			gpu.ReadToBytes(v, RawImages.Values)
 		case ValuesVar:
 			v, _ := syVars.ValueByIndex(2, "Values", 0)
 			v.ReadSync()
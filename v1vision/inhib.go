@@ -44,6 +44,10 @@ const (
 	// max Act activation values,
 	// which drive FB inhibition
 	ActMax
+
+	// max absolute change in Act activation values on the last
+	// iteration, for stopping iterations early.
+	DelActMax
 )
 
 //gosl:end
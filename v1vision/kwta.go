@@ -7,6 +7,7 @@
 package v1vision
 
 import (
+	"cogentcore.org/core/math32"
 	"github.com/emer/v1vision/kwta"
 )
 
@@ -47,7 +48,8 @@ func (vv *V1Vision) NewNeighInhibOutput(fn int, geom *Geom) int {
 // geom.Out is the size of the outer Y,X dimensions, and
 // FilterSize is the inner Y,X dimensions.
 // Allocates a Inhibs to hold the inhibition compute values,
-// including an additional Y row for the layer-level values at the end.
+// including an additional Y row for the layer-level values at the end,
+// and a Scalar for the max delta-activation per data item.
 func (vv *V1Vision) NewKWTA(in, inExtGi, fn, kwtaIdx, inhIdx int, geom *Geom) int {
 	op := vv.NewOp()
 	op.Op = KWTAInhib
@@ -59,6 +61,32 @@ func (vv *V1Vision) NewKWTA(in, inExtGi, fn, kwtaIdx, inhIdx int, geom *Geom) in
 	op.Inhibs = int32(inhIdx)
 	op.FilterN = int32(fn)
 	op.KWTA = int32(kwtaIdx)
+	op.IntArg1 = 2
+	op.OutScalar = int32(vv.NewScalar(1))
+	op.Geom = *geom
+	return out
+}
+
+// NewKWTA4D adds a [KWTAInhib4D] operation, on Values4D data,
+// so that competition within each pool spans all of the
+// [UnitY][UnitX] values at each [PoolY][PoolX] location
+// (e.g., all the feature rows of an assembled V1c output).
+// in = Values4D index of raw initial inputs, with unitY, unitX
+// inner dimensions. geom.Out is the size of the outer PoolY, PoolX
+// dimensions. Allocates an Inhibs, as in [V1Vision.NewKWTA].
+// Returns the Values4D out index.
+func (vv *V1Vision) NewKWTA4D(in, unitY, unitX, kwtaIdx, inhIdx int, geom *Geom) int {
+	op := vv.NewOp()
+	op.Op = KWTAInhib4D
+	out := vv.NewValues4D(int(geom.Out.Y), int(geom.Out.X), unitY, unitX)
+	op.RunN = uint32(geom.Out.Y * geom.Out.X)
+	op.InValue = int32(in)
+	op.OutValue4D = int32(out)
+	op.Inhibs = int32(inhIdx)
+	op.FilterN = int32(unitX)
+	op.KWTA = int32(kwtaIdx)
+	op.IntArg1 = int32(unitY)
+	op.OutScalar = int32(vv.NewScalar(1))
 	op.Geom = *geom
 	return out
 }
@@ -94,8 +122,37 @@ func (op *Op) NeighInhib4(i, ni int32) {
 	Values.Set(op.FloatArg1*gi, int(op.OutValue), int(ni), int(yo), int(xo), int(pi), int(ang))
 }
 
-// KWTAInitPool is the kernel to initialize KWTA process, on Values data.
-// i = op.Geom.Out.Y * X. 2 x FilterN is inner 2 dims. Operates on Inhibs.
+// KWTAGe returns the raw input (ge) value for KWTA, from Values,
+// or Values4D for [KWTAInhib4D].
+func (op *Op) KWTAGe(ni, yo, xo, py, px int32) float32 {
+	if op.Op == KWTAInhib4D {
+		return Values4D.Value(int(op.InValue), int(ni), int(yo), int(xo), int(py), int(px))
+	}
+	return Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(py), int(px))
+}
+
+// KWTAAct returns the current KWTA activation, from Values,
+// or Values4D for [KWTAInhib4D].
+func (op *Op) KWTAAct(ni, yo, xo, py, px int32) float32 {
+	if op.Op == KWTAInhib4D {
+		return Values4D.Value(int(op.OutValue4D), int(ni), int(yo), int(xo), int(py), int(px))
+	}
+	return Values.Value(int(op.OutValue), int(ni), int(yo), int(xo), int(py), int(px))
+}
+
+// SetKWTAAct sets the KWTA activation, in Values,
+// or Values4D for [KWTAInhib4D].
+func (op *Op) SetKWTAAct(act float32, ni, yo, xo, py, px int32) {
+	if op.Op == KWTAInhib4D {
+		Values4D.Set(act, int(op.OutValue4D), int(ni), int(yo), int(xo), int(py), int(px))
+	} else {
+		Values.Set(act, int(op.OutValue), int(ni), int(yo), int(xo), int(py), int(px))
+	}
+}
+
+// KWTAInitPool is the kernel to initialize KWTA process, on Values data
+// (or Values4D for [KWTAInhib4D]).
+// i = op.Geom.Out.Y * X. IntArg1 x FilterN is inner 2 dims. Operates on Inhibs.
 // InValue = raw initial activations (ge)
 // OutValue = acts (output result)
 func KWTAInitPool(i uint32) { //gosl:kernel
@@ -109,13 +166,13 @@ func KWTAInitPool(i uint32) { //gosl:kernel
 	yo := ri / op.Geom.Out.X
 	xo := ri % op.Geom.Out.X
 
-	pn := 2 * op.FilterN
+	pn := op.IntArg1 * op.FilterN
 
 	geAvg := float32(0)
 	geMax := float32(0)
-	for py := range 2 { // for 4D, FilterSize.Y
+	for py := range op.IntArg1 {
 		for px := range op.FilterN {
-			ge := Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(py), int(px))
+			ge := op.KWTAGe(ni, yo, xo, py, px)
 			geAvg += ge
 			geMax = max(geMax, ge)
 		}
@@ -162,15 +219,18 @@ func KWTAIterLayerX(i uint32) { //gosl:kernel
 	geMax := float32(0)
 	actAvg := float32(0)
 	actMax := float32(0)
+	delMax := float32(0)
 	for xo := range szX {
 		gavg := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(GeAvg))
 		gmx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(GeMax))
 		aavg := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(ActAvg))
 		amx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(ActMax))
+		dmx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(DelActMax))
 		geAvg += gavg
 		geMax = max(geMax, gmx)
 		actAvg += aavg
 		actMax = max(actMax, amx)
+		delMax = max(delMax, dmx)
 	}
 	geAvg /= ln
 	actAvg /= ln
@@ -178,11 +238,14 @@ func KWTAIterLayerX(i uint32) { //gosl:kernel
 	Inhibs.Set(geMax, int(op.Inhibs), int(ni), int(yo), int(szX), int(GeMax))
 	Inhibs.Set(actAvg, int(op.Inhibs), int(ni), int(yo), int(szX), int(ActAvg))
 	Inhibs.Set(actMax, int(op.Inhibs), int(ni), int(yo), int(szX), int(ActMax))
+	Inhibs.Set(delMax, int(op.Inhibs), int(ni), int(yo), int(szX), int(DelActMax))
 }
 
 // KWTAIterLayerY is the kernel to iterate KWTA process at layer.
 // i = NData.
 // Operates on Inhibs updated from pool-level.
+// Also writes the max delta-activation from the previous IterPool
+// to OutScalar, for early stopping.
 // Call this first then IterPool
 func KWTAIterLayerY(i uint32) { //gosl:kernel
 	op := GetCurOp(0)
@@ -198,15 +261,18 @@ func KWTAIterLayerY(i uint32) { //gosl:kernel
 	geMax := float32(0)
 	actAvg := float32(0)
 	actMax := float32(0)
+	delMax := float32(0)
 	for yo := range szY {
 		gavg := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(szX), int(GeAvg))
 		gmx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(szX), int(GeMax))
 		aavg := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(szX), int(ActAvg))
 		amx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(szX), int(ActMax))
+		dmx := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(szX), int(DelActMax))
 		geAvg += gavg
 		geMax = max(geMax, gmx)
 		actAvg += aavg
 		actMax = max(actMax, amx)
+		delMax = max(delMax, dmx)
 	}
 	geAvg /= ln
 	actAvg /= ln
@@ -214,6 +280,8 @@ func KWTAIterLayerY(i uint32) { //gosl:kernel
 	Inhibs.Set(geMax, int(op.Inhibs), int(ni), int(szY), int(0), int(GeMax))
 	Inhibs.Set(actAvg, int(op.Inhibs), int(ni), int(szY), int(0), int(ActAvg))
 	Inhibs.Set(actMax, int(op.Inhibs), int(ni), int(szY), int(0), int(ActMax))
+	Inhibs.Set(delMax, int(op.Inhibs), int(ni), int(szY), int(0), int(DelActMax))
+	Scalars.Set(delMax, int(op.OutScalar), int(ni))
 
 	kp := GetKWTAs(uint32(op.KWTA))
 	fbi := Inhibs.Value(int(op.Inhibs), int(ni), int(szY), int(0), int(FBi))
@@ -234,10 +302,10 @@ func KWTAIterLayerY(i uint32) { //gosl:kernel
 }
 
 // KWTAIterPool is the kernel to iterate KWTA process for Pools.
-// i = op.Geom.Out.Y * X. FilterSize is inner 2 dims. Operates on Inhibs.
+// i = op.Geom.Out.Y * X. IntArg1 x FilterN is inner 2 dims. Operates on Inhibs.
 // InValue = raw initial activations (ge)
-// InValue2 = extra Gi values, if non-0
-// OutValue = acts (output result)
+// InValue2 = extra Gi values, if non-0 (not for [KWTAInhib4D])
+// OutValue = acts (output result), or OutValue4D for [KWTAInhib4D]
 func KWTAIterPool(i uint32) { //gosl:kernel
 	op := GetCurOp(0)
 	if i >= op.RunN*op.NData {
@@ -253,8 +321,7 @@ func KWTAIterPool(i uint32) { //gosl:kernel
 	layGi := Inhibs.Value(int(op.Inhibs), int(ni), int(szY), int(0), int(Gi))
 	kp := GetKWTAs(uint32(op.KWTA))
 
-	pn := 2 * op.FilterN
-	// pn := op.Geom.FilterSize.Y * op.Geom.FilterSize.X
+	pn := op.IntArg1 * op.FilterN
 
 	geAvg := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(GeAvg))
 	geMax := Inhibs.Value(int(op.Inhibs), int(ni), int(yo), int(xo), int(GeMax))
@@ -279,7 +346,8 @@ func KWTAIterPool(i uint32) { //gosl:kernel
 
 	actAvg = 0.0
 	actMax := float32(0.0)
-	for py := range 2 { // op.Geom.FilterSize.Y {
+	maxDelAct := float32(0.0)
+	for py := range op.IntArg1 {
 		for px := range op.FilterN {
 			pgi := giPool
 			if op.InValue2 > 0 {
@@ -288,20 +356,21 @@ func KWTAIterPool(i uint32) { //gosl:kernel
 				pgi = max(pgi, eGi)
 			}
 			geThr := kp.GeThrFromG(pgi)
-			ge := Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(py), int(px))
-			act := Values.Value(int(op.OutValue), int(ni), int(yo), int(xo), int(py), int(px))
+			ge := op.KWTAGe(ni, yo, xo, py, px)
+			act := op.KWTAAct(ni, yo, xo, py, px)
 			geAvg += ge
 			geMax = max(geMax, ge)
 			delAct := float32(0)
 			nwAct := kp.ActFromG(geThr, ge, act, &delAct)
-			// maxDelAct = max(maxDelAct, math32.Abs(delAct)) // todo
-			Values.Set(nwAct, int(op.OutValue), int(ni), int(yo), int(xo), int(py), int(px))
+			maxDelAct = max(maxDelAct, math32.Abs(delAct))
+			op.SetKWTAAct(nwAct, ni, yo, xo, py, px)
 			actAvg += nwAct
 			actMax = max(actMax, nwAct)
 		}
 	}
 	Inhibs.Set(actAvg/float32(pn), int(op.Inhibs), int(ni), int(yo), int(xo), int(ActAvg))
 	Inhibs.Set(actMax, int(op.Inhibs), int(ni), int(yo), int(xo), int(ActMax))
+	Inhibs.Set(maxDelAct, int(op.Inhibs), int(ni), int(yo), int(xo), int(DelActMax))
 }
 
 // Neigh4X = []int{0, -1, 1, -1}
@@ -25,6 +25,17 @@ func (vv *V1Vision) NewMaxPool(in, pn, fn int, geom *Geom) int {
 	return out
 }
 
+// NewMaxPoolArgMax adds a [MaxPool] operation as in [V1Vision.NewMaxPool],
+// that also records the index of the max value within each pool
+// (py * FilterSize.X + px) into a second values output, for use in
+// [V1Vision.NewUnPool]. Returns the indexes of the output and argmax values.
+func (vv *V1Vision) NewMaxPoolArgMax(in, pn, fn int, geom *Geom) (out, argmax int) {
+	out = vv.NewMaxPool(in, pn, fn, geom)
+	argmax = vv.NewValues(int(geom.Out.Y), int(geom.Out.X), fn)
+	vv.Ops[len(vv.Ops)-1].IntArg2 = 1
+	return
+}
+
 // NewMaxPolarity adds a [MaxPolarity] operation, from in value -> out values.
 // fn is number of filters (innermost values dimension).
 // geom.Out is the size of both input and output,
@@ -75,15 +86,20 @@ func (op *Op) MaxPool(i, ni int32) {
 	ix := xo * op.Geom.Spacing.X
 
 	mx := float32(0)
+	mi := int32(0)
 	for py := range fY {
 		for px := range fX {
 			iv := Values.Value(int(op.InValue), int(ni), int(iy+py), int(ix+px), int(pi), int(fi))
 			if iv > mx {
 				mx = iv
+				mi = py*fX + px
 			}
 		}
 	}
 	Values.Set(mx, int(op.OutValue), int(ni), int(yo), int(xo), int(pi), int(fi))
+	if op.IntArg2 > 0 {
+		Values.Set(float32(mi), int(op.OutValue+1), int(ni), int(yo), int(xo), int(pi), int(fi))
+	}
 }
 
 // MaxPolarity is kernel.
@@ -6,6 +6,10 @@
 
 package v1vision
 
+import (
+	"cogentcore.org/core/math32"
+)
+
 // NewMotionIntegrate adds a [MotionIntegrate] operation,
 // operating on given values input index, with given number of filters.
 // fastTau and slowTau are the tau time constants for integrating.
@@ -66,6 +70,83 @@ func (vv *V1Vision) NewMotionFullField(in, fn int, geom *Geom) int {
 	return out
 }
 
+// NewMotionFlow adds a [MotionFlow] operation,
+// operating on given values input index = star output,
+// with given number of original input filters (same as arg for Star).
+// The geom is for the pooling over the star output, with In = star size
+// (i.e., Out - 1 of the geom used for Star), and FilterSize and Spacing
+// specifying the pooling regions. The gain multiplies the average
+// opponent difference within each pool.
+// Adds new Values for output, with dx, dy in feature dimension of
+// polarity 0, index returned.
+func (vv *V1Vision) NewMotionFlow(in, fn int, gain float32, geom *Geom) int {
+	out := vv.NewValues(int(geom.Out.Y), int(geom.Out.X), 2)
+	op := vv.NewOp()
+	op.Op = MotionFlow
+	op.RunN = uint32(geom.Out.Y * geom.Out.X)
+	op.InValue = int32(in)
+	op.OutValue = int32(out)
+	op.FilterN = int32(fn)
+	op.FloatArg1 = gain
+	op.Geom = *geom
+	return out
+}
+
+// NewMotionOpticFlow adds a [MotionOpticFlow] operation,
+// operating on given values input index = star output.
+// with given number of original input filters (same as arg for Star).
+// focusX, focusY are the center of the expansion and rotation templates,
+// as a proportion of the size of the star output (0.5 = center).
+// Adds 4 new Scalar outputs for instantaneous optic flow output, in order:
+// Expand, Contract, Clockwise, CounterClockwise.
+// Allocates an intermediate OutValue for 2-phase integration process.
+// starting Scalar index returned.
+func (vv *V1Vision) NewMotionOpticFlow(in, fn int, focusX, focusY float32, geom *Geom) int {
+	out := vv.NewScalar(4)
+	op := vv.NewOp()
+	op.Op = MotionOpticFlow
+	oy := int(geom.Out.Y - 1)
+	op.RunN = uint32(oy) // first pass N
+	op.InValue = int32(in)
+	op.OutValue = int32(vv.NewValues(oy, 1, 4))
+	op.OutScalar = int32(out)
+	op.FilterN = int32(fn)
+	op.FloatArg1 = focusX
+	op.FloatArg2 = focusY
+	op.Geom = *geom
+	return out
+}
+
+// NewMotionGrid adds a [MotionGrid] operation,
+// operating on given values input index = star output.
+// with given number of original input filters (same as arg for Star).
+// gridY, gridX are the number of regions to pool over.
+// norm is the Scalars index of the sum of input activity used for
+// normalizing (e.g., from [SumScalar] on the DoG output), which is
+// integrated over frames as in [motion.Params.FullFieldInteg], and gain
+// is an additional multiplier. integTau is the time constant for
+// integrating over frames.
+// Adds a new Values for the integrated norm state, and a new Values4D
+// for output: [GridY][GridX][2][2], index returned.
+// Sets geom.FilterSize to the grid size.
+func (vv *V1Vision) NewMotionGrid(in, fn, gridY, gridX, norm int, gain, integTau float32, geom *Geom) int {
+	out := vv.NewValues4D(gridY, gridX, 2, 2)
+	op := vv.NewOp()
+	op.Op = MotionGrid
+	op.RunN = uint32(gridY * gridX)
+	op.InValue = int32(in)
+	op.InScalar = int32(norm)
+	op.OutValue = int32(vv.NewValues(gridY, gridX, 1))
+	op.OutValue4D = int32(out)
+	op.FilterN = int32(fn)
+	op.FloatArg1 = gain
+	op.FloatArg2 = 1.0 / integTau
+	op.Geom = *geom
+	op.Geom.FilterSize.X = int32(gridX)
+	op.Geom.FilterSize.Y = int32(gridY)
+	return out
+}
+
 //gosl:start
 
 // MotionIntegrate is the kernel.
@@ -134,6 +215,109 @@ func (op *Op) MotionStar(i, ni int32) {
 	}
 }
 
+// MotionFlow is the kernel.
+func (op *Op) MotionFlow(i, ni int32) {
+	yo := i / op.Geom.Out.X
+	xo := i % op.Geom.Out.X
+	sy := yo * op.Geom.Spacing.Y
+	sx := xo * op.Geom.Spacing.X
+
+	dx := float32(0)
+	dy := float32(0)
+	for py := range op.Geom.FilterSize.Y {
+		y := sy + py
+		if y >= op.Geom.In.Y {
+			continue
+		}
+		for px := range op.Geom.FilterSize.X {
+			x := sx + px
+			if x >= op.Geom.In.X {
+				continue
+			}
+			for pi := range 2 { // pos / neg
+				for fi := range op.FilterN { // original features
+					dfo := fi * 4
+					l := Values.Value(int(op.InValue), int(ni), int(y), int(x), int(pi), int(dfo))
+					r := Values.Value(int(op.InValue), int(ni), int(y), int(x), int(pi), int(dfo+1))
+					d := Values.Value(int(op.InValue), int(ni), int(y), int(x), int(pi), int(dfo+2))
+					u := Values.Value(int(op.InValue), int(ni), int(y), int(x), int(pi), int(dfo+3))
+					dx += r - l
+					dy += u - d
+				}
+			}
+		}
+	}
+	norm := op.FloatArg1 / float32(op.Geom.FilterSize.Y*op.Geom.FilterSize.X)
+	Values.Set(norm*dx, int(op.OutValue), int(ni), int(yo), int(xo), int(0), int(0))
+	Values.Set(norm*dy, int(op.OutValue), int(ni), int(yo), int(xo), int(0), int(1))
+	Values.Set(0.0, int(op.OutValue), int(ni), int(yo), int(xo), int(1), int(0))
+	Values.Set(0.0, int(op.OutValue), int(ni), int(yo), int(xo), int(1), int(1))
+}
+
+// MotionGrid is the kernel.
+func (op *Op) MotionGrid(i, ni int32) {
+	gY := op.Geom.FilterSize.Y
+	gX := op.Geom.FilterSize.X
+	szY := op.Geom.Out.Y - 1
+	szX := op.Geom.Out.X - 1
+	gy := i / gX
+	gx := i % gX
+	sy := (gy * szY) / gY
+	ey := ((gy + 1) * szY) / gY
+	sx := (gx * szX) / gX
+	ex := ((gx + 1) * szX) / gX
+
+	// integrated norm, same for all regions, as in FullFieldInteg
+	visNorm := Scalars.Value(int(op.InScalar), int(ni))
+	norm := Values.Value(int(op.OutValue), int(ni), int(gy), int(gx), int(0), int(0))
+	if norm == 0 {
+		norm = visNorm
+	} else {
+		norm += op.FloatArg2 * (visNorm - norm)
+	}
+	Values.Set(norm, int(op.OutValue), int(ni), int(gy), int(gx), int(0), int(0))
+	vnf := op.FloatArg1 * float32(gY*gX)
+	if norm > 0 {
+		vnf /= norm
+	}
+
+	for dir := range int32(2) { // left-right, down-up
+		doff := dir * 2
+		csum := float32(0)
+		nsum := float32(0)
+		for yo := sy; yo < ey; yo++ {
+			for xo := sx; xo < ex; xo++ {
+				for pi := range 2 { // pos / neg
+					for fi := range op.FilterN { // original features
+						dfo := fi*4 + doff
+						c := Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo))   // left, down
+						n := Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo+1)) // right, up
+						v := c - n
+						if v >= 0 {
+							csum += v
+						} else {
+							nsum += -v
+						}
+					}
+				}
+			}
+		}
+		if csum > nsum {
+			csum = vnf * (csum - nsum)
+			nsum = 0
+		} else {
+			nsum = vnf * (nsum - csum)
+			csum = 0
+		}
+		cint := Values4D.Value(int(op.OutValue4D), int(ni), int(gy), int(gx), int(dir), int(0))
+		nint := Values4D.Value(int(op.OutValue4D), int(ni), int(gy), int(gx), int(dir), int(1))
+		cint += op.FloatArg2 * (csum - cint)
+		nint += op.FloatArg2 * (nsum - nint)
+		Values4D.Set(cint, int(op.OutValue4D), int(ni), int(gy), int(gx), int(dir), int(0))
+		Values4D.Set(nint, int(op.OutValue4D), int(ni), int(gy), int(gx), int(dir), int(1))
+	}
+}
+
 // MotionFullFieldX is the kernel: i = 2 * Y, first pass, FilterN = orig filtn
 func MotionFullFieldX(i uint32) { //gosl:kernel
 	op := GetCurOp(0)
@@ -192,4 +376,76 @@ func MotionFullFieldY(i uint32) { //gosl:kernel
 	Scalars.Set(nsum, int(op.OutScalar+doff+1), int(ni))
 }
 
+// MotionOpticFlowX is the kernel: i = Y, first pass, FilterN = orig filtn
+func MotionOpticFlowX(i uint32) { //gosl:kernel
+	op := GetCurOp(0)
+	if i >= op.RunN*op.NData {
+		return
+	}
+	yo := int32(i % op.RunN)
+	ni := int32(i / op.RunN)
+	szX := op.Geom.Out.X - 1
+	szY := op.Geom.Out.Y - 1
+	fno := op.FilterN // original features
+	fx := op.FloatArg1 * float32(szX-1)
+	fy := op.FloatArg2 * float32(szY-1)
+	ry := float32(yo) - fy
+
+	expand := float32(0)
+	contract := float32(0)
+	cw := float32(0)
+	ccw := float32(0)
+	for xo := range szX {
+		rx := float32(xo) - fx
+		rd := math32.Sqrt(rx*rx + ry*ry)
+		if rd < 0.5 { // no direction at focus
+			continue
+		}
+		rx /= rd
+		rdy := ry / rd
+		dx := float32(0)
+		dy := float32(0)
+		for pi := range 2 { // pos / neg
+			for fi := range fno { // original features
+				dfo := fi * 4
+				dx += Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo+1)) - Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo))
+				dy += Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo+3)) - Values.Value(int(op.InValue), int(ni), int(yo), int(xo), int(pi), int(dfo+2))
+			}
+		}
+		rad := dx*rx + dy*rdy // radial: + = outward
+		tan := dy*rx - dx*rdy // tangential: + = counterclockwise
+		if rad >= 0 {
+			expand += rad
+		} else {
+			contract += -rad
+		}
+		if tan >= 0 {
+			ccw += tan
+		} else {
+			cw += -tan
+		}
+	}
+	Values.Set(expand, int(op.OutValue), int(ni), int(yo), int(0), int(0), int(0))
+	Values.Set(contract, int(op.OutValue), int(ni), int(yo), int(0), int(0), int(1))
+	Values.Set(cw, int(op.OutValue), int(ni), int(yo), int(0), int(0), int(2))
+	Values.Set(ccw, int(op.OutValue), int(ni), int(yo), int(0), int(0), int(3))
+}
+
+// MotionOpticFlowY is the kernel: i = NData, second pass
+func MotionOpticFlowY(i uint32) { //gosl:kernel
+	op := GetCurOp(0)
+	if i >= op.NData {
+		return
+	}
+	ni := int32(i)
+	szY := op.Geom.Out.Y - 1
+	for fi := range int32(4) {
+		sum := float32(0)
+		for y := range szY {
+			sum += Values.Value(int(op.OutValue), int(ni), int(y), int(0), int(0), int(fi))
+		}
+		Scalars.Set(sum, int(op.OutScalar+fi), int(ni))
+	}
+}
+
 //gosl:end
@@ -0,0 +1,95 @@
+// Copyright (c) 2025, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1vision
+
+import (
+	"fmt"
+
+	"cogentcore.org/lab/tensor"
+	"github.com/emer/v1vision/npy"
+)
+
+// Values4DAt returns the Values4D subspace at given index as a
+// [NData][PoolY][PoolX][UnitY][UnitX] tensor. Values4D are allocated
+// to the maximum size of all subspaces, so if sizes are given
+// (PoolY, PoolX, UnitY, UnitX), the result is a copy with exactly
+// those sizes, which is usually what should be saved.
+func (vv *V1Vision) Values4DAt(idx int, sizes ...int) *tensor.Float32 {
+	return exactSizes(vv.Values4D.SubSpace(idx).(*tensor.Float32), sizes, true)
+}
+
+// FilterAt returns the Filters subspace at given index, as a
+// [FilterN][Y][X] tensor. If sizes are given (FilterN, Y, X), the
+// result is a copy with exactly those sizes (see [V1Vision.Values4DAt]).
+func (vv *V1Vision) FilterAt(idx int, sizes ...int) *tensor.Float32 {
+	return exactSizes(vv.Filters.SubSpace(idx).(*tensor.Float32), sizes, false)
+}
+
+// exactSizes returns a copy of given tensor with given inner sizes,
+// or the tensor itself if sizes is empty. If ndata, the outer
+// NData dimension is kept.
+func exactSizes(tsr *tensor.Float32, sizes []int, ndata bool) *tensor.Float32 {
+	if len(sizes) == 0 {
+		return tsr
+	}
+	if ndata {
+		sizes = append([]int{tsr.DimSize(0)}, sizes...)
+	}
+	out := tensor.NewFloat32(sizes...)
+	tensor.CopyFromLargerShape(out, tsr)
+	return out
+}
+
+// SaveValues4D saves the Values4D subspace at given index to a NumPy
+// .npy file, with given exact sizes if specified.
+// See [V1Vision.Values4DAt].
+func (vv *V1Vision) SaveValues4D(filename string, idx int, sizes ...int) error {
+	return npy.Save(filename, vv.Values4DAt(idx, sizes...))
+}
+
+// SaveFilter saves the Filters subspace at given index to a NumPy
+// .npy file, with given exact sizes if specified.
+// See [V1Vision.FilterAt].
+func (vv *V1Vision) SaveFilter(filename string, idx int, sizes ...int) error {
+	return npy.Save(filename, vv.FilterAt(idx, sizes...))
+}
+
+// SetValues4D sets the Values4D subspace at given index from the
+// given tensor, which can be smaller on each dimension,
+// e.g., as saved with exact sizes. Any remaining values are set to 0.
+func (vv *V1Vision) SetValues4D(idx int, tsr tensor.Tensor) error {
+	return setSubSpace(vv.Values4D.SubSpace(idx).(*tensor.Float32), tsr)
+}
+
+// SetFilter sets the Filters subspace at given index from the given
+// tensor, which can be smaller on each dimension, e.g., a [FilterN][Y][X]
+// filter set computed in Python and read with [npy.Open].
+// Any remaining values are set to 0. The filter must have been
+// allocated with NewFilter at a sufficient size, and must be copied
+// to the GPU (ToGPU(FiltersVar)) after GPUInit, if running there.
+func (vv *V1Vision) SetFilter(idx int, tsr tensor.Tensor) error {
+	return setSubSpace(vv.Filters.SubSpace(idx).(*tensor.Float32), tsr)
+}
+
+// setSubSpace sets the given subspace from the given tensor of
+// the same or smaller shape, zeroing the remaining values.
+func setSubSpace(sub *tensor.Float32, tsr tensor.Tensor) error {
+	ss := sub.ShapeSizes()
+	ts := tsr.ShapeSizes()
+	if len(ss) != len(ts) {
+		return fmt.Errorf("v1vision: shape %v is not compatible with %v", ts, ss)
+	}
+	for i := range ss {
+		if ts[i] > ss[i] {
+			return fmt.Errorf("v1vision: shape %v is larger than %v", ts, ss)
+		}
+	}
+	clear(sub.Values)
+	n := tsr.Len()
+	for i := range n {
+		sub.SetFloat(tsr.Float1D(i), tsr.Shape().IndexFrom1D(i)...)
+	}
+	return nil
+}
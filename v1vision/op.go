@@ -84,9 +84,16 @@ const (
 	// based on overall levels of activity, over multiple iterations.
 	KWTAInhib
 
+	// KWTAInhib4D computes k-winners-take-all inhibition, rate-code version,
+	// on Values4D data, where each pool is the [UnitY][UnitX] values at each
+	// [PoolY][PoolX] location: InValue -> OutValue4D (both Values4D).
+	KWTAInhib4D
+
 	// MaxPool performs max-pooling over given pool size and spacing,
 	// effectively reducing the dimensionality of the output by the
 	// spacing factor. Size must = spacing or 2 * spacing.
+	// If IntArg2 > 0, the index of the max value within each pool
+	// is recorded in OutValue+1, for use in UnPool.
 	MaxPool
 
 	// MaxPolarity performs max-pooling over the polarity (on vs. off)
@@ -128,6 +135,86 @@ const (
 	// OutScalar[0-3] = instantaneous full-field values per this frame
 	// OutScalar[4-7] = integrated full-field values over time
 	MotionFullField
+
+	// MotionFlow computes a local flow field from the output of
+	// MotionStar, pooled over regions per Geom FilterSize, Spacing.
+	// Result is signed dx, dy in OutValue [Y][X][0][0-1], with positive
+	// values for Right and Up motion.
+	MotionFlow
+
+	// MotionOpticFlow computes full-field optic flow summary of output
+	// from MotionStar, by projecting the local motion at each point onto
+	// radial and tangential templates around a focus point
+	// (FloatArg1, FloatArg2 = X, Y as proportion of size), into 4 Scalars
+	// for Expand, Contract, Clockwise, CounterClockwise.
+	MotionOpticFlow
+
+	// MotionGrid computes regional full-field summaries of output from
+	// MotionStar, over a grid of regions (Geom.FilterSize), with opponent
+	// competition and normalization by the InScalar sum of input activity.
+	// The normalization state is integrated over time in OutValue, and
+	// the opponent grid outputs are integrated over time into OutValue4D
+	// [GridY][GridX][2][2] for [Left,Right][Down,Up], as for full-field.
+	MotionGrid
+
+	// TemporalFilter applies a stateful temporal filter kernel
+	// ([TemporalKernels] in IntArg1) to values across successive runs:
+	// InValue -> OutValue, with OutValue+1 (and +2) holding filter state.
+	TemporalFilter
+
+	// ResetValues sets InValue to zero, for NData item in IntArg1
+	// (all if < 0). Used for resetting state outside of the Ops sequence.
+	ResetValues
+
+	// ResetValues4D sets OutValue4D to zero, for NData item in IntArg1
+	// (all if < 0). Used for resetting state outside of the Ops sequence.
+	ResetValues4D
+
+	// BinocularEnergy computes binocular disparity energy-model responses
+	// from left (InImage) and right (InImage2) images, using gabor
+	// quadrature-pair filters, over a range of position and phase
+	// disparities, writing to OutValue4D [Y][X][disparity][angle].
+	BinocularEnergy
+
+	// PoissonSpikes generates Poisson spikes from rate-code activations
+	// in InValue (e.g., output of [KWTAInhib]), with probability per cycle
+	// of FloatArg1 * activation, over IntArg1 cycles, using IntArg2 as the
+	// random seed. Spike counts go to OutValue. Per-cycle spike trains
+	// can be computed on the CPU with [V1Vision.PoissonSpikeTrains].
+	PoissonSpikes
+
+	// TopKPool does exact top-k selection within each pool
+	// ([Polarity][FilterN] at each location), setting all but the
+	// IntArg1 largest values to 0. InValue -> OutValue.
+	TopKPool
+
+	// TopKLayer does exact top-k selection over the entire layer,
+	// setting all but the IntArg1 largest values to 0, except that
+	// values tied with the k-th largest are also kept. This is done by
+	// a bitwise search for the k-th largest value, counting values at
+	// or above each candidate threshold into InValue2 [Y] and
+	// OutScalar+0..2, followed by one thresholding pass.
+	// InValue -> OutValue.
+	TopKLayer
+
+	// UnPool performs inverse max-pooling of InValue -> OutValue,
+	// placing each pooled value at the location of its max within the
+	// pool, per the argmax values in InValue2 recorded by MaxPool,
+	// or at all locations in the pool if InValue2 < 0.
+	// Geom is the same as used for MaxPool, with OutValue at Geom.In size.
+	UnPool
+
+	// DeconvImage performs reverse convolution of InValue values,
+	// as the output of ConvolveImage with the same filters and Geom,
+	// accumulating the sum of filter * activation (on - off polarity)
+	// into OutImage at OutImage2 color component.
+	DeconvImage
+
+	// UnpackImage converts packed uint32 pixels in RawImages, in
+	// [RawFormats] IntArg2, into OutImage, with IntArg1 padding and
+	// flipping Y unless IntArg3 (TopZero) is set. Writes all RGB
+	// components if InImageRGB = 3, else greyscale to that component.
+	UnpackImage
 )
 
 // Op specifies an operation to perform.
@@ -203,7 +290,15 @@ type Op struct {
 	// KWTA is the index of the KWTA parameters to use.
 	KWTA int32
 
-	pad, pad1, pad2 int32
+	// InImage2 is the index of a second image to process as an input,
+	// where needed (e.g., right eye image for [BinocularEnergy]).
+	InImage2 int32
+
+	// IntArg2 is an arbitrary integer arg, used for different ops.
+	IntArg2 int32
+
+	// IntArg3 is an arbitrary integer arg, used for different ops.
+	IntArg3 int32
 
 	// Geom is the geometry to use for this operation.
 	Geom Geom
@@ -247,6 +342,30 @@ func (op *Op) Run(ri, ni int32) {
 		op.MotionIntegrate(ri, ni)
 	case MotionStar:
 		op.MotionStar(ri, ni)
+	case MotionFlow:
+		op.MotionFlow(ri, ni)
+	case MotionGrid:
+		op.MotionGrid(ri, ni)
+	case TemporalFilter:
+		op.TemporalFilter(ri, ni)
+	case ResetValues:
+		op.ResetValues(ri, ni)
+	case ResetValues4D:
+		op.ResetValues4D(ri, ni)
+	case BinocularEnergy:
+		op.BinocularEnergy(ri, ni)
+	case PoissonSpikes:
+		op.PoissonSpikes(ri, ni)
+	case TopKPool:
+		op.TopKPool(ri, ni)
+	case TopKLayer:
+		op.TopKLayer(ri, ni)
+	case UnPool:
+		op.UnPool(ri, ni)
+	case DeconvImage:
+		op.DeconvImage(ri, ni)
+	case UnpackImage:
+		op.UnpackImage(ri, ni)
 	default:
 	}
 }
@@ -266,6 +385,7 @@ func DoCurOp(i uint32) { //gosl:kernel
 // RunOps runs all the operations.
 func (vv *V1Vision) RunOps() {
 	nops := len(vv.Ops)
+	vv.KWTAIters = vv.KWTAIters[:0]
 	for i := range nops {
 		vv.CurOp[0] = vv.Ops[i]
 		ToGPU(CurOpVar)
@@ -282,18 +402,37 @@ func (vv *V1Vision) RunOps() {
 		case MeanScalar:
 			RunSumScalarX(int(op.RunN) * vv.NData)
 			RunMeanScalarY(vv.NData)
-		case KWTAInhib:
+		case KWTAInhib, KWTAInhib4D:
 			kp := &vv.KWTAs[op.KWTA]
 			RunKWTAInitLayer(vv.NData)
 			RunKWTAInitPool(int(op.RunN) * vv.NData)
-			for range kp.Iters {
+			iters := 0
+			for it := range kp.Iters {
 				RunKWTAIterLayerX(int(op.Geom.Out.Y) * vv.NData)
 				RunKWTAIterLayerY(vv.NData)
+				if it > 0 && kp.EarlyStop.IsTrue() {
+					RunDone(ScalarsVar)
+					if vv.kwtaConverged(op, kp) {
+						break
+					}
+				}
 				RunKWTAIterPool(int(op.RunN) * vv.NData)
+				iters++
+			}
+			vv.KWTAIters = append(vv.KWTAIters, iters)
+		case TopKLayer:
+			RunTopKLayerInit(vv.NData)
+			for range 32 {
+				RunTopKLayerCountX(int(op.Geom.Out.Y) * vv.NData)
+				RunTopKLayerCountY(vv.NData)
 			}
+			RunDoCurOp(int(op.RunN) * vv.NData)
 		case MotionFullField:
 			RunMotionFullFieldX(int(op.RunN) * vv.NData)
 			RunMotionFullFieldY(2 * vv.NData)
+		case MotionOpticFlow:
+			RunMotionOpticFlowX(int(op.RunN) * vv.NData)
+			RunMotionOpticFlowY(vv.NData)
 		default:
 			RunDoCurOp(int(op.RunN) * vv.NData)
 		}
@@ -302,3 +441,15 @@ func (vv *V1Vision) RunOps() {
 		}
 	}
 }
+
+// kwtaConverged returns true if the max delta-activation for the
+// [KWTAInhib] op, in its OutScalar, is below the DelActThr threshold
+// for all data-parallel items.
+func (vv *V1Vision) kwtaConverged(op *Op, kp *KWTA) bool {
+	for ni := range vv.NData {
+		if vv.Scalars.Value(int(op.OutScalar), ni) >= kp.DelActThr {
+			return false
+		}
+	}
+	return true
+}
@@ -13,10 +13,12 @@ var<storage, read> Filters: array<f32>;
 @group(2) @binding(0)
 var<storage, read_write> Images: array<f32>;
 @group(2) @binding(1)
-var<storage, read_write> Values: array<f32>;
+var<storage, read> RawImages: array<u32>;
 @group(2) @binding(2)
-var<storage, read_write> Values4D: array<f32>;
+var<storage, read_write> Values: array<f32>;
 @group(2) @binding(3)
+var<storage, read_write> Values4D: array<f32>;
+@group(2) @binding(4)
 var<storage, read_write> Scalars: array<f32>;
 
 alias GPUVars = i32;
@@ -35,6 +37,10 @@ fn Index5D(s0: u32, s1: u32, s2: u32, s3: u32, s4: u32, i0: u32, i1: u32, i2: u3
 	return s0 * i0 + s1 * i1 + s2 * i2 + s3 * i3 + s4 * i4;
 }
 
+fn Index3D(s0: u32, s1: u32, s2: u32, i0: u32, i1: u32, i2: u32) -> u32 {
+	return s0 * i0 + s1 * i1 + s2 * i2;
+}
+
 fn Index6D(s0: u32, s1: u32, s2: u32, s3: u32, s4: u32, s5: u32, i0: u32, i1: u32, i2: u32, i3: u32, i4: u32, i5: u32) -> u32 {
 	return s0 * i0 + s1 * i1 + s2 * i2 + s3 * i3 + s4 * i4 + s5 * i5;
 }
@@ -124,22 +130,22 @@ fn Op_LenSum4(op: Op, i: i32,ni: i32) {
 	var oy: i32;
 	LenSumOffsets(ang, &ox, &oy);
 	var norm = f32(1) / 3;
-	var ctr = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(0), u32(ang))];
+	var ctr = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(0), u32(ang))];
 	var lp = f32(0);
 	var ln = f32(0);
 	var lpX = xo + ox;
 	var lpY = yo + oy;
 	if (lpX >= 0 && lpX < szX && lpY >= 0 && lpY < szY) {
-		lp = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(lpY), u32(lpX), u32(0), u32(ang))];
+		lp = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(lpY), u32(lpX), u32(0), u32(ang))];
 	}
 	var lnX = xo - ox;
 	var lnY = yo - oy;
 	if (lnX >= 0 && lnX < szX && lnY >= 0 && lnY < szY) {
-		ln = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(lnY), u32(lnX), u32(0), u32(ang))];
+		ln = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(lnY), u32(lnX), u32(0), u32(ang))];
 	}
 	var ls = norm * (ctr + lp + ln);
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(ang))] = ls;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(ang))] = ls;
 }
 fn Op_EndStop4(op: Op, i: i32,ni: i32) {
 	var szX = op.Geom.Out.x;
@@ -161,7 +167,7 @@ fn Op_EndStop4(op: Op, i: i32,ni: i32) {
 	var lnX = xo - dsign*ox;
 	var lnY = yo - dsign*oy;
 	if (lnX >= 0 && lnX < szX && lnY >= 0 && lnY < szY) {
-		ls = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue2), u32(ni), u32(lnY), u32(lnX), u32(0), u32(ang))];
+		ls = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue2), u32(ni), u32(lnY), u32(lnX), u32(0), u32(ang))];
 	}
 	var offMax = f32(0);
 	for (var oi = i32(0);
@@ -172,7 +178,7 @@ fn Op_EndStop4(op: Op, i: i32,ni: i32) {
 		var ofX = xo + dsign*ox;
 		var ofY = yo + dsign*oy;
 		if (ofX >= 0 && ofX < szX && ofY >= 0 && ofY < szY) {
-			var off = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(ofY), u32(ofX), u32(0), u32(ang))];
+			var off = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(ofY), u32(ofX), u32(0), u32(ang))];
 			offMax = max(offMax, off);
 		}
 	}
@@ -180,8 +186,8 @@ fn Op_EndStop4(op: Op, i: i32,ni: i32) {
 	if (es < 0.2) {       // note: builtin threshold
 		es = f32(0);
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(ang))] = es;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(ang))] = es;
 }
 fn LenSumOffsets(ang: i32, ox: ptr<function,i32>,oy: ptr<function,i32>) {
 	switch (ang) {
@@ -243,21 +249,25 @@ fn Op_ConvolveImage(op: Op, i: i32,ni: i32) {
 	var fyn = i32(op.Geom.FilterSize.y);
 	var fxn = i32(op.Geom.FilterSize.x);
 	var sum = f32(0);
-	for (var fy=0; fy<fyn; fy++) {
-		for (var fx=0; fx<fxn; fx++) {
-			var iv = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xi + fx))];
-			var fv = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(fi), u32(fy), u32(fx))];
-			sum += fv * iv;
+	if (op.InImage2 >= 0) {
+		sum = Op_ConvolveMasked(op, op.InImage, op.InImageRGB, fi, yi, xi, ni);
+	} else {
+		for (var fy=0; fy<fyn; fy++) {
+			for (var fx=0; fx<fxn; fx++) {
+				var iv = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xi + fx))];
+				var fv = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(fi), u32(fy), u32(fx))];
+				sum += fv * iv;
+			}
 		}
 	}
 	sum *= op.FloatArg1;
 	if (sum > 0) {
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = sum;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = sum;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = 0.0;
 	} else {
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = 0.0;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-		TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = -sum;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+		TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = -sum;
 	}
 }
 fn Op_ConvolveDiff(op: Op, i: i32,ni: i32) {
@@ -272,30 +282,97 @@ fn Op_ConvolveDiff(op: Op, i: i32,ni: i32) {
 	var fxn = i32(op.Geom.FilterSize.x);
 	var sumOn = f32(0);
 	var sumOff = f32(0);
-	for (var fy=0; fy<fyn; fy++) {
-		for (var fx=0; fx<fxn; fx++) {
-			var iv1 = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xi + fx))];
-			var iv2 = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InValue2), u32(ni), u32(op.OutImage2), u32(yi + fy), u32(xi + fx))];
-			var fv1 = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(op.FilterN), u32(fy), u32(fx))];
-			var fv2 = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(op.IntArg1), u32(fy), u32(fx))];
-			sumOn += fv1 * iv1;
-			sumOff += fv2 * iv2;
+	if (op.InImage2 >= 0) {
+		sumOn = Op_ConvolveMasked(op, op.InImage, op.InImageRGB, op.FilterN, yi, xi, ni);
+		sumOff = Op_ConvolveMasked(op, op.InValue2, op.OutImage2, op.IntArg1, yi, xi, ni);
+	} else {
+		for (var fy=0; fy<fyn; fy++) {
+			for (var fx=0; fx<fxn; fx++) {
+				var iv1 = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xi + fx))];
+				var iv2 = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InValue2), u32(ni), u32(op.OutImage2), u32(yi + fy), u32(xi + fx))];
+				var fv1 = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(op.FilterN), u32(fy), u32(fx))];
+				var fv2 = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(op.IntArg1), u32(fy), u32(fx))];
+				sumOn += fv1 * iv1;
+				sumOff += fv2 * iv2;
+			}
 		}
 	}
 	var diff = op.FloatArg1 * (op.FloatArg2*sumOn - sumOff);
 	if (diff > 0) {
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = diff;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = diff;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = 0.0;
 	} else {
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = -diff;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))] = -diff;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+		TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = 0.0;
+	}
+}
+fn Op_ConvolveMasked(op: Op, img: i32,rgb: i32,fi: i32, yi: i32,xi: i32, ni: i32) -> f32 {
+	var cy = yi + i32(op.Geom.FilterLt.y);
+	var cx = xi + i32(op.Geom.FilterLt.x);
+	if (Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage2), u32(ni), u32(0), u32(cy), u32(cx))] < 0.5) {
+		return f32(0.0);
+	}
+	var fyn = i32(op.Geom.FilterSize.y);
+	var fxn = i32(op.Geom.FilterSize.x);
+	var sum = f32(0);
+	var isum = f32(0); // sum of filter over invalid
+	var vsum = f32(0); // sum of valid image values
+	var nv = 0;
+	for (var fy=0; fy<fyn; fy++) {
+		for (var fx=0; fx<fxn; fx++) {
+			var fv = Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(fi), u32(fy), u32(fx))];
+			if (Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage2), u32(ni), u32(0), u32(yi + fy), u32(xi + fx))] < 0.5) {
+				isum += fv;
+				continue;
+			}
+			var iv = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(img), u32(ni), u32(rgb), u32(yi + fy), u32(xi + fx))];
+			sum += fv * iv;
+			vsum += iv;
+			nv++;
+		}
+	}return sum + isum*vsum/f32(nv);
+}
+
+//////// import: "deconv.go"
+fn Op_DeconvImage(op: Op, i: i32,ni: i32) {
+	var yi = i / op.Geom.In.x;
+	var xi = i % op.Geom.In.x;
+	var fyn = op.Geom.FilterSize.y;
+	var fxn = op.Geom.FilterSize.x;
+	var sY = op.Geom.Spacing.y;
+	var sX = op.Geom.Spacing.x;
+	var ry = yi - (op.Geom.Border.y - op.Geom.FilterLt.y);
+	var rx = xi - (op.Geom.Border.x - op.Geom.FilterLt.x);
+	var sum = f32(0);
+	if (ry < 0 || rx < 0) {
+		Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(op.OutImage2), u32(yi), u32(xi))] = sum;return;
+	}
+	var sy = (max(ry-fyn+1, 0) + sY - 1) / sY;
+	var ey = min(ry/sY+1, op.Geom.Out.y);
+	var sx = (max(rx-fxn+1, 0) + sX - 1) / sX;
+	var ex = min(rx/sX+1, op.Geom.Out.x);
+	for (var yo = sy;
+	 yo < ey; yo++) {
+		var fy = ry - yo*sY;
+		for (var xo = sx;
+		 xo < ex; xo++) {
+			var fx = rx - xo*sX;
+			for (var fi=0; fi<op.FilterN; fi++) {
+				var act = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] - Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(1), u32(fi))];
+				sum += act * Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(fi), u32(fy), u32(fx))];
+			}
+		}
 	}
+	Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(op.OutImage2), u32(yi), u32(xi))] = op.FloatArg1 * sum;
 }
 
 //////// import: "enumgen.go"
-const GPUVarsN: GPUVars = 8;
-const InhibVarsN: InhibVars = 9;
-const OperationsN: Operations = 24;
+const GPUVarsN: GPUVars = 9;
+const InhibVarsN: InhibVars = 10;
+const OperationsN: Operations = 38;
+const TemporalKernelsN: TemporalKernels = 2;
+const RawFormatsN: RawFormats = 3;
 
 //////// import: "fffb-fffb.go"
 struct FFFB {
@@ -353,12 +430,12 @@ fn Op_WrapPad(op: Op, i: i32,ni: i32) {
 fn Op_FadePad(op: Op, i: i32,ni: i32) {
 	var ii = i;
 	var ri = op.InImageRGB;
-	var avg = Scalars[Index2D(TensorStrides[40], TensorStrides[41], u32(op.InScalar), u32(ni))];
+	var avg = Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(op.InScalar), u32(ni))];
 	if (ri == 3) {
 		var xy = op.Geom.In.x * op.Geom.In.y;
 		ri = i / xy;
 		ii = i % xy;
-		avg = Scalars[Index2D(TensorStrides[40], TensorStrides[41], u32(i32(op.InScalar) + ri), u32(ni))];
+		avg = Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(i32(op.InScalar) + ri), u32(ni))];
 	}
 	var y = ii / op.Geom.In.x;
 	var x = ii % op.Geom.In.x;
@@ -445,6 +522,7 @@ const  GeAvg: InhibVars = 5;
 const  GeMax: InhibVars = 6;
 const  ActAvg: InhibVars = 7;
 const  ActMax: InhibVars = 8;
+const  DelActMax: InhibVars = 9;
 
 //////// import: "kwta-chans.go"
 struct Chans {
@@ -468,7 +546,7 @@ struct KWTA {
 	ErevSubThr: Chans,
 	ThrSubErev: Chans,
 	ActDt: f32,
-	pad: f32,
+	EarlyStop: i32,
 	pad1: f32,
 	pad2: f32,
 }
@@ -488,17 +566,17 @@ fn Op_NeighInhib4(op: Op, i: i32,ni: i32) {
 	var npX = xo + ox;
 	var npY = yo + oy;
 	if (npX >= 0 && npX < op.Geom.Out.x && npY >= 0 && npY < op.Geom.Out.y) {
-		var v = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(npY), u32(npX), u32(pi), u32(ang))];
+		var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(npY), u32(npX), u32(pi), u32(ang))];
 		gi = max(gi, v);
 	}
 	npX = xo - ox;
 	npY = yo - oy;
 	if (npX >= 0 && npX < op.Geom.Out.x && npY >= 0 && npY < op.Geom.Out.y) {
-		var v = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(npY), u32(npX), u32(pi), u32(ang))];
+		var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(npY), u32(npX), u32(pi), u32(ang))];
 		gi = max(gi, v);
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24],
-	TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(ang))] = op.FloatArg1 * gi;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34],
+	TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(ang))] = op.FloatArg1 * gi;
 }
 fn NeighInhibOffsets(ang: i32, ox: ptr<function,i32>,oy: ptr<function,i32>) {
 	switch (ang) {
@@ -533,9 +611,9 @@ fn Op_LogValues(op: Op, i: i32,ni: i32) {
 	var ii = pii / 2;
 	var yo = ii / op.Geom.Out.x;
 	var xo = ii % op.Geom.Out.x;
-	var lg = op.FloatArg1 * log(1.0+Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))]);
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = lg;
+	var lg = op.FloatArg1 * log(1.0+Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))]);
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = lg;
 }
 fn Op_NormDiv(op: Op, i: i32,ni: i32) {
 	var fi = i % op.FilterN; // inner
@@ -544,12 +622,12 @@ fn Op_NormDiv(op: Op, i: i32,ni: i32) {
 	var ii = pii / 2;
 	var yo = ii / op.Geom.Out.x;
 	var xo = ii % op.Geom.Out.x;
-	var sc = Scalars[Index2D(TensorStrides[40], TensorStrides[41], u32(op.InScalar), u32(ni))];
-	var v = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var sc = Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(op.InScalar), u32(ni))];
+	var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
 	if (sc != 0) {
 		v /= sc;
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = v;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = v;
 }
 
 //////// import: "math32-fastexp.go"
@@ -568,16 +646,21 @@ fn Op_MaxPool(op: Op, i: i32,ni: i32) {
 	var iy = yo * op.Geom.Spacing.y;
 	var ix = xo * op.Geom.Spacing.x;
 	var mx = f32(0);
+	var mi = i32(0);
 	for (var py=0; py<fY; py++) {
 		for (var px=0; px<fX; px++) {
-			var iv = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(iy + py), u32(ix + px), u32(pi), u32(fi))];
+			var iv = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(iy + py), u32(ix + px), u32(pi), u32(fi))];
 			if (iv > mx) {
 				mx = iv;
+				mi = py*fX + px;
 			}
 		}
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = mx;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = mx;
+	if (op.IntArg2 > 0) {
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34],
+		TensorStrides[35], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = f32(mi);
+	}
 }
 fn Op_MaxPolarity(op: Op, i: i32,ni: i32) {
 	var szX = op.Geom.Out.x;
@@ -587,13 +670,13 @@ fn Op_MaxPolarity(op: Op, i: i32,ni: i32) {
 	var xo = ii % szX;
 	var mx = f32(0);
 	for (var pi=0; pi<2; pi++) {
-		var iv = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+		var iv = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
 		if (iv > mx) {
 			mx = iv;
 		}
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = mx;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(fi))] = mx;
 }
 fn Op_MaxCopy(op: Op, i: i32,ni: i32) {
 	var szX = op.Geom.Out.x;
@@ -603,9 +686,9 @@ fn Op_MaxCopy(op: Op, i: i32,ni: i32) {
 	var ii = pii / 2;
 	var yo = ii / szX;
 	var xo = ii % szX;
-	var i1 = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
-	var i2 = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue2), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = max(i1, i2);
+	var i1 = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var i2 = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue2), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = max(i1, i2);
 }
 
 //////// import: "motion.go"
@@ -616,9 +699,9 @@ fn Op_MotionIntegrate(op: Op, i: i32,ni: i32) {
 	var ii = pii / 2;
 	var yo = ii / op.Geom.Out.x;
 	var xo = ii % op.Geom.Out.x;
-	var v = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
-	var f = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
-	var s = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var f = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var s = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
 	if (v > f) {
 		f = v;
 	} else {
@@ -629,9 +712,9 @@ fn Op_MotionIntegrate(op: Op, i: i32,ni: i32) {
 	} else {
 		s += op.FloatArg2 * (v - s);
 	}
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = f;
-	Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23],
-	TensorStrides[24], TensorStrides[25], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = s;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = f;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = s;
 }
 fn Op_MotionStar(op: Op, i: i32,ni: i32) {
 	var szX = op.Geom.Out.x - 1;
@@ -652,27 +735,128 @@ fn Op_MotionStar(op: Op, i: i32,ni: i32) {
 	} else {
 		yoff = i32(1);
 	}
-	var cf = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], // fast
-	TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fio))];
-	var nf = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], // next
-	TensorStrides[25], u32(op.InValue), u32(ni), u32(yo + yoff), u32(xo + xoff), u32(pi), u32(fio))];
-	var cs = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], // slow
-	TensorStrides[25], u32(op.InValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fio))];
-	var ns = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], // next
-	TensorStrides[25], u32(op.InValue + 1), u32(ni), u32(yo + yoff), u32(xo + xoff), u32(pi), u32(fio))];
+	var cf = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], // fast
+	TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fio))];
+	var nf = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], // next
+	TensorStrides[35], u32(op.InValue), u32(ni), u32(yo + yoff), u32(xo + xoff), u32(pi), u32(fio))];
+	var cs = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], // slow
+	TensorStrides[35], u32(op.InValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fio))];
+	var ns = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], // next
+	TensorStrides[35], u32(op.InValue + 1), u32(ni), u32(yo + yoff), u32(xo + xoff), u32(pi), u32(fio))];
 	var minact = min(min(min(cf, cs), nf), ns);
 	var cd = cf - cs;
 	var nd = nf - ns;
 	var v = op.FloatArg1 * (cd - nd);
 	if (v >= 0) { // delta bigger on current than next
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], // 0 = left/down
-		TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff))] = minact * v;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff + 1))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], // 0 = left/down
+		TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff))] = minact * v;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff + 1))] = 0.0;
 	} else {
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], // 1 = right/up
-		TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff + 1))] = -minact * v;
-		Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24],
-		TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff))] = 0.0;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], // 1 = right/up
+		TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff + 1))] = -minact * v;
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34],
+		TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(doff))] = 0.0;
+	}
+}
+fn Op_MotionFlow(op: Op, i: i32,ni: i32) {
+	var yo = i / op.Geom.Out.x;
+	var xo = i % op.Geom.Out.x;
+	var sy = yo * op.Geom.Spacing.y;
+	var sx = xo * op.Geom.Spacing.x;
+	var dx = f32(0);
+	var dy = f32(0);
+	for (var py=0; py<op.Geom.FilterSize.y; py++) {
+		var y = sy + py;
+		if (y >= op.Geom.In.y) {
+			continue;
+		}
+		for (var px=0; px<op.Geom.FilterSize.x; px++) {
+			var x = sx + px;
+			if (x >= op.Geom.In.x) {
+				continue;
+			}
+			for (var pi=0; pi<2; pi++) { // pos / neg
+				for (var fi=0; fi<op.FilterN; fi++) { // original features
+					var dfo = fi * 4;
+					var l = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(y), u32(x), u32(pi), u32(dfo))];
+					var r = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(y), u32(x), u32(pi), u32(dfo + 1))];
+					var d = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(y), u32(x), u32(pi), u32(dfo + 2))];
+					var u = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(y), u32(x), u32(pi), u32(dfo + 3))];
+					dx += r - l;
+					dy += u - d;
+				}
+			}
+		}
+	}
+	var norm = op.FloatArg1 / f32(op.Geom.FilterSize.y*op.Geom.FilterSize.x);
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(0))] = norm * dx;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(0), u32(1))] = norm * dy;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(0))] = 0.0;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(1), u32(1))] = 0.0;
+}
+fn Op_MotionGrid(op: Op, i: i32,ni: i32) {
+	var gY = op.Geom.FilterSize.y;
+	var gX = op.Geom.FilterSize.x;
+	var szY = op.Geom.Out.y - 1;
+	var szX = op.Geom.Out.x - 1;
+	var gy = i / gX;
+	var gx = i % gX;
+	var sy = (gy * szY) / gY;
+	var ey = ((gy + 1) * szY) / gY;
+	var sx = (gx * szX) / gX;
+	var ex = ((gx + 1) * szX) / gX;
+	var visNorm = Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(op.InScalar), u32(ni))];
+	var norm = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(gy), u32(gx), u32(0), u32(0))];
+	if (norm == 0) {
+		norm = visNorm;
+	} else {
+		norm += op.FloatArg2 * (visNorm - norm);
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(gy), u32(gx), u32(0), u32(0))] = norm;
+	var vnf = op.FloatArg1 * f32(gY*gX);
+	if (norm > 0) {
+		vnf /= norm;
+	}
+	for (var dir=0; dir<i32(2); dir++) { // left-right, down-up
+		var doff = dir * 2;
+		var csum = f32(0);
+		var nsum = f32(0);
+		for (var yo = sy;
+		 yo < ey; yo++) {
+			for (var xo = sx;
+			 xo < ex; xo++) {
+				for (var pi=0; pi<2; pi++) { // pos / neg
+					for (var fi=0; fi<op.FilterN; fi++) { // original features
+						var dfo = fi*4 + doff;
+						var c = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], // left, down
+						TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo))];
+						var n = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], // right, up
+						TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo + 1))];
+						var v = c - n;
+						if (v >= 0) {
+							csum += v;
+						} else {
+							nsum += -v;
+						}
+					}
+				}
+			}
+		}
+		if (csum > nsum) {
+			csum = vnf * (csum - nsum);
+			nsum = f32(0);
+		} else {
+			nsum = vnf * (nsum - csum);
+			csum = f32(0);
+		}
+		var cint = Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(gy), u32(gx), u32(dir), u32(0))];
+		var nint = Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(gy), u32(gx), u32(dir), u32(1))];
+		cint += op.FloatArg2 * (csum - cint);
+		nint += op.FloatArg2 * (nsum - nint);
+		Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(gy), u32(gx), u32(dir), u32(0))] = cint;
+		Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44],
+		TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(gy), u32(gx), u32(dir), u32(1))] = nint;
 	}
 }
 
@@ -713,15 +897,29 @@ const  MeanScalar: Operations = 11;
 const  NormDiv: Operations = 12;
 const  NeighInhib4: Operations = 13;
 const  KWTAInhib: Operations = 14;
-const  MaxPool: Operations = 15;
-const  MaxPolarity: Operations = 16;
-const  MaxCopy: Operations = 17;
-const  LenSum4: Operations = 18;
-const  EndStop4: Operations = 19;
-const  To4D: Operations = 20;
-const  MotionIntegrate: Operations = 21;
-const  MotionStar: Operations = 22;
-const  MotionFullField: Operations = 23;
+const  KWTAInhib4D: Operations = 15;
+const  MaxPool: Operations = 16;
+const  MaxPolarity: Operations = 17;
+const  MaxCopy: Operations = 18;
+const  LenSum4: Operations = 19;
+const  EndStop4: Operations = 20;
+const  To4D: Operations = 21;
+const  MotionIntegrate: Operations = 22;
+const  MotionStar: Operations = 23;
+const  MotionFullField: Operations = 24;
+const  MotionFlow: Operations = 25;
+const  MotionOpticFlow: Operations = 26;
+const  MotionGrid: Operations = 27;
+const  TemporalFilter: Operations = 28;
+const  ResetValues: Operations = 29;
+const  ResetValues4D: Operations = 30;
+const  BinocularEnergy: Operations = 31;
+const  PoissonSpikes: Operations = 32;
+const  TopKPool: Operations = 33;
+const  TopKLayer: Operations = 34;
+const  UnPool: Operations = 35;
+const  DeconvImage: Operations = 36;
+const  UnpackImage: Operations = 37;
 struct Op {
 	Op: Operations,
 	NData: u32,
@@ -744,9 +942,9 @@ struct Op {
 	OutScalar: i32,
 	Inhibs: i32,
 	KWTA: i32,
-	pad: i32,
-	pad1: i32,
-	pad2: i32,
+	InImage2: i32,
+	IntArg2: i32,
+	IntArg3: i32,
 	Geom: Geom,
 }
 fn Op_Run(op: Op, ri: i32,ni: i32) {
@@ -802,6 +1000,42 @@ fn Op_Run(op: Op, ri: i32,ni: i32) {
 	case MotionStar: {
 		Op_MotionStar(op, ri, ni);
 	}
+	case MotionFlow: {
+		Op_MotionFlow(op, ri, ni);
+	}
+	case MotionGrid: {
+		Op_MotionGrid(op, ri, ni);
+	}
+	case TemporalFilter: {
+		Op_TemporalFilter(op, ri, ni);
+	}
+	case ResetValues: {
+		Op_ResetValues(op, ri, ni);
+	}
+	case ResetValues4D: {
+		Op_ResetValues4D(op, ri, ni);
+	}
+	case BinocularEnergy: {
+		Op_BinocularEnergy(op, ri, ni);
+	}
+	case PoissonSpikes: {
+		Op_PoissonSpikes(op, ri, ni);
+	}
+	case TopKPool: {
+		Op_TopKPool(op, ri, ni);
+	}
+	case TopKLayer: {
+		Op_TopKLayer(op, ri, ni);
+	}
+	case UnPool: {
+		Op_UnPool(op, ri, ni);
+	}
+	case DeconvImage: {
+		Op_DeconvImage(op, ri, ni);
+	}
+	case UnpackImage: {
+		Op_UnpackImage(op, ri, ni);
+	}
 	default: {
 	}
 	}
@@ -829,6 +1063,135 @@ const Pi = 3.141592653589793;
 
 //////// import: "slmath-vector3.go"
 
+//////// import: "spikes.go"
+fn Op_PoissonRand(op: Op, i: i32,ni: i32,cy: i32) -> f32 {
+	var key = u32(ni)*op.RunN + u32(i);
+	var ctr: su64;
+	var st = u32(op.IntArg2 * op.IntArg1);
+return RandFloat32(ctr, st+u32(cy), key);
+}
+fn Op_PoissonSpikes(op: Op, i: i32,ni: i32) {
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % 2; // plus-minus
+	var ii = pii / 2;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	var p = op.FloatArg1 * Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var n = i32(0);
+	for (var cy=0; cy<op.IntArg1; cy++) {
+		if (Op_PoissonRand(op, i, ni, cy) < p) {
+			n++;
+		}
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = f32(n);
+}
+
+//////// import: "state.go"
+fn Op_ResetValues(op: Op, i: i32,ni: i32) {
+	var di = ni;
+	if (op.IntArg1 >= 0) {
+		di = op.IntArg1;
+	}
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % 2; // plus-minus
+	var ii = pii / 2;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(di), u32(yo), u32(xo), u32(pi), u32(fi))] = 0.0;
+}
+fn Op_ResetValues4D(op: Op, i: i32,ni: i32) {
+	var di = ni;
+	if (op.IntArg1 >= 0) {
+		di = op.IntArg1;
+	}
+	var fY = op.Geom.FilterSize.y;
+	var fX = op.Geom.FilterSize.x;
+	var ux = i % fX;
+	var pii = i / fX;
+	var uy = pii % fY;
+	var ii = pii / fY;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(di), u32(yo), u32(xo), u32(uy), u32(ux))] = 0.0;
+}
+
+//////// import: "stereo.go"
+fn BinocularShift(di: i32,nd: i32,dispStep: i32) -> i32 {
+	var s = (2*di - (nd - 1)) * dispStep;
+	if (s%2 != 0) {
+		if (s > 0) {
+			s++;
+		} else {
+			s--;
+		}
+	}return s / 2;
+}
+fn Op_BinocularEnergy(op: Op, i: i32,ni: i32) {
+	var fn = op.FilterN;
+	var nd = op.IntArg1;
+	var ang = i % fn;
+	var ii = i / fn;
+	var di = ii % nd;
+	var pi = ii / nd;
+	var yo = pi / op.Geom.Out.x;
+	var xo = pi % op.Geom.Out.x;
+	var shift = BinocularShift(di, nd, op.IntArg2);
+	var istX = op.Geom.Border.x - op.Geom.FilterLt.x;
+	var istY = op.Geom.Border.y - op.Geom.FilterLt.y;
+	var yi = istY + yo*op.Geom.Spacing.y;
+	var xi = istX + xo*op.Geom.Spacing.x;
+	var xr = xi + shift;
+	var rfi = di*2*fn + ang; // right filter index
+	var le = f32(0);
+	var lo = f32(0);
+	var re = f32(0);
+	var ro = f32(0);
+	for (var fy=0; fy<op.Geom.FilterSize.y; fy++) {
+		for (var fx=0; fx<op.Geom.FilterSize.x; fx++) {
+			var lv = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xi + fx))];
+			var rv = Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.InImage2), u32(ni), u32(op.InImageRGB), u32(yi + fy), u32(xr + fx))];
+			le += lv * Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(ang), u32(fy), u32(fx))];
+			lo += lv * Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType), u32(fn + ang), u32(fy), u32(fx))];
+			re += rv * Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType + 1), u32(rfi), u32(fy), u32(fx))];
+			ro += rv * Filters[Index4D(TensorStrides[0], TensorStrides[1], TensorStrides[2], TensorStrides[3], u32(op.FilterType + 1), u32(rfi + fn), u32(fy), u32(fx))];
+		}
+	}
+	var ev = le + re;
+	var od = lo + ro;
+	Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(yo), u32(xo), u32(di), u32(ang))] = op.FloatArg1 * (ev*ev + od*od);
+}
+
+//////// import: "temporal.go"
+alias TemporalKernels = i32; //enums:enum
+const  Exponential: TemporalKernels = 0;
+const  Biphasic: TemporalKernels = 1;
+fn Op_TemporalFilter(op: Op, i: i32,ni: i32) {
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % 2; // plus-minus
+	var ii = pii / 2;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var f = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	f += op.FloatArg1 * (v - f);
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue + 1), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = f;
+	var out = f;
+	if (TemporalKernels(op.IntArg1) == Biphasic) {
+		var s = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue + 2), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+		s += op.FloatArg2 * (v - s);
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue + 2), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = s;
+		out = f - s;
+		if (out < 0) {
+			out = -out;
+		}
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = op.FloatArg3 * out;
+}
+
 //////// import: "to4d.go"
 fn Op_To4D(op: Op, i: i32,ni: i32) {
 	var fY = op.Geom.FilterSize.y;
@@ -841,6 +1204,302 @@ fn Op_To4D(op: Op, i: i32,ni: i32) {
 	var yo = ii / szX;
 	var xo = ii % szX;
 	var toY = op.IntArg1;
-	var iv = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
-	Values4D[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue4D), u32(ni), u32(yo), u32(xo), u32(toY + pi), u32(fi))] = iv;
+	var iv = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(yo), u32(xo), u32(toY + pi), u32(fi))] = iv;
+}
+
+//////// import: "topk.go"
+fn Op_TopKPool(op: Op, i: i32,ni: i32) {
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % 2; // plus-minus
+	var ii = pii / 2;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	var idx = pi*op.FilterN + fi;
+	var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	var rank = i32(0);
+	for (var py=0; py<i32(2); py++) {
+		for (var px=0; px<op.FilterN; px++) {
+			var w = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+			if (w > v || (w == v && py*op.FilterN+px < idx)) {
+				rank++;
+			}
+		}
+	}
+	if (rank >= op.IntArg1) {
+		v = f32(0);
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = v;
+}
+fn TopKKey(v: f32) -> u32 {
+	var b = bitcast<u32>(v);
+	if (b >= 0x80000000) { // negative: reverse order
+		return 0xFFFFFFFF - b;
+	}return b + 0x80000000;
+}
+fn Op_TopKThreshold(op: Op, ni: i32) -> u32 {
+	var hi = u32(Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(op.OutScalar), u32(ni))]);
+	var lo = u32(Scalars[Index2D(TensorStrides[50], TensorStrides[51], u32(op.OutScalar + 1), u32(ni))]);
+return (hi << 16) + lo;
+}
+fn Op_TopKLayer(op: Op, i: i32,ni: i32) {
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % 2; // plus-minus
+	var ii = pii / 2;
+	var yo = ii / op.Geom.Out.x;
+	var xo = ii % op.Geom.Out.x;
+	var v = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))];
+	if (TopKKey(v) < Op_TopKThreshold(op, ni)) {
+		v = f32(0);
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))] = v;
+}
+
+//////// import: "unpool.go"
+fn Op_UnPool(op: Op, i: i32,ni: i32) {
+	var fY = op.Geom.FilterSize.y;
+	var fX = op.Geom.FilterSize.x;
+	var sY = op.Geom.Spacing.y;
+	var sX = op.Geom.Spacing.x;
+	var fi = i % op.FilterN; // inner
+	var pii = i / op.FilterN;
+	var pi = pii % op.IntArg1; // plus-minus
+	var ii = pii / op.IntArg1;
+	var y = ii / op.Geom.In.x;
+	var x = ii % op.Geom.In.x;
+	var sy = (max(y-fY+1, 0) + sY - 1) / sY;
+	var ey = min(y/sY+1, op.Geom.Out.y);
+	var sx = (max(x-fX+1, 0) + sX - 1) / sX;
+	var ex = min(x/sX+1, op.Geom.Out.x);
+	var mx = f32(0);
+	for (var yo = sy;
+	 yo < ey; yo++) {
+		for (var xo = sx;
+		 xo < ex; xo++) {
+			var pidx = (y-yo*sY)*fX + (x - xo*sX);
+			if (op.InValue2 >= 0 && i32(Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue2), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))]) != pidx) {
+				continue;
+			}
+			mx = max(mx, Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(fi))]);
+		}
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(y), u32(x), u32(pi), u32(fi))] = mx;
+}
+
+//////// import: "upload.go"
+alias RawFormats = i32; //enums:enum
+const  RawRGBA: RawFormats = 0;
+const  RawNRGBA: RawFormats = 1;
+const  RawGray: RawFormats = 2;
+fn Op_UnpackImage(op: Op, i: i32,ni: i32) {
+	var y = i / op.Geom.In.x;
+	var x = i % op.Geom.In.x;
+	var padWidth = op.IntArg1;
+	var sY = op.Geom.In.y - 2*padWidth;
+	var sX = op.Geom.In.x - 2*padWidth;
+	var uy = y - padWidth;
+	var ux = x - padWidth;
+	var r = f32(0);
+	var g = f32(0);
+	var b = f32(0);
+	if (uy >= 0 && uy < sY && ux >= 0 && ux < sX) {
+		var sy = uy;
+		if (op.IntArg3 == 0) {
+			sy = (sY - 1) - uy;
+		}
+		var pv = RawImages[Index3D(TensorStrides[20], TensorStrides[21], TensorStrides[22], u32(ni), u32(sy), u32(ux))];
+		r = f32(pv&0xFF) / 255.0;
+		if (op.IntArg2 == i32(RawGray)) {
+			g = r;
+			b = r;
+		} else {
+			g = f32((pv>>8)&0xFF) / 255.0;
+			b = f32((pv>>16)&0xFF) / 255.0;
+			var a = f32((pv>>24)&0xFF) / 255.0;
+			if (op.IntArg2 == i32(RawRGBA)) {
+				if (a > 0) { // un-premultiply, as in colors.ToFloat32
+					r /= a;
+					g /= a;
+					b /= a;
+				}
+			}
+		}
+	}
+	if (op.InImageRGB == 3) {
+		Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(0), u32(y), u32(x))] = r;
+		Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(1), u32(y), u32(x))] = g;
+		Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(2), u32(y), u32(x))] = b;
+	} else {
+		Images[Index5D(TensorStrides[10], TensorStrides[11], TensorStrides[12], TensorStrides[13], TensorStrides[14], u32(op.OutImage), u32(ni), u32(op.InImageRGB), u32(y), u32(x))] = (r + g + b) / 3;
+	}
+}
+
+//////// import: "slrand.wgsl"
+fn Philox2x32round(counter: su64, key: u32) -> su64 {
+	let mul = Uint32Mul64(u32(0xD256D193), counter.x);
+	var ctr: su64;
+	ctr.x = mul.y ^ key ^ counter.y;
+	ctr.y = mul.x;
+	return ctr;
+}
+fn Philox2x32bumpkey(key: u32) -> u32 {
+	return key + u32(0x9E3779B9);
+}
+fn Philox2x32(counter: su64, key: u32) -> vec2<u32> {
+	var ctr = Philox2x32round(counter, key); // 1
+	var ky = Philox2x32bumpkey(key);
+	ctr = Philox2x32round(ctr, ky); // 2
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 3
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 4
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 5
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 6
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 7
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 8
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 9
+	ky = Philox2x32bumpkey(ky);
+	return Philox2x32round(ctr, ky); // 10
+}
+fn RandUint32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<u32> {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key);
+}
+fn RandUint32(counter: su64, funcIndex: u32, key: u32) -> u32 {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key).x;
+}
+fn RandFloat32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32(RandUint32(counter, funcIndex, key));
+}
+fn RandFloat32Range11Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32Range11(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32Range11(RandUint32(counter, funcIndex, key));
+}
+fn RandBoolP(counter: su64, funcIndex: u32, key: u32, p: f32) -> bool { 
+	return (RandFloat32(counter, funcIndex, key) < p);
+}
+fn sincospi(x: f32) -> vec2<f32> {
+	let PIf = 3.1415926535897932;
+	var r: vec2<f32>;
+	r.x = cos(PIf*x);
+	r.y = sin(PIf*x);
+	return r;
+}
+fn RandFloat32NormVec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> { 
+	let ur = RandUint32Vec2(counter, funcIndex, key);
+	var f = sincospi(Uint32ToFloat32Range11(ur.x));
+	let r = sqrt(-2.0 * log(Uint32ToFloat32(ur.y))); // guaranteed to avoid 0.
+	return f * r;
+}
+fn RandFloat32Norm(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return RandFloat32Vec2(counter, funcIndex, key).x;
+}
+fn RandUint32N(counter: su64, funcIndex: u32, key: u32, n: u32) -> u32 { 
+	let v = RandFloat32(counter, funcIndex, key);
+	return u32(v * f32(n));
+}
+struct RandCounter {
+	Counter: su64,
+	HiSeed: u32,
+	pad: u32,
+}
+fn RandCounter_Reset(ct: ptr<function,RandCounter>) {
+	(*ct).Counter.x = u32(0);
+	(*ct).Counter.y = (*ct).HiSeed;
+}
+fn RandCounter_Seed(ct: ptr<function,RandCounter>, seed: u32) {
+	(*ct).HiSeed = seed;
+	RandCounter_Reset(ct);
+}
+fn RandCounter_Add(ct: ptr<function,RandCounter>, inc: u32) {
+	(*ct).Counter = Uint64Add32((*ct).Counter, inc);
+}
+
+//////// import: "sltype.wgsl"
+alias su64 = vec2<u32>;
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	let LOMASK = (((u32(1))<<16)-1);
+	var r: su64;
+	r.x = a * b;               /* full low multiply */
+	let ahi = a >> 16;
+	let alo = a & LOMASK;
+	let bhi = b >> 16;
+	let blo = b & LOMASK;
+	let ahbl = ahi * blo;
+	let albh = alo * bhi;
+	let ahbl_albh = ((ahbl&LOMASK) + (albh&LOMASK));
+	var hit = ahi*bhi + (ahbl>>16) +  (albh>>16);
+	hit += ahbl_albh >> 16; /* carry from the sum of lo(ahbl) + lo(albh) ) */
+	/* carry from the sum with alo*blo */
+	if ((r.x >> u32(16)) < (ahbl_albh&LOMASK)) {
+		hit += u32(1);
+	}
+	r.y = hit; 
+	return r;
+}
+/*
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	return su64(a) * su64(b);
+}
+*/
+fn Uint64Add32(a: su64, b: u32) -> su64 {
+	if (b == 0) {
+		return a;
+	}
+	var s = a;
+	if (s.x > u32(0xffffffff) - b) {
+		s.y++;
+		s.x = (b - 1) - (u32(0xffffffff) - s.x);
+	} else {
+		s.x += b;
+	}
+	return s;
+}
+fn Uint64Incr(a: su64) -> su64 {
+	var s = a;
+	if(s.x == 0xffffffff) {
+		s.y++;
+		s.x = u32(0);
+	} else {
+		s.x++;
+	}
+	return s;
+}
+fn Uint32ToFloat32(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(u32(0xffffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	var f = f32(val) * factor + halffactor;
+	if (f == 1.0) { // exclude 1
+		return bitcast<f32>(0x3F7FFFFF);
+	}
+	return f;
+}
+fn Uint32ToFloat32Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32(val.x);
+	r.y = Uint32ToFloat32(val.y);
+	return r;
+}
+fn Uint32ToFloat32Range11(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(i32(0x7fffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	return (f32(val) * factor + halffactor);
+}
+fn Uint32ToFloat32Range11Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32Range11(val.x);
+	r.y = Uint32ToFloat32Range11(val.y);
+	return r;
 }
\ No newline at end of file
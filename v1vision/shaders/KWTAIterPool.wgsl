@@ -10,9 +10,11 @@ var<storage, read> CurOp: array<Op>;
 var<storage, read> KWTAs: array<KWTA>;
 // // Filters are one general stack of rendered filters, sized to the max of each // of the inner dimensional values: [FilterTypes][FilterN][Y][X] // FilterTypes = different filter types (DoG, Gabor, etc) // FilterN = number of filters within the group (On, Off, angle, etc) // Y, X = sizes. 
 // // Images are float-valued image data: // [ImageNo][NData][RGB][Y][X], // sized to the max of each inner-dimensional value (RGB=3, // if more needed, use additional ImageNo) 
-@group(2) @binding(1)
+@group(2) @binding(2)
 var<storage, read_write> Values: array<f32>;
-@group(2) @binding(4)
+@group(2) @binding(3)
+var<storage, read_write> Values4D: array<f32>;
+@group(2) @binding(5)
 var<storage, read_write> Inhibs: array<f32>;
 
 alias GPUVars = i32;
@@ -57,10 +59,14 @@ func LMSToXYZ_CAT02(l, m, s f32) (x, y, z f32) {
 
 //////// import: "convolve.go"
 
+//////// import: "deconv.go"
+
 //////// import: "enumgen.go"
-const GPUVarsN: GPUVars = 8;
-const InhibVarsN: InhibVars = 9;
-const OperationsN: Operations = 24;
+const GPUVarsN: GPUVars = 9;
+const InhibVarsN: InhibVars = 10;
+const OperationsN: Operations = 38;
+const TemporalKernelsN: TemporalKernels = 2;
+const RawFormatsN: RawFormats = 3;
 
 //////// import: "fffb-fffb.go"
 struct FFFB {
@@ -113,6 +119,7 @@ const  GeAvg: InhibVars = 5;
 const  GeMax: InhibVars = 6;
 const  ActAvg: InhibVars = 7;
 const  ActMax: InhibVars = 8;
+const  DelActMax: InhibVars = 9;
 
 //////// import: "kwta-chans.go"
 struct Chans {
@@ -136,7 +143,7 @@ struct KWTA {
 	ErevSubThr: Chans,
 	ThrSubErev: Chans,
 	ActDt: f32,
-	pad: f32,
+	EarlyStop: i32,
 	pad1: f32,
 	pad2: f32,
 }
@@ -152,6 +159,26 @@ return nwAct;
 }
 
 //////// import: "kwta.go"
+fn Op_KWTAGe(op: Op, ni: i32,yo: i32,xo: i32,py: i32,px: i32) -> f32 {
+	if (op.Op == KWTAInhib4D) {
+		return Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+	}return Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+}
+fn Op_KWTAAct(op: Op, ni: i32,yo: i32,xo: i32,py: i32,px: i32) -> f32 {
+	if (op.Op == KWTAInhib4D) {
+		return Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+	}return Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+}
+fn Op_SetKWTAAct(op: Op, act: f32, ni: i32,yo: i32,xo: i32,py: i32,px: i32) {
+	if (op.Op == KWTAInhib4D) {
+		Values4D[Index6D(TensorStrides[40], TensorStrides[41], TensorStrides[42], TensorStrides[43], TensorStrides[44], TensorStrides[45], u32(op.OutValue4D), u32(ni), u32(yo), u32(xo), u32(py), u32(px))] = act;
+	} else {
+		Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+		TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))] = act;
+	}
+}
 fn KWTAIterPool(i: u32) { //gosl:kernel
 	let op = CurOp[0];
 	if (i >= op.RunN*op.NData) {
@@ -163,13 +190,13 @@ fn KWTAIterPool(i: u32) { //gosl:kernel
 	var szX = op.Geom.Out.x;
 	var yo = ri / szX;
 	var xo = ri % szX;
-	var layGi = Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(szY), u32(0), u32(Gi))];
+	var layGi = Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(szY), u32(0), u32(Gi))];
 	let kp = KWTAs[u32(op.KWTA)];
-	var pn = 2 * op.FilterN;
-	var geAvg = Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GeAvg))];
-	var geMax = Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GeMax))];
-	var actAvg = Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActAvg))];
-	var fbi = Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FBi))];
+	var pn = op.IntArg1 * op.FilterN;
+	var geAvg = Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GeAvg))];
+	var geMax = Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GeMax))];
+	var actAvg = Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActAvg))];
+	var fbi = Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FBi))];
 	var ffi = FFFB_FFInhib(kp.Pool, geAvg, geMax);
 	var newFBi = FFFB_FBInhib(kp.Pool, actAvg);
 	fbi = FFFB_FBUpdt(kp.Pool, fbi, newFBi);
@@ -177,37 +204,40 @@ fn KWTAIterPool(i: u32) { //gosl:kernel
 		ffi = f32(0.0);
 		fbi = f32(0.0);
 	}
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FFi))] = ffi;
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FBi))] = fbi;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FFi))] = ffi;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(FBi))] = fbi;
 	var gi = kp.Pool.Gi * (ffi + fbi);
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(Gi))] = gi;
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GiOrig))] = gi;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(Gi))] = gi;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(GiOrig))] = gi;
 	var giPool = max(layGi, gi);
 	actAvg = f32(0.0);
 	var actMax = f32(0.0);
-	for (var py=0; py<2; py++) { // op.Geom.FilterSize.Y {
+	var maxDelAct = f32(0.0);
+	for (var py=0; py<op.IntArg1; py++) {
 		for (var px=0; px<op.FilterN; px++) {
 			var pgi = giPool;
 			if (op.InValue2 > 0) {
-				var eIn = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue2), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+				var eIn = Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue2), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
 				var eGi = kp.Pool.Gi * FFFB_FFInhib(kp.Pool, eIn, eIn);
 				pgi = max(pgi, eGi);
 			}
 			var geThr = KWTA_GeThrFromG(kp, pgi);
-			var ge = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
-			var act = Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))];
+			var ge = Op_KWTAGe(op, ni, yo, xo, py, px);
+			var act = Op_KWTAAct(op, ni, yo, xo, py, px);
 			geAvg += ge;
 			geMax = max(geMax, ge);
 			var delAct = f32(0);
 			var nwAct = KWTA_ActFromG(kp, geThr, ge, act, &delAct);
-			Values[Index6D(TensorStrides[20], TensorStrides[21], TensorStrides[22], TensorStrides[23], TensorStrides[24], TensorStrides[25], u32(op.OutValue), u32(ni), u32(yo), u32(xo), u32(py), u32(px))] = nwAct;
+			maxDelAct = max(maxDelAct, abs(delAct));
+			Op_SetKWTAAct(op, nwAct, ni, yo, xo, py, px);
 			actAvg += nwAct;
 			actMax = max(actMax, nwAct);
 		}
 	}
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53], TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActAvg))] = actAvg / f32(pn);
-	Inhibs[Index5D(TensorStrides[50], TensorStrides[51], TensorStrides[52], TensorStrides[53],
-	TensorStrides[54], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActMax))] = actMax;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActAvg))] = actAvg / f32(pn);
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63], TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(ActMax))] = actMax;
+	Inhibs[Index5D(TensorStrides[60], TensorStrides[61], TensorStrides[62], TensorStrides[63],
+	TensorStrides[64], u32(op.Inhibs), u32(ni), u32(yo), u32(xo), u32(DelActMax))] = maxDelAct;
 }
 
 //////// import: "logrenorm.go"
@@ -283,15 +313,29 @@ const  MeanScalar: Operations = 11;
 const  NormDiv: Operations = 12;
 const  NeighInhib4: Operations = 13;
 const  KWTAInhib: Operations = 14;
-const  MaxPool: Operations = 15;
-const  MaxPolarity: Operations = 16;
-const  MaxCopy: Operations = 17;
-const  LenSum4: Operations = 18;
-const  EndStop4: Operations = 19;
-const  To4D: Operations = 20;
-const  MotionIntegrate: Operations = 21;
-const  MotionStar: Operations = 22;
-const  MotionFullField: Operations = 23;
+const  KWTAInhib4D: Operations = 15;
+const  MaxPool: Operations = 16;
+const  MaxPolarity: Operations = 17;
+const  MaxCopy: Operations = 18;
+const  LenSum4: Operations = 19;
+const  EndStop4: Operations = 20;
+const  To4D: Operations = 21;
+const  MotionIntegrate: Operations = 22;
+const  MotionStar: Operations = 23;
+const  MotionFullField: Operations = 24;
+const  MotionFlow: Operations = 25;
+const  MotionOpticFlow: Operations = 26;
+const  MotionGrid: Operations = 27;
+const  TemporalFilter: Operations = 28;
+const  ResetValues: Operations = 29;
+const  ResetValues4D: Operations = 30;
+const  BinocularEnergy: Operations = 31;
+const  PoissonSpikes: Operations = 32;
+const  TopKPool: Operations = 33;
+const  TopKLayer: Operations = 34;
+const  UnPool: Operations = 35;
+const  DeconvImage: Operations = 36;
+const  UnpackImage: Operations = 37;
 struct Op {
 	Op: Operations,
 	NData: u32,
@@ -314,9 +358,9 @@ struct Op {
 	OutScalar: i32,
 	Inhibs: i32,
 	KWTA: i32,
-	pad: i32,
-	pad1: i32,
-	pad2: i32,
+	InImage2: i32,
+	IntArg2: i32,
+	IntArg3: i32,
 	Geom: Geom,
 }
 
@@ -333,4 +377,192 @@ const Pi = 3.141592653589793;
 
 //////// import: "slmath-vector3.go"
 
-//////// import: "to4d.go"
\ No newline at end of file
+//////// import: "spikes.go"
+
+//////// import: "state.go"
+
+//////// import: "stereo.go"
+
+//////// import: "temporal.go"
+alias TemporalKernels = i32; //enums:enum
+const  Exponential: TemporalKernels = 0;
+const  Biphasic: TemporalKernels = 1;
+
+//////// import: "to4d.go"
+
+//////// import: "topk.go"
+
+//////// import: "unpool.go"
+
+//////// import: "upload.go"
+alias RawFormats = i32; //enums:enum
+const  RawRGBA: RawFormats = 0;
+const  RawNRGBA: RawFormats = 1;
+const  RawGray: RawFormats = 2;
+
+//////// import: "slrand.wgsl"
+fn Philox2x32round(counter: su64, key: u32) -> su64 {
+	let mul = Uint32Mul64(u32(0xD256D193), counter.x);
+	var ctr: su64;
+	ctr.x = mul.y ^ key ^ counter.y;
+	ctr.y = mul.x;
+	return ctr;
+}
+fn Philox2x32bumpkey(key: u32) -> u32 {
+	return key + u32(0x9E3779B9);
+}
+fn Philox2x32(counter: su64, key: u32) -> vec2<u32> {
+	var ctr = Philox2x32round(counter, key); // 1
+	var ky = Philox2x32bumpkey(key);
+	ctr = Philox2x32round(ctr, ky); // 2
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 3
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 4
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 5
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 6
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 7
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 8
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 9
+	ky = Philox2x32bumpkey(ky);
+	return Philox2x32round(ctr, ky); // 10
+}
+fn RandUint32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<u32> {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key);
+}
+fn RandUint32(counter: su64, funcIndex: u32, key: u32) -> u32 {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key).x;
+}
+fn RandFloat32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32(RandUint32(counter, funcIndex, key));
+}
+fn RandFloat32Range11Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32Range11(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32Range11(RandUint32(counter, funcIndex, key));
+}
+fn RandBoolP(counter: su64, funcIndex: u32, key: u32, p: f32) -> bool { 
+	return (RandFloat32(counter, funcIndex, key) < p);
+}
+fn sincospi(x: f32) -> vec2<f32> {
+	let PIf = 3.1415926535897932;
+	var r: vec2<f32>;
+	r.x = cos(PIf*x);
+	r.y = sin(PIf*x);
+	return r;
+}
+fn RandFloat32NormVec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> { 
+	let ur = RandUint32Vec2(counter, funcIndex, key);
+	var f = sincospi(Uint32ToFloat32Range11(ur.x));
+	let r = sqrt(-2.0 * log(Uint32ToFloat32(ur.y))); // guaranteed to avoid 0.
+	return f * r;
+}
+fn RandFloat32Norm(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return RandFloat32Vec2(counter, funcIndex, key).x;
+}
+fn RandUint32N(counter: su64, funcIndex: u32, key: u32, n: u32) -> u32 { 
+	let v = RandFloat32(counter, funcIndex, key);
+	return u32(v * f32(n));
+}
+struct RandCounter {
+	Counter: su64,
+	HiSeed: u32,
+	pad: u32,
+}
+fn RandCounter_Reset(ct: ptr<function,RandCounter>) {
+	(*ct).Counter.x = u32(0);
+	(*ct).Counter.y = (*ct).HiSeed;
+}
+fn RandCounter_Seed(ct: ptr<function,RandCounter>, seed: u32) {
+	(*ct).HiSeed = seed;
+	RandCounter_Reset(ct);
+}
+fn RandCounter_Add(ct: ptr<function,RandCounter>, inc: u32) {
+	(*ct).Counter = Uint64Add32((*ct).Counter, inc);
+}
+
+//////// import: "sltype.wgsl"
+alias su64 = vec2<u32>;
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	let LOMASK = (((u32(1))<<16)-1);
+	var r: su64;
+	r.x = a * b;               /* full low multiply */
+	let ahi = a >> 16;
+	let alo = a & LOMASK;
+	let bhi = b >> 16;
+	let blo = b & LOMASK;
+	let ahbl = ahi * blo;
+	let albh = alo * bhi;
+	let ahbl_albh = ((ahbl&LOMASK) + (albh&LOMASK));
+	var hit = ahi*bhi + (ahbl>>16) +  (albh>>16);
+	hit += ahbl_albh >> 16; /* carry from the sum of lo(ahbl) + lo(albh) ) */
+	/* carry from the sum with alo*blo */
+	if ((r.x >> u32(16)) < (ahbl_albh&LOMASK)) {
+		hit += u32(1);
+	}
+	r.y = hit; 
+	return r;
+}
+/*
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	return su64(a) * su64(b);
+}
+*/
+fn Uint64Add32(a: su64, b: u32) -> su64 {
+	if (b == 0) {
+		return a;
+	}
+	var s = a;
+	if (s.x > u32(0xffffffff) - b) {
+		s.y++;
+		s.x = (b - 1) - (u32(0xffffffff) - s.x);
+	} else {
+		s.x += b;
+	}
+	return s;
+}
+fn Uint64Incr(a: su64) -> su64 {
+	var s = a;
+	if(s.x == 0xffffffff) {
+		s.y++;
+		s.x = u32(0);
+	} else {
+		s.x++;
+	}
+	return s;
+}
+fn Uint32ToFloat32(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(u32(0xffffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	var f = f32(val) * factor + halffactor;
+	if (f == 1.0) { // exclude 1
+		return bitcast<f32>(0x3F7FFFFF);
+	}
+	return f;
+}
+fn Uint32ToFloat32Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32(val.x);
+	r.y = Uint32ToFloat32(val.y);
+	return r;
+}
+fn Uint32ToFloat32Range11(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(i32(0x7fffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	return (f32(val) * factor + halffactor);
+}
+fn Uint32ToFloat32Range11Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32Range11(val.x);
+	r.y = Uint32ToFloat32Range11(val.y);
+	return r;
+}
\ No newline at end of file
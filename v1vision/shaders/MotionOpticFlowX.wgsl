@@ -0,0 +1,479 @@
+// Code generated by "gosl"; DO NOT EDIT
+// kernel: MotionOpticFlowX
+
+// // CurOp is the current operation to perform. 
+@group(0) @binding(0)
+var<storage, read> TensorStrides: array<u32>;
+@group(0) @binding(1)
+var<storage, read> CurOp: array<Op>;
+// // Filters are one general stack of rendered filters, sized to the max of each // of the inner dimensional values: [FilterTypes][FilterN][Y][X] // FilterTypes = different filter types (DoG, Gabor, etc) // FilterN = number of filters within the group (On, Off, angle, etc) // Y, X = sizes. 
+// // Images are float-valued image data: // [ImageNo][NData][RGB][Y][X], // sized to the max of each inner-dimensional value (RGB=3, // if more needed, use additional ImageNo) 
+@group(2) @binding(2)
+var<storage, read_write> Values: array<f32>;
+
+alias GPUVars = i32;
+
+@compute @workgroup_size(64, 1, 1)
+fn main(@builtin(workgroup_id) wgid: vec3<u32>, @builtin(num_workgroups) nwg: vec3<u32>, @builtin(local_invocation_index) loci: u32) {
+	let idx = loci + (wgid.x + wgid.y * nwg.x + wgid.z * nwg.x * nwg.y) * 64;
+	MotionOpticFlowX(idx);
+}
+
+fn Index6D(s0: u32, s1: u32, s2: u32, s3: u32, s4: u32, s5: u32, i0: u32, i1: u32, i2: u32, i3: u32, i4: u32, i5: u32) -> u32 {
+	return s0 * i0 + s1 * i1 + s2 * i2 + s3 * i3 + s4 * i4 + s5 * i5;
+}
+
+
+//////// import: "vars.go"
+
+//////// import: "colorspace-lms.go"
+/*
+func LMSToXYZ_CAT02(l, m, s f32) (x, y, z f32) {
+    x = 1.096124 * l + 0.4296f * Y + -0.1624f * Z;
+    y = -0.7036f * X + 1.6975f * Y + 0.0061f * Z;
+    z = 0.0030f * X + 0.0136f * Y + 0.9834 * Z;
+  }
+*/
+/*
+  func LMStoXYZ_HPE(float& X, float& Y, float& Z,
+                                    L, M, S) {
+    X = 1.096124f * L + 0.4296f * Y + -0.1624f * Z;
+    Y = -0.7036f * X + 1.6975f * Y + 0.0061f * Z;
+    Z = 0.0030f * X + 0.0136f * Y + 0.9834 * Z;
+  }
+*/
+
+//////// import: "colorspace-srgb.go"
+
+//////// import: "complex.go"
+
+//////// import: "convolve.go"
+
+//////// import: "deconv.go"
+
+//////// import: "enumgen.go"
+const GPUVarsN: GPUVars = 9;
+const InhibVarsN: InhibVars = 10;
+const OperationsN: Operations = 38;
+const TemporalKernelsN: TemporalKernels = 2;
+const RawFormatsN: RawFormats = 3;
+
+//////// import: "fffb-fffb.go"
+struct FFFB {
+	On: i32,
+	Gi: f32,
+	FF: f32,
+	FB: f32,
+	FBTau: f32,
+	MaxVsAvg: f32,
+	FF0: f32,
+	FBDt: f32,
+}
+
+//////// import: "geom.go"
+struct Geom {
+	In: vec4<i32>,
+	Out: vec4<i32>,
+	Border: vec4<i32>,
+	Spacing: vec4<i32>,
+	FilterSize: vec4<i32>,
+	FilterLt: vec4<i32>,
+	FilterRt: vec4<i32>,
+}
+
+//////// import: "image.go"
+
+//////// import: "inhib.go"
+alias InhibVars = i32; //enums:enum
+const  FFi: InhibVars = 0;
+const  FBi: InhibVars = 1;
+const  Gi: InhibVars = 2;
+const  GiOrig: InhibVars = 3;
+const  LayGi: InhibVars = 4;
+const  GeAvg: InhibVars = 5;
+const  GeMax: InhibVars = 6;
+const  ActAvg: InhibVars = 7;
+const  ActMax: InhibVars = 8;
+const  DelActMax: InhibVars = 9;
+
+//////// import: "kwta-chans.go"
+struct Chans {
+	E: f32,
+	L: f32,
+	I: f32,
+	K: f32,
+}
+
+//////// import: "kwta-kwta.go"
+struct KWTA {
+	On: i32,
+	Iters: i32,
+	DelActThr: f32,
+	ActTau: f32,
+	Layer: FFFB,
+	Pool: FFFB,
+	XX1: Params,
+	Gbar: Chans,
+	Erev: Chans,
+	ErevSubThr: Chans,
+	ThrSubErev: Chans,
+	ActDt: f32,
+	EarlyStop: i32,
+	pad1: f32,
+	pad2: f32,
+}
+
+//////// import: "kwta.go"
+
+//////// import: "logrenorm.go"
+
+//////// import: "math32-fastexp.go"
+
+//////// import: "maxpool.go"
+
+//////// import: "motion.go"
+fn MotionOpticFlowX(i: u32) { //gosl:kernel
+	let op = CurOp[0];
+	if (i >= op.RunN*op.NData) {
+		return;
+	}
+	var yo = i32(i % op.RunN);
+	var ni = i32(i / op.RunN);
+	var szX = op.Geom.Out.x - 1;
+	var szY = op.Geom.Out.y - 1;
+	var fno = op.FilterN; // original features
+	var fx = op.FloatArg1 * f32(szX-1);
+	var fy = op.FloatArg2 * f32(szY-1);
+	var ry = f32(yo) - fy;
+	var expand = f32(0);
+	var contract = f32(0);
+	var cw = f32(0);
+	var ccw = f32(0);
+	for (var xo=0; xo<szX; xo++) {
+		var rx = f32(xo) - fx;
+		var rd = sqrt(rx*rx + ry*ry);
+		if (rd < 0.5) { // no direction at focus
+			continue;
+		}
+		rx /= rd;
+		var rdy = ry / rd;
+		var dx = f32(0);
+		var dy = f32(0);
+		for (var pi=0; pi<2; pi++) { // pos / neg
+			for (var fi=0; fi<fno; fi++) { // original features
+				var dfo = fi * 4;
+				dx += Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo + 1))] - Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo))];
+				dy += Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo + 3))] - Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35],
+				u32(op.InValue), u32(ni), u32(yo), u32(xo), u32(pi), u32(dfo + 2))];
+			}
+		}
+		var rad = dx*rx + dy*rdy;
+		var tan = dy*rx - dx*rdy; // tangential: + = counterclockwise
+		if (rad >= 0) {
+			expand += rad;
+		} else {
+			contract += -rad;
+		}
+		if (tan >= 0) {
+			ccw += tan;
+		} else {
+			cw += -tan;
+		}
+	}
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(0), u32(0), u32(0))] = expand;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(0), u32(0), u32(1))] = contract;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33], TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(0), u32(0), u32(2))] = cw;
+	Values[Index6D(TensorStrides[30], TensorStrides[31], TensorStrides[32], TensorStrides[33],
+	TensorStrides[34], TensorStrides[35], u32(op.OutValue), u32(ni), u32(yo), u32(0), u32(0), u32(3))] = ccw;
+}
+
+//////// import: "nxx1-nxx1.go"
+struct Params {
+	Thr: f32,
+	Gain: f32,
+	NVar: f32,
+	VmActThr: f32,
+	SigMult: f32,
+	SigMultPow: f32,
+	SigGain: f32,
+	InterpRange: f32,
+	GainCorRange: f32,
+	GainCor: f32,
+	SigGainNVar: f32,
+	SigMultEff: f32,
+	SigValAt0: f32,
+	InterpVal: f32,
+	pad: f32,
+	pad1: f32,
+}
+
+//////// import: "op.go"
+alias Operations = i32; //enums:enum
+const  NoOp: Operations = 0;
+const  WrapPad: Operations = 1;
+const  EdgeAvg: Operations = 2;
+const  FadePad: Operations = 3;
+const  LMSOpponents: Operations = 4;
+const  LMSComponents: Operations = 5;
+const  ConvolveImage: Operations = 6;
+const  ConvolveDiff: Operations = 7;
+const  LogValues: Operations = 8;
+const  MaxScalar: Operations = 9;
+const  SumScalar: Operations = 10;
+const  MeanScalar: Operations = 11;
+const  NormDiv: Operations = 12;
+const  NeighInhib4: Operations = 13;
+const  KWTAInhib: Operations = 14;
+const  KWTAInhib4D: Operations = 15;
+const  MaxPool: Operations = 16;
+const  MaxPolarity: Operations = 17;
+const  MaxCopy: Operations = 18;
+const  LenSum4: Operations = 19;
+const  EndStop4: Operations = 20;
+const  To4D: Operations = 21;
+const  MotionIntegrate: Operations = 22;
+const  MotionStar: Operations = 23;
+const  MotionFullField: Operations = 24;
+const  MotionFlow: Operations = 25;
+const  MotionOpticFlow: Operations = 26;
+const  MotionGrid: Operations = 27;
+const  TemporalFilter: Operations = 28;
+const  ResetValues: Operations = 29;
+const  ResetValues4D: Operations = 30;
+const  BinocularEnergy: Operations = 31;
+const  PoissonSpikes: Operations = 32;
+const  TopKPool: Operations = 33;
+const  TopKLayer: Operations = 34;
+const  UnPool: Operations = 35;
+const  DeconvImage: Operations = 36;
+const  UnpackImage: Operations = 37;
+struct Op {
+	Op: Operations,
+	NData: u32,
+	RunN: u32,
+	InImage: i32,
+	InImageRGB: i32,
+	InValue: i32,
+	InValue2: i32,
+	OutValue: i32,
+	OutValue4D: i32,
+	OutImage: i32,
+	OutImage2: i32,
+	FilterType: i32,
+	FilterN: i32,
+	FloatArg1: f32,
+	FloatArg2: f32,
+	FloatArg3: f32,
+	IntArg1: i32,
+	InScalar: i32,
+	OutScalar: i32,
+	Inhibs: i32,
+	KWTA: i32,
+	InImage2: i32,
+	IntArg2: i32,
+	IntArg3: i32,
+	Geom: Geom,
+}
+
+//////// import: "scalar.go"
+
+//////// import: "slmath-math.go"
+const Pi = 3.141592653589793;
+
+//////// import: "slmath-matrix3.go"
+
+//////// import: "slmath-quaternion.go"
+
+//////// import: "slmath-vector2.go"
+
+//////// import: "slmath-vector3.go"
+
+//////// import: "spikes.go"
+
+//////// import: "state.go"
+
+//////// import: "stereo.go"
+
+//////// import: "temporal.go"
+alias TemporalKernels = i32; //enums:enum
+const  Exponential: TemporalKernels = 0;
+const  Biphasic: TemporalKernels = 1;
+
+//////// import: "to4d.go"
+
+//////// import: "topk.go"
+
+//////// import: "unpool.go"
+
+//////// import: "upload.go"
+alias RawFormats = i32; //enums:enum
+const  RawRGBA: RawFormats = 0;
+const  RawNRGBA: RawFormats = 1;
+const  RawGray: RawFormats = 2;
+
+//////// import: "slrand.wgsl"
+fn Philox2x32round(counter: su64, key: u32) -> su64 {
+	let mul = Uint32Mul64(u32(0xD256D193), counter.x);
+	var ctr: su64;
+	ctr.x = mul.y ^ key ^ counter.y;
+	ctr.y = mul.x;
+	return ctr;
+}
+fn Philox2x32bumpkey(key: u32) -> u32 {
+	return key + u32(0x9E3779B9);
+}
+fn Philox2x32(counter: su64, key: u32) -> vec2<u32> {
+	var ctr = Philox2x32round(counter, key); // 1
+	var ky = Philox2x32bumpkey(key);
+	ctr = Philox2x32round(ctr, ky); // 2
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 3
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 4
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 5
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 6
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 7
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 8
+	ky = Philox2x32bumpkey(ky);
+	ctr = Philox2x32round(ctr, ky); // 9
+	ky = Philox2x32bumpkey(ky);
+	return Philox2x32round(ctr, ky); // 10
+}
+fn RandUint32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<u32> {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key);
+}
+fn RandUint32(counter: su64, funcIndex: u32, key: u32) -> u32 {
+	return Philox2x32(Uint64Add32(counter, funcIndex), key).x;
+}
+fn RandFloat32Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32(RandUint32(counter, funcIndex, key));
+}
+fn RandFloat32Range11Vec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> {
+	return Uint32ToFloat32Vec2(RandUint32Vec2(counter, funcIndex, key));
+}
+fn RandFloat32Range11(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return Uint32ToFloat32Range11(RandUint32(counter, funcIndex, key));
+}
+fn RandBoolP(counter: su64, funcIndex: u32, key: u32, p: f32) -> bool { 
+	return (RandFloat32(counter, funcIndex, key) < p);
+}
+fn sincospi(x: f32) -> vec2<f32> {
+	let PIf = 3.1415926535897932;
+	var r: vec2<f32>;
+	r.x = cos(PIf*x);
+	r.y = sin(PIf*x);
+	return r;
+}
+fn RandFloat32NormVec2(counter: su64, funcIndex: u32, key: u32) -> vec2<f32> { 
+	let ur = RandUint32Vec2(counter, funcIndex, key);
+	var f = sincospi(Uint32ToFloat32Range11(ur.x));
+	let r = sqrt(-2.0 * log(Uint32ToFloat32(ur.y))); // guaranteed to avoid 0.
+	return f * r;
+}
+fn RandFloat32Norm(counter: su64, funcIndex: u32, key: u32) -> f32 { 
+	return RandFloat32Vec2(counter, funcIndex, key).x;
+}
+fn RandUint32N(counter: su64, funcIndex: u32, key: u32, n: u32) -> u32 { 
+	let v = RandFloat32(counter, funcIndex, key);
+	return u32(v * f32(n));
+}
+struct RandCounter {
+	Counter: su64,
+	HiSeed: u32,
+	pad: u32,
+}
+fn RandCounter_Reset(ct: ptr<function,RandCounter>) {
+	(*ct).Counter.x = u32(0);
+	(*ct).Counter.y = (*ct).HiSeed;
+}
+fn RandCounter_Seed(ct: ptr<function,RandCounter>, seed: u32) {
+	(*ct).HiSeed = seed;
+	RandCounter_Reset(ct);
+}
+fn RandCounter_Add(ct: ptr<function,RandCounter>, inc: u32) {
+	(*ct).Counter = Uint64Add32((*ct).Counter, inc);
+}
+
+//////// import: "sltype.wgsl"
+alias su64 = vec2<u32>;
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	let LOMASK = (((u32(1))<<16)-1);
+	var r: su64;
+	r.x = a * b;               /* full low multiply */
+	let ahi = a >> 16;
+	let alo = a & LOMASK;
+	let bhi = b >> 16;
+	let blo = b & LOMASK;
+	let ahbl = ahi * blo;
+	let albh = alo * bhi;
+	let ahbl_albh = ((ahbl&LOMASK) + (albh&LOMASK));
+	var hit = ahi*bhi + (ahbl>>16) +  (albh>>16);
+	hit += ahbl_albh >> 16; /* carry from the sum of lo(ahbl) + lo(albh) ) */
+	/* carry from the sum with alo*blo */
+	if ((r.x >> u32(16)) < (ahbl_albh&LOMASK)) {
+		hit += u32(1);
+	}
+	r.y = hit; 
+	return r;
+}
+/*
+fn Uint32Mul64(a: u32, b: u32) -> su64 {
+	return su64(a) * su64(b);
+}
+*/
+fn Uint64Add32(a: su64, b: u32) -> su64 {
+	if (b == 0) {
+		return a;
+	}
+	var s = a;
+	if (s.x > u32(0xffffffff) - b) {
+		s.y++;
+		s.x = (b - 1) - (u32(0xffffffff) - s.x);
+	} else {
+		s.x += b;
+	}
+	return s;
+}
+fn Uint64Incr(a: su64) -> su64 {
+	var s = a;
+	if(s.x == 0xffffffff) {
+		s.y++;
+		s.x = u32(0);
+	} else {
+		s.x++;
+	}
+	return s;
+}
+fn Uint32ToFloat32(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(u32(0xffffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	var f = f32(val) * factor + halffactor;
+	if (f == 1.0) { // exclude 1
+		return bitcast<f32>(0x3F7FFFFF);
+	}
+	return f;
+}
+fn Uint32ToFloat32Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32(val.x);
+	r.y = Uint32ToFloat32(val.y);
+	return r;
+}
+fn Uint32ToFloat32Range11(val: u32) -> f32 {
+	let factor = f32(1.0) / (f32(i32(0x7fffffff)) + f32(1.0));
+	let halffactor = f32(0.5) * factor;
+	return (f32(val) * factor + halffactor);
+}
+fn Uint32ToFloat32Range11Vec2(val: vec2<u32>) -> vec2<f32> {
+	var r: vec2<f32>;
+	r.x = Uint32ToFloat32Range11(val.x);
+	r.y = Uint32ToFloat32Range11(val.y);
+	return r;
+}
\ No newline at end of file
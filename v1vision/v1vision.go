@@ -30,6 +30,11 @@ type V1Vision struct {
 	// KWTAs are KWTA inhibition parameters that can be used.
 	KWTAs []kwta.KWTA
 
+	// KWTAIters has the number of iterations used by each [KWTAInhib]
+	// or [KWTAInhib4D] operation on the last Run, in the order of the Ops.
+	// This is less than [kwta.KWTA.Iters] when EarlyStop stopped it.
+	KWTAIters []int
+
 	// Filters are one general stack of rendered filters, sized to the max of each
 	// of the inner dimensional values: [FilterTypes][FilterN][Y][X]
 	// FilterTypes = different filter types (DoG, Gabor, etc)
@@ -42,6 +47,10 @@ type V1Vision struct {
 	// if more needed, use additional ImageNo)
 	Images *tensor.Float32
 
+	// RawImages is the staging buffer for uploading raw image pixel data,
+	// packed as one uint32 per pixel: [NData][Y][X]. See [V1Vision.UploadImages].
+	RawImages *tensor.Uint32
+
 	// Values are intermediate input / output data:
 	// [ValueNo][NData][Y][X][Polarity][FilterN]
 	// where FilterN corresponds to the different filters applied or other such data,
@@ -60,6 +69,14 @@ type V1Vision struct {
 	// Inhibs are [KWTAInhib] inhibitory state values:
 	// [InhibNo][NData][PoolY][PoolX][InhibVarsN]
 	Inhibs *tensor.Float32
+
+	// maskImage is the index of the valid-region mask image used by
+	// subsequent ConvolveImage and ConvolveDiff ops, if >= 0.
+	maskImage int
+
+	// imagesOnGPU is set when Images have been uploaded directly on the GPU
+	// by UploadImages, so they are not copied from the CPU in the next Run.
+	imagesOnGPU bool
 }
 
 // Init makes initial versions of all variables.
@@ -68,8 +85,11 @@ func (vv *V1Vision) Init(ndata int) {
 	vv.NData = max(1, ndata)
 	vv.Ops = []Op{}
 	vv.CurOp = make([]Op, 1)
+	vv.KWTAs = []kwta.KWTA{}
 	vv.Filters = tensor.NewFloat32(0, 1, 1, 1)
 	vv.Images = tensor.NewFloat32(0, vv.NData, 3, 1, 1)
+	vv.RawImages = tensor.NewUint32(vv.NData, 1, 1)
+	vv.maskImage = -1
 	vv.Values = tensor.NewFloat32(0, vv.NData, 1, 1, 2, 1)
 	vv.Values4D = tensor.NewFloat32(0, vv.NData, 1, 1, 1, 1)
 	vv.Scalars = tensor.NewFloat32(0, vv.NData)
@@ -100,6 +120,32 @@ func (vv *V1Vision) NewImage(size math32.Vector2i) int {
 	return n
 }
 
+// NewMaskImage adds a new image for a valid-region mask of the input
+// images, with 1 for valid and 0 for invalid pixels (e.g., letterbox
+// padding) in the first component. Returns the image index, which is
+// passed to [V1Vision.SetMaskImage] to use it. The mask image must be
+// set per the image padding, e.g., with [v1std.Image.SetMask].
+func (vv *V1Vision) NewMaskImage(size math32.Vector2i) int {
+	return vv.NewImage(size)
+}
+
+// SetMaskImage sets the valid-region mask image at given index (from
+// [V1Vision.NewMaskImage]) to be used by the [ConvolveImage] and
+// [ConvolveDiff] operations added after this call, until
+// [V1Vision.ClearMaskImage] is called: invalid pixels are replaced by
+// the mean of the valid pixels under the filter, so that they do not
+// generate spurious edge responses, and outputs centered on invalid
+// pixels are 0.
+func (vv *V1Vision) SetMaskImage(idx int) {
+	vv.maskImage = idx
+}
+
+// ClearMaskImage stops using the mask image set by
+// [V1Vision.SetMaskImage] for subsequently added operations.
+func (vv *V1Vision) ClearMaskImage() {
+	vv.maskImage = -1
+}
+
 // NewValues adds a new Values of given sizes. returns value index.
 func (vv *V1Vision) NewValues(y, x, filtN int) int {
 	sizes := vv.Values.ShapeSizes()
@@ -154,6 +200,7 @@ func (vv *V1Vision) SetAsCurrent() {
 	KWTAs = vv.KWTAs
 	Filters = vv.Filters
 	Images = vv.Images
+	RawImages = vv.RawImages
 	Values = vv.Values
 	Values4D = vv.Values4D
 	Scalars = vv.Scalars
@@ -201,14 +248,21 @@ func ImagesToGPU() {
 
 // Run transfers Images to GPU, does RunOps, retrieving the
 // specified set of variables back from the GPU (if GPU running).
+// Images are not transferred if they were just uploaded directly
+// on the GPU by [V1Vision.UploadImages].
 func (vv *V1Vision) Run(vars ...GPUVars) {
-	ImagesToGPU()
+	if !vv.imagesOnGPU {
+		ImagesToGPU()
+	}
+	vv.imagesOnGPU = false
 	vv.RunOps()
 	RunDone(vars...)
 }
 
 // ZeroValues sets all the values to zero.
 // Useful when there are integrated accumulating values (e.g., motion).
+// See [V1Vision.ResetItem] for resetting only the stateful values
+// for individual data-parallel items.
 func (vv *V1Vision) ZeroValues() {
 	tensor.SetAllFloat64(vv.Values, 0)
 	ToGPU(ValuesVar)
@@ -50,6 +50,13 @@ var (
 	//gosl:dims 5
 	Images *tensor.Float32
 
+	// RawImages is the staging buffer for uploading raw image pixel data,
+	// packed as one uint32 per pixel, which is converted into Images
+	// by [UnpackImage]: [NData][Y][X].
+	//gosl:read-only
+	//gosl:dims 3
+	RawImages *tensor.Uint32
+
 	// Values are intermediate input / output data:
 	// [ValueNo][NData][Y][X][Polarity][FilterN]
 	// where FilterN corresponds to the different filters applied or other such data,